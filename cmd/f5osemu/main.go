@@ -0,0 +1,95 @@
+// Command f5osemu serves an emulated F5OS device for local demos and
+// manual testing of the provider. See internal/f5osemu.
+//
+//	go run ./cmd/f5osemu -platform velos-partition -version 2.0.0-3012
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strings"
+
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+// fixtureFlags collects repeated -fixture path=file flags.
+type fixtureFlags []string
+
+func (f *fixtureFlags) String() string { return strings.Join(*f, ",") }
+
+func (f *fixtureFlags) Set(v string) error {
+	if strings.LastIndex(v, "=") <= 0 {
+		return fmt.Errorf("expected path=file, got %q", v)
+	}
+	*f = append(*f, v)
+	return nil
+}
+
+func main() {
+	var (
+		opts     f5osemu.Options
+		platform string
+		addr     string
+		useTLS   bool
+		fixtures fixtureFlags
+	)
+	flag.StringVar(&addr, "addr", "localhost:8888", "address to listen on")
+	flag.StringVar(&platform, "platform", string(f5osemu.RSeries), "platform to emulate: rseries, velos-controller or velos-partition")
+	flag.StringVar(&opts.Version, "version", "", "F5OS version to report (default a 1.8 release)")
+	flag.StringVar(&opts.Username, "username", "admin", "username accepted at login")
+	flag.StringVar(&opts.Password, "password", "admin", "password accepted at login")
+	flag.IntVar(&opts.AsyncPolls, "polls", 0, "status reads before an asynchronous operation completes (0 for the default, -1 for immediately)")
+	flag.BoolVar(&useTLS, "tls", true, "serve HTTPS with a self-signed certificate, as a device does")
+	flag.Var(&fixtures, "fixture", "seed data as `path=file`, where file holds a RESTCONF JSON document for path; repeatable")
+	flag.Parse()
+	opts.Platform = f5osemu.Platform(platform)
+
+	emu, err := f5osemu.New(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range fixtures {
+		i := strings.LastIndex(f, "=")
+		body, err := os.ReadFile(f[i+1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := emu.Load(f[:i], body); err != nil {
+			log.Fatalf("loading %s: %v", f, err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(emu)
+	server.Listener.Close()
+	server.Listener = listener
+	if useTLS {
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	defer server.Close()
+
+	fmt.Printf("Emulating F5OS %s %s at %s\n\n", emu.Platform(), emu.Version(), server.URL)
+	if useTLS {
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		fmt.Printf("provider \"f5os\" {\n  host               = %q\n  port               = %s\n  username           = %q\n  password           = %q\n  disable_tls_verify = true\n}\n\n",
+			host, port, opts.Username, opts.Password)
+	} else {
+		fmt.Printf("provider \"f5os\" {\n  host     = %q\n  username = %q\n  password = %q\n}\n\n",
+			server.URL, opts.Username, opts.Password)
+	}
+	fmt.Println("Set F5OS_POLL_INTERVAL=1s to skip the client's long waits between status polls.")
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	<-stop
+}
@@ -0,0 +1,11 @@
+data "f5os_restconf" "alarms" {
+  path    = "/openconfig-system:system/f5-alarms:alarms"
+  content = "nonconfig"
+  selectors = {
+    critical = "$.alarms.alarm[?(@.state.severity == 'critical')].state.description"
+  }
+}
+
+output "critical_alarms" {
+  value = lookup(data.f5os_restconf.alarms.values, "critical", "none")
+}
@@ -0,0 +1,5 @@
+data "f5os_tenant_capacity" "current" {}
+
+output "free_vcpus" {
+  value = { for node in data.f5os_tenant_capacity.current.nodes : node.node => node.free_vcpus }
+}
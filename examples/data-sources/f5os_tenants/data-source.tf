@@ -0,0 +1,10 @@
+data "f5os_tenants" "web" {
+  name_regex = "^web-"
+}
+
+check "web_tenants_running" {
+  assert {
+    condition     = alltrue([for tenant in data.f5os_tenants.web.tenants : tenant.status == "Running"])
+    error_message = "Not every web tenant is running."
+  }
+}
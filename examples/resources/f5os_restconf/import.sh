@@ -0,0 +1,2 @@
+# A RESTCONF object can be imported by specifying its path.
+terraform import f5os_restconf.vlan /openconfig-vlan:vlans/vlan=210
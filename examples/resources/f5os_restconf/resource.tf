@@ -0,0 +1,22 @@
+# Manages a RESTCONF object that has no dedicated resource
+resource "f5os_restconf" "snmp_location" {
+  path = "/openconfig-system:system/f5-system-snmp:snmp/config"
+  payload = jsonencode({
+    "f5-system-snmp:config" = {
+      location = "Rack 12, DC1"
+    }
+  })
+  delete_method = "NONE"
+}
+
+# Creates a VLAN with POST, failing if it already exists
+resource "f5os_restconf" "vlan" {
+  path          = "/openconfig-vlan:vlans/vlan=210"
+  create_method = "POST"
+  payload = jsonencode({
+    "openconfig-vlan:vlan" = [{
+      vlan-id = 210
+      config  = { vlan-id = 210, name = "app-vlan" }
+    }]
+  })
+}
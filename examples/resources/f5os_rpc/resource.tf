@@ -0,0 +1,31 @@
+# Downloads a tenant image and waits for the transfer to finish.
+# Changing the version re-runs the download.
+resource "f5os_rpc" "image_download" {
+  path = "/f5-utils-file-transfer:file/import"
+  input = jsonencode({
+    "f5-utils-file-transfer:remote-host" = "files.example.net"
+    "f5-utils-file-transfer:remote-file" = "/images/BIGIP-17.1.0-0.0.16.ALL-F5OS.qcow2.zip.bundle"
+    "f5-utils-file-transfer:local-file"  = "images/tenant"
+    "f5-utils-file-transfer:protocol"    = "https"
+  })
+  triggers = {
+    version = "17.1.0-0.0.16"
+  }
+  status_path       = "/f5-utils-file-transfer:file/transfer-operations"
+  status_condition  = "$..transfer-operation[?(@.status == 'Completed')]"
+  failure_condition = "$..transfer-operation[?(@.status == 'Failed')]"
+  poll_interval     = "15s"
+  timeout           = 1800
+}
+
+# Reads the EULA for a registration key
+resource "f5os_rpc" "eula" {
+  path = "/openconfig-system:system/f5-system-licensing:licensing/f5-system-licensing-install:get-eula"
+  input = jsonencode({
+    "f5-system-licensing-install:registration-key" = var.registration_key
+  })
+}
+
+output "eula" {
+  value = jsondecode(f5os_rpc.eula.output)
+}
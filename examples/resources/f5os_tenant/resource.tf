@@ -15,4 +15,12 @@ resource "f5os_tenant" "test3" {
   # max_nodes is only supported on F5OS 2.0.0 and later; ignored on earlier
   # versions.
   max_nodes = 2
+
+  # Finish only once the BIG-IP REST API on mgmt_ip answers. It returns 401
+  # to requests without credentials once it is up.
+  wait_for_ready = {
+    url             = "/mgmt/tm/sys/ready"
+    expected_status = 401
+    timeout         = "15m"
+  }
 }
\ No newline at end of file
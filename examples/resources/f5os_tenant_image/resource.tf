@@ -6,7 +6,6 @@ resource "f5os_tenant_image" "test" {
   local_path  = "images" ## for velos partition/rSeries appliance this path should be `images`/`images/tenant` respectively
   protocol    = "https"  ## supported values: scp, sftp, https
   insecure    = true     ## skip TLS certificate verification on the remote host
-  timeout     = 360
 }
 
 # Import a tenant image via SCP with credentials
@@ -19,5 +18,8 @@ resource "f5os_tenant_image" "scp_example" {
   remote_user     = "imageuser"
   remote_password = "imagepass"
   remote_port     = 22
-  timeout         = 600
+
+  timeouts {
+    create = "10m"
+  }
 }
\ No newline at end of file
@@ -0,0 +1,252 @@
+package f5osemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// actionFunc runs a YANG action or RPC invoked with POST at segs.
+type actionFunc func(e *Emulator, w http.ResponseWriter, r *http.Request, segs []segment) error
+
+// actions maps the local node names of the implemented action paths, list
+// keys aside, to their handlers.
+var actions = map[string]actionFunc{
+	"file/import":                      (*Emulator).importImage,
+	"file/upload/start-upload":         (*Emulator).startUpload,
+	"system/image/upload-image":        (*Emulator).uploadImage,
+	"images/remove":                    (*Emulator).removeImage,
+	"partitions/partition/set-version": (*Emulator).setPartitionVersion,
+	"system/aaa/logout":                (*Emulator).logout,
+}
+
+// actionFor returns the handler for a POST to segs, or nil when the POST
+// creates data.
+func actionFor(segs []segment) actionFunc {
+	names := make([]string, len(segs))
+	for i, s := range segs {
+		names[i] = localName(s.name)
+	}
+	return actions[strings.Join(names, "/")]
+}
+
+// actionInput decodes an action's input, which the client sends either
+// bare or wrapped in an "input" member.
+func (e *Emulator) actionInput(r *http.Request) (map[string]interface{}, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	var in map[string]interface{}
+	if err := json.Unmarshal(body, &in); err != nil {
+		return nil, errBadRequest("invalid JSON body: %v", err)
+	}
+	if len(in) == 1 {
+		for k, v := range in {
+			if inner, ok := v.(map[string]interface{}); ok && localName(k) == "input" {
+				in = inner
+			}
+		}
+	}
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[localName(k)] = v
+	}
+	return out, nil
+}
+
+func writeOutput(w http.ResponseWriter, module string, output map[string]interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{module + ":output": output})
+}
+
+// container returns the container at the local path under a top-level
+// node, creating it as needed.
+func (e *Emulator) container(top string, names ...string) map[string]interface{} {
+	cur, ok := e.data[top].(map[string]interface{})
+	if !ok {
+		cur = map[string]interface{}{}
+		e.data[top] = cur
+	}
+	for _, n := range names {
+		next, ok := cur[n].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			cur[n] = next
+		}
+		cur = next
+	}
+	return cur
+}
+
+// importImage starts a tenant image import. The transfer completes, and
+// the image appears, after the transfer status has been polled; the image
+// is then verified (replicated to the blades on VELOS) after it has been
+// polled in turn.
+func (e *Emulator) importImage(w http.ResponseWriter, r *http.Request, _ []segment) error {
+	in, err := e.actionInput(r)
+	if err != nil {
+		return err
+	}
+	remote := scalarString(in["remote-file"])
+	if remote == "" || scalarString(in["remote-host"]) == "" {
+		return errBadRequest("remote-host and remote-file are required")
+	}
+	name := path.Base(remote)
+	if e.supports("f5-tenant-images") {
+		for _, img := range e.entries("f5-tenant-images:images", "image") {
+			if scalarString(img["name"]) == name {
+				writeOutput(w, "f5-utils-file-transfer", map[string]interface{}{"result": "Aborted: local-file already exists"})
+				return nil
+			}
+		}
+	}
+
+	e.serial++
+	id := fmt.Sprintf("IMPORT-%d", e.serial)
+	op := map[string]interface{}{
+		"operation-id":     id,
+		"local-file-path":  strings.TrimSuffix(scalarString(in["local-file"]), "/") + "/" + name,
+		"remote-host":      in["remote-host"],
+		"remote-file-path": remote,
+		"operation":        "Import file",
+		"protocol":         in["protocol"],
+		"status":           "In Progress (10.0%)",
+		"timestamp":        time.Now().UTC().Format("Mon Jan 02 15:04:05 2006"),
+	}
+	ops := e.container("f5-utils-file-transfer:file", "transfer-operations")
+	list, _ := ops["transfer-operation"].([]interface{})
+	ops["transfer-operation"] = append(list, op)
+
+	e.schedule([]string{"file", "transfer-operations"}, func() {
+		op["status"] = "Completed"
+		if e.supports("f5-tenant-images") {
+			e.addImage(name)
+		}
+	})
+	writeOutput(w, "f5-utils-file-transfer", map[string]interface{}{
+		"result":       "File transfer is initiated.(" + remote + ")",
+		"operation-id": id,
+	})
+	return nil
+}
+
+// addImage adds a tenant image that still has to be verified.
+func (e *Emulator) addImage(name string) {
+	pending, done := "verifying", "verified"
+	if e.opts.Platform == VelosPartition {
+		pending, done = "replicating", "replicated"
+	}
+	img := map[string]interface{}{
+		"name":   name,
+		"in-use": false,
+		"type":   "vm-image",
+		"status": pending,
+		"date":   time.Now().UTC().Format("2006-01-02"),
+		"size":   "2.16 GB",
+	}
+	images := e.container("f5-tenant-images:images")
+	list, _ := images["image"].([]interface{})
+	images["image"] = append(list, img)
+
+	e.schedule([]string{"images", segment{name: "image", keys: []string{name}, keyed: true}.String()}, func() {
+		img["status"] = done
+	})
+}
+
+func (e *Emulator) startUpload(w http.ResponseWriter, r *http.Request, _ []segment) error {
+	in, err := e.actionInput(r)
+	if err != nil {
+		return err
+	}
+	name := scalarString(in["name"])
+	if name == "" {
+		return errBadRequest("name is required")
+	}
+	e.serial++
+	id := fmt.Sprintf("%d", 600000+e.serial)
+	e.uploads[id] = name
+	writeOutput(w, "f5-file-upload-meta-data", map[string]interface{}{"upload-id": id})
+	return nil
+}
+
+// uploadImage accepts the multipart upload of a tenant image started with
+// start-upload.
+func (e *Emulator) uploadImage(w http.ResponseWriter, r *http.Request, _ []segment) error {
+	name, ok := e.uploads[r.Header.Get("File-Upload-Id")]
+	if !ok {
+		return errBadRequest("unknown File-Upload-Id %q", r.Header.Get("File-Upload-Id"))
+	}
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		return errBadRequest("reading upload: %v", err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(io.Discard, file); err != nil {
+		return errBadRequest("reading upload: %v", err)
+	}
+	delete(e.uploads, r.Header.Get("File-Upload-Id"))
+	if e.supports("f5-tenant-images") {
+		e.addImage(name)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result-tag": "uploaded-successfully"})
+	return nil
+}
+
+func (e *Emulator) removeImage(w http.ResponseWriter, r *http.Request, _ []segment) error {
+	in, err := e.actionInput(r)
+	if err != nil {
+		return err
+	}
+	name := scalarString(in["name"])
+	images := e.container("f5-tenant-images:images")
+	list, _ := images["image"].([]interface{})
+	for i, item := range list {
+		img, _ := item.(map[string]interface{})
+		if scalarString(img["name"]) != name {
+			continue
+		}
+		if inUse, _ := img["in-use"].(bool); inUse {
+			writeOutput(w, "f5-tenant-images", map[string]interface{}{"result": "Failed: image " + name + " is in use by a tenant."})
+			return nil
+		}
+		images["image"] = append(list[:i:i], list[i+1:]...)
+		writeOutput(w, "f5-tenant-images", map[string]interface{}{"result": "Successful."})
+		return nil
+	}
+	writeOutput(w, "f5-tenant-images", map[string]interface{}{"result": "Failed: image " + name + " not found."})
+	return nil
+}
+
+// setPartitionVersion changes a partition's ISO version, which restarts
+// it.
+func (e *Emulator) setPartitionVersion(w http.ResponseWriter, r *http.Request, segs []segment) error {
+	in, err := e.actionInput(r)
+	if err != nil {
+		return err
+	}
+	if inner, ok := in["set-version"].(map[string]interface{}); ok {
+		in = inner
+	}
+	version := scalarString(in["iso-version"])
+	if version == "" {
+		return errBadRequest("iso-version is required")
+	}
+	p, _, err := descend(e.data, segs[:len(segs)-1], false)
+	if err != nil {
+		return err
+	}
+	config(p)["iso-version"] = version
+	e.reconcile()
+	writeOutput(w, "f5-system-partition", map[string]interface{}{"result": "Version update successful."})
+	return nil
+}
+
+func (e *Emulator) logout(w http.ResponseWriter, r *http.Request, _ []segment) error {
+	delete(e.tokens, r.Header.Get("X-Auth-Token"))
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
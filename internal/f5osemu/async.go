@@ -0,0 +1,600 @@
+package f5osemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// moduleGates lists the modules that exist only on some platforms.
+// Modules not listed exist everywhere.
+var moduleGates = map[string][]Platform{
+	"f5-tenants":                 {RSeries, VelosPartition},
+	"f5-tenant-images":           {RSeries, VelosPartition},
+	"f5-system-partition":        {VelosController},
+	"f5-system-slot":             {VelosController},
+	"f5-system-controller-image": {VelosController},
+	"f5-cluster":                 {VelosPartition},
+	"f5-tenant-vwire":            {RSeries},
+}
+
+// moduleSince lists the modules introduced after F5OS 1.x, with the first
+// version that has them.
+var moduleSince = map[string]string{
+	"f5-openconfig-aaa-login-policy": "2.0.0",
+	"f5-openconfig-aaa-ldap":         "2.0.0",
+}
+
+// yangModules are the modules the emulated device implements besides
+// those in its datastore, as long as supports allows them. They make up
+// the ietf-yang-library module list and the NETCONF hello.
+var yangModules = []string{
+	"f5-aaa-confd-restconf-token", "f5-alarms", "f5-cluster", "f5-database",
+	"f5-if-aggregate", "f5-if-ethernet", "f5-interface", "f5-lacp",
+	"f5-openconfig-aaa", "f5-openconfig-aaa-ldap", "f5-openconfig-aaa-login-policy",
+	"f5-openconfig-aaa-password-policy", "f5-openconfig-aaa-tls",
+	"f5-openconfig-system-logging", "f5-openconfig-system-ntp", "f5-platform",
+	"f5-primary-key", "f5-security-ciphers", "f5-security-fips-module",
+	"f5-system-aaa", "f5-system-controller-image", "f5-system-diagnostics-qkview",
+	"f5-system-image", "f5-system-licensing", "f5-system-licensing-install",
+	"f5-system-partition", "f5-system-settings", "f5-system-slot", "f5-system-snmp",
+	"f5-tenant-images", "f5-tenant-l2-inline", "f5-tenant-mgmt-vlan",
+	"f5-tenant-vwire", "f5-tenants", "f5-utils-file-transfer", "ietf-yang-library",
+	"openconfig-if-aggregate", "openconfig-if-ethernet", "openconfig-interfaces",
+	"openconfig-lacp", "openconfig-platform", "openconfig-system", "openconfig-vlan",
+}
+
+// leafSince lists leaves added to existing modules, with the first version
+// that accepts them.
+var leafSince = map[string]string{
+	"max-nodes":        "2.0.0",
+	"mac-ndi-set":      "1.7.0",
+	"ha-state":         "1.7.0",
+	"floating-address": "1.7.0",
+	"trust-mode":       "1.7.0",
+	"reserved-cpus":    "1.7.0",
+}
+
+// parseVersion parses the numeric part of an F5OS version such as
+// "1.8.0-17191".
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// atLeast reports whether the emulated version is min or later.
+func (e *Emulator) atLeast(min string) bool {
+	have, _ := parseVersion(e.opts.Version)
+	want, _ := parseVersion(min)
+	for i := range have {
+		if have[i] != want[i] {
+			return have[i] > want[i]
+		}
+	}
+	return true
+}
+
+// supports reports whether the emulated device has module.
+func (e *Emulator) supports(module string) bool {
+	if platforms, ok := moduleGates[module]; ok {
+		found := false
+		for _, p := range platforms {
+			found = found || p == e.opts.Platform
+		}
+		if !found {
+			return false
+		}
+	}
+	if since, ok := moduleSince[module]; ok && !e.atLeast(since) {
+		return false
+	}
+	return true
+}
+
+// implemented returns the sorted names of the modules the emulated device
+// implements. The caller must hold e.mu.
+func (e *Emulator) implemented() []string {
+	found := map[string]bool{}
+	modules(e.data, found)
+	for _, m := range yangModules {
+		if e.supports(m) {
+			found[m] = true
+		}
+	}
+	delete(found, "")
+	names := make([]string, 0, len(found))
+	for m := range found {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// yangLibrary returns the device's ietf-yang-library module list. The
+// caller must hold e.mu.
+func (e *Emulator) yangLibrary() map[string]interface{} {
+	names := e.implemented()
+	list := make([]interface{}, len(names))
+	for i, m := range names {
+		list[i] = map[string]interface{}{
+			"name":             m,
+			"revision":         "",
+			"namespace":        netconfModuleNS + m,
+			"conformance-type": "implement",
+		}
+	}
+	return map[string]interface{}{
+		"module-set-id": fmt.Sprintf("%s-%s", e.opts.Platform, e.opts.Version),
+		"module":        list,
+	}
+}
+
+// unknownLeaf returns the first leaf in v that the emulated version does
+// not accept.
+func (e *Emulator) unknownLeaf(v interface{}) string {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, child := range x {
+			if since, ok := leafSince[localName(k)]; ok && !e.atLeast(since) {
+				return k
+			}
+			if leaf := e.unknownLeaf(child); leaf != "" {
+				return leaf
+			}
+		}
+	case []interface{}:
+		for _, child := range x {
+			if leaf := e.unknownLeaf(child); leaf != "" {
+				return leaf
+			}
+		}
+	}
+	return ""
+}
+
+// numericLeaves are leaves the client sometimes sends as strings but the
+// device stores, and returns, as numbers.
+var numericLeaves = map[string]bool{"vlan-id": true, "slot-num": true}
+
+// normalize converts numericLeaves in v to numbers, as the device's YANG
+// types would.
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, child := range x {
+			if s, ok := child.(string); ok && numericLeaves[localName(k)] {
+				if n, err := strconv.Atoi(s); err == nil {
+					x[k] = float64(n)
+					continue
+				}
+			}
+			x[k] = normalize(child)
+		}
+	case []interface{}:
+		for i, child := range x {
+			x[i] = normalize(child)
+		}
+	}
+	return v
+}
+
+// job is an asynchronous operation in progress. Reads of its path advance
+// it; once polls reaches zero the next such read completes it first.
+type job struct {
+	path  []string
+	polls int
+	done  func()
+}
+
+// schedule starts an asynchronous operation whose status is read at
+// path, replacing any operation already running there.
+func (e *Emulator) schedule(path []string, done func()) {
+	polls := e.opts.AsyncPolls
+	if polls < 0 {
+		polls = 0
+	}
+	j := &job{path: path, polls: polls, done: done}
+	for i, old := range e.jobs {
+		if strings.Join(old.path, "/") == strings.Join(path, "/") {
+			e.jobs[i] = j
+			return
+		}
+	}
+	e.jobs = append(e.jobs, j)
+}
+
+// runDue completes the operations due at path, before it is read.
+func (e *Emulator) runDue(path []string) {
+	var pending []*job
+	var due []*job
+	for _, j := range e.jobs {
+		if j.polls <= 0 && overlaps(j.path, path) {
+			due = append(due, j)
+		} else {
+			pending = append(pending, j)
+		}
+	}
+	e.jobs = pending
+	for _, j := range due {
+		j.done()
+	}
+	if len(due) > 0 {
+		e.reconcile()
+	}
+}
+
+// tick advances the operations at path, after it has been read.
+func (e *Emulator) tick(path []string) {
+	for _, j := range e.jobs {
+		if overlaps(j.path, path) {
+			j.polls--
+		}
+	}
+}
+
+// markSynced records the current configuration as deployed, so reconcile
+// only reacts to later changes.
+func (e *Emulator) markSynced() {
+	e.synced = map[string]string{}
+	for _, t := range e.entries("f5-tenants:tenants", "tenant") {
+		e.synced["tenant="+scalarString(t["name"])] = snapshot(t["config"])
+	}
+	for _, p := range e.entries("f5-system-partition:partitions", "partition") {
+		e.synced["partition="+scalarString(p["name"])] = snapshot(p["config"])
+	}
+	e.refreshImages()
+}
+
+// reconcile reacts to configuration changes the way the device's
+// orchestration does: changed tenants and partitions are redeployed and
+// derived state is refreshed.
+func (e *Emulator) reconcile() {
+	seen := map[string]bool{}
+	for _, t := range e.entries("f5-tenants:tenants", "tenant") {
+		id := "tenant=" + scalarString(t["name"])
+		seen[id] = true
+		// A Pending tenant is retried on every change, as the device
+		// does when images or resources become available. A PUT of the
+		// entry drops its state, which the device keeps, so that is
+		// rebuilt as well.
+		if e.synced[id] != snapshot(t["config"]) || tenantStatus(t) == "Pending" || t["state"] == nil {
+			e.deployTenant(t)
+			e.synced[id] = snapshot(t["config"])
+		}
+	}
+	for _, p := range e.entries("f5-system-partition:partitions", "partition") {
+		id := "partition=" + scalarString(p["name"])
+		seen[id] = true
+		if e.synced[id] != snapshot(p["config"]) {
+			e.deployPartition(p)
+			e.synced[id] = snapshot(p["config"])
+		}
+	}
+	for id := range e.synced {
+		if !seen[id] {
+			delete(e.synced, id)
+			if strings.HasPrefix(id, "partition=") {
+				e.releaseSlots(strings.TrimPrefix(id, "partition="))
+			}
+		}
+	}
+	e.refreshImages()
+}
+
+// entries returns the entries of list under the top-level container.
+func (e *Emulator) entries(container, list string) []map[string]interface{} {
+	c, ok := e.data[container].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	items, _ := c[list].([]interface{})
+	var out []map[string]interface{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func tenantStatus(t map[string]interface{}) string {
+	state, _ := t["state"].(map[string]interface{})
+	return scalarString(state["status"])
+}
+
+func snapshot(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func config(entry map[string]interface{}) map[string]interface{} {
+	cfg, ok := entry["config"].(map[string]interface{})
+	if !ok {
+		cfg = map[string]interface{}{}
+		entry["config"] = cfg
+	}
+	return cfg
+}
+
+// macPoolSizes maps the tenant mac-block-size to the MAC pool it
+// allocates.
+var macPoolSizes = map[string]int{"one": 1, "small": 8, "medium": 16, "large": 32}
+
+// tenantStateLeaves are the configuration leaves mirrored into tenant
+// state.
+var tenantStateLeaves = []string{
+	"name", "type", "image", "mgmt-ip", "prefix-length", "gateway", "nodes", "vlans",
+	"cryptos", "vcpu-cores-per-node", "dag-ipv6-prefix-length", "max-nodes",
+}
+
+// deployTenant rebuilds a tenant's state from its configuration and starts
+// its deployment.
+func (e *Emulator) deployTenant(t map[string]interface{}) {
+	name := scalarString(t["name"])
+	cfg := config(t)
+	if _, ok := cfg["running-state"]; !ok {
+		cfg["running-state"] = "configured"
+	}
+	if _, ok := cfg["nodes"]; !ok {
+		cfg["nodes"] = []interface{}{float64(1)}
+	}
+	path := []string{"tenants", segment{name: "tenant", keys: []string{name}, keyed: true}.String()}
+	e.upgradeTenant(cfg, path)
+
+	state, _ := t["state"].(map[string]interface{})
+	if state == nil {
+		state = map[string]interface{}{}
+		t["state"] = state
+	}
+	for _, leaf := range tenantStateLeaves {
+		if v, ok := cfg[leaf]; ok {
+			state[leaf] = deepCopy(v)
+		}
+	}
+	state["name"] = name
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	state["unit-key-hash"] = fmt.Sprintf("%x", h.Sum64())
+	if mem, ok := cfg["memory"]; ok {
+		state["memory"] = scalarString(mem)
+		state["physical-memory"] = scalarString(mem)
+	}
+	if storage, ok := cfg["storage"].(map[string]interface{}); ok {
+		state["storage"] = map[string]interface{}{"size": storage["size"]}
+	}
+	size := 1
+	if md, ok := cfg["mac-data"].(map[string]interface{}); ok {
+		for k, v := range md {
+			if localName(k) == "mac-block-size" {
+				size = macPoolSizes[scalarString(v)]
+			}
+		}
+	}
+	macData := map[string]interface{}{"base-mac": "00:94:a1:8e:d0:00", "mac-pool-size": float64(size)}
+	// Only 1.x devices list the block's addresses.
+	if !e.atLeast("2.0.0") {
+		var block []interface{}
+		for i := 0; i < size; i++ {
+			block = append(block, map[string]interface{}{"mac": fmt.Sprintf("00:94:a1:8e:d0:%02x", i)})
+		}
+		macData["f5-tenant-l2-inline:mac-block"] = block
+	}
+	state["mac-data"] = macData
+	state["running-state"] = cfg["running-state"]
+	if !e.atLeast("2.0.0") {
+		state["image-version"] = scalarString(cfg["image"])
+	}
+	delete(state, "instances")
+
+	running := scalarString(cfg["running-state"])
+	switch running {
+	case "deployed":
+		if reason := e.tenantPending(t); reason != "" {
+			state["status"] = "Pending"
+			e.setInstances(state, cfg, reason, "Tenant deployment will be processed when the resources are available")
+			return
+		}
+		state["status"] = "Starting"
+		e.setInstances(state, cfg, "Allocating resources to tenant is in progress", "Starting")
+		e.schedule(path, func() {
+			state["status"] = "Running"
+			e.setInstances(state, cfg, "Running", "Started tenant instance")
+		})
+	case "provisioned":
+		state["status"] = "Provisioned"
+	default:
+		state["status"] = "Configured"
+	}
+}
+
+// upgradeTenant starts moving a tenant to the target-image of its
+// configuration cfg, reporting progress in upgrade-status. As on the
+// device, only a tenant in the configured running state is upgraded.
+func (e *Emulator) upgradeTenant(cfg map[string]interface{}, path []string) {
+	target := scalarString(cfg["target-image"])
+	if target == "" {
+		return
+	}
+	if target == scalarString(cfg["image"]) {
+		delete(cfg, "target-image")
+		delete(cfg, "target-deployment-file")
+		delete(cfg, "upgrade-status")
+		return
+	}
+	if running := scalarString(cfg["running-state"]); running != "configured" {
+		cfg["upgrade-status"] = "failed: tenant must be in the configured running-state to be upgraded, not " + running
+		return
+	}
+	if reason := e.imageProblem(target); reason != "" {
+		cfg["upgrade-status"] = "failed: " + reason
+		return
+	}
+	cfg["upgrade-status"] = "in-progress"
+	e.schedule(path, func() {
+		cfg["image"] = target
+		if file, ok := cfg["target-deployment-file"]; ok {
+			cfg["deployment-file"] = file
+		}
+		delete(cfg, "target-image")
+		delete(cfg, "target-deployment-file")
+		cfg["upgrade-status"] = "completed"
+	})
+}
+
+// setInstances reports per-node instance detail, which only 1.x devices
+// include in tenant state.
+func (e *Emulator) setInstances(state, cfg map[string]interface{}, phase, status string) {
+	if e.atLeast("2.0.0") {
+		return
+	}
+	nodes, _ := cfg["nodes"].([]interface{})
+	name := scalarString(cfg["name"])
+	var list []interface{}
+	for i, n := range nodes {
+		list = append(list, map[string]interface{}{
+			"node":          n,
+			"pod-name":      fmt.Sprintf("%s-%s", name, scalarString(n)),
+			"instance-id":   float64(i + 1),
+			"phase":         phase,
+			"creation-time": time.Now().UTC().Format(time.RFC3339),
+			"status":        status,
+		})
+	}
+	state["instances"] = map[string]interface{}{"instance": list}
+}
+
+// tenantPending returns why a tenant cannot be deployed, or "" if it can.
+func (e *Emulator) tenantPending(t map[string]interface{}) string {
+	cfg := config(t)
+	if reason := e.imageProblem(scalarString(cfg["image"])); reason != "" {
+		return reason
+	}
+
+	// Every node the tenant runs on must have room for its vCPUs next to
+	// the other tenants placed there.
+	used := map[string]int{}
+	for _, other := range e.entries("f5-tenants:tenants", "tenant") {
+		if scalarString(other["name"]) == scalarString(t["name"]) {
+			continue
+		}
+		ocfg := config(other)
+		if rs := scalarString(ocfg["running-state"]); rs != "deployed" && rs != "provisioned" {
+			continue
+		}
+		for _, n := range listOf(ocfg["nodes"]) {
+			used[scalarString(n)] += intOf(ocfg["vcpu-cores-per-node"])
+		}
+	}
+	want := intOf(cfg["vcpu-cores-per-node"])
+	for _, n := range listOf(cfg["nodes"]) {
+		if used[scalarString(n)]+want > e.opts.CoresPerNode {
+			return fmt.Sprintf("Insufficient vCPUs on node %s: %d requested, %d free", scalarString(n), want, e.opts.CoresPerNode-used[scalarString(n)])
+		}
+	}
+	return ""
+}
+
+// imageProblem returns why a tenant cannot run image, or "" if it can.
+func (e *Emulator) imageProblem(image string) string {
+	for _, img := range e.entries("f5-tenant-images:images", "image") {
+		if scalarString(img["name"]) != image {
+			continue
+		}
+		if status := scalarString(img["status"]); status != "verified" && status != "replicated" {
+			return fmt.Sprintf("Tenant image %s is not ready (%s)", image, status)
+		}
+		return ""
+	}
+	return fmt.Sprintf("Tenant image %s is not present", image)
+}
+
+func listOf(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func intOf(v interface{}) int {
+	n, _ := strconv.Atoi(scalarString(v))
+	return n
+}
+
+// refreshImages marks the tenant images referenced by a tenant as in use.
+func (e *Emulator) refreshImages() {
+	inUse := map[string]bool{}
+	for _, t := range e.entries("f5-tenants:tenants", "tenant") {
+		inUse[scalarString(config(t)["image"])] = true
+	}
+	for _, img := range e.entries("f5-tenant-images:images", "image") {
+		img["in-use"] = inUse[scalarString(img["name"])]
+	}
+}
+
+// deployPartition rebuilds a partition's state from its configuration and
+// starts bringing it up on both controllers.
+func (e *Emulator) deployPartition(p map[string]interface{}) {
+	cfg := config(p)
+	name := scalarString(p["name"])
+	id := 0
+	for _, other := range e.entries("f5-system-partition:partitions", "partition") {
+		if st, ok := other["state"].(map[string]interface{}); ok && intOf(st["id"]) > id && scalarString(other["name"]) != name {
+			id = intOf(st["id"])
+		}
+	}
+	state, _ := p["state"].(map[string]interface{})
+	if state == nil {
+		state = map[string]interface{}{"id": float64(id + 1)}
+		p["state"] = state
+	}
+	version := scalarString(cfg["iso-version"])
+	state["os-version"] = version
+	state["service-version"] = version
+	state["install-status"] = "success"
+
+	status := "starting"
+	if enabled, _ := cfg["enabled"].(bool); !enabled {
+		status = "disabled"
+	}
+	state["controllers"] = partitionControllers(status, status)
+	if status == "disabled" {
+		return
+	}
+	path := []string{"partitions", segment{name: "partition", keys: []string{name}, keyed: true}.String()}
+	e.schedule(path, func() {
+		state["controllers"] = partitionControllers("running-active", "running-standby")
+	})
+}
+
+func partitionControllers(first, second string) map[string]interface{} {
+	return map[string]interface{}{"controller": []interface{}{
+		map[string]interface{}{"controller": float64(1), "partition-status": first},
+		map[string]interface{}{"controller": float64(2), "partition-status": second},
+	}}
+}
+
+// releaseSlots returns the slots of a deleted partition to "none".
+func (e *Emulator) releaseSlots(partition string) {
+	for _, s := range e.entries("f5-system-slot:slots", "slot") {
+		if scalarString(s["partition"]) == partition {
+			s["partition"] = "none"
+		}
+	}
+}
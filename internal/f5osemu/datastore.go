@@ -0,0 +1,690 @@
+package f5osemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The datastore holds the device's data tree in RFC 7951 JSON form, as
+// decoded by encoding/json: top-level members are module-qualified, child
+// members are qualified only where they cross into another module, lists
+// are []interface{} of entries and leaves are scalars.
+
+// listKeys names the key leaves of the lists the provider addresses by
+// key, in the order they appear in a RESTCONF path. Lists not named here
+// are keyed by "name".
+var listKeys = map[string][]string{
+	"component":          {"name"},
+	"controller":         {"controller", "number"},
+	"instance":           {"node"},
+	"iso":                {"version"},
+	"role":               {"rolename"},
+	"server":             {"address"},
+	"slot":               {"slot-num"},
+	"software-component": {"software-index"},
+	"transfer-operation": {"operation-id", "local-file-path"},
+	"user":               {"username"},
+	"vlan":               {"vlan-id"},
+}
+
+// knownLists are the list nodes a POST or PUT body may carry as a single
+// object rather than an array, as the client does for partitions.
+var knownLists = map[string]bool{
+	"image": true, "interface": true, "partition": true, "tenant": true,
+}
+
+// segment is one step of a RESTCONF data path: a node name, qualified by
+// module on the first step and where the path crosses modules, and the
+// list keys when it selects a list entry.
+type segment struct {
+	name string
+	keys []string
+	// keyed is set when the step carries "=", even with an empty key.
+	keyed bool
+}
+
+func (s segment) String() string {
+	if !s.keyed {
+		return s.name
+	}
+	keys := make([]string, len(s.keys))
+	for i, k := range s.keys {
+		keys[i] = url.PathEscape(k)
+	}
+	return s.name + "=" + strings.Join(keys, ",")
+}
+
+// restconfError is an error reported to the client as an RFC 8040 error
+// document.
+type restconfError struct {
+	status  int
+	tag     string
+	path    string
+	message string
+}
+
+func (e *restconfError) Error() string { return e.message }
+
+func (e *restconfError) document() []byte {
+	entry := map[string]interface{}{
+		"error-type":    "application",
+		"error-tag":     e.tag,
+		"error-message": e.message,
+	}
+	if e.status >= 500 {
+		entry["error-type"] = "protocol"
+	}
+	if e.path != "" {
+		entry["error-path"] = e.path
+	}
+	doc, _ := json.Marshal(map[string]interface{}{
+		"ietf-restconf:errors": map[string]interface{}{"error": []interface{}{entry}},
+	})
+	return doc
+}
+
+// errNotFound is confd's answer for a path that does not exist; the
+// client matches on its message.
+func errNotFound() error {
+	return &restconfError{status: http.StatusNotFound, tag: "invalid-value", message: "uri keypath not found"}
+}
+
+func errExists(path string) error {
+	return &restconfError{status: http.StatusConflict, tag: "data-exists", path: path, message: "object already exists: " + path}
+}
+
+func errBadRequest(format string, args ...interface{}) error {
+	return &restconfError{status: http.StatusBadRequest, tag: "malformed-message", message: fmt.Sprintf(format, args...)}
+}
+
+// parsePath splits a data path, relative to the RESTCONF data root, into
+// segments.
+func parsePath(p string) ([]segment, error) {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil, errBadRequest("empty data path")
+	}
+	var segs []segment
+	for _, raw := range strings.Split(p, "/") {
+		seg := segment{name: raw}
+		if i := strings.IndexByte(raw, '='); i >= 0 {
+			seg.name, seg.keyed = raw[:i], true
+			for _, k := range strings.Split(raw[i+1:], ",") {
+				v, err := url.PathUnescape(k)
+				if err != nil {
+					return nil, errBadRequest("invalid list key %q", k)
+				}
+				seg.keys = append(seg.keys, v)
+			}
+		}
+		if seg.name == "" {
+			return nil, errBadRequest("invalid data path %q", p)
+		}
+		segs = append(segs, seg)
+	}
+	if moduleOf(segs[0].name) == "" {
+		return nil, errBadRequest("top-level node %q must be module-qualified", segs[0].name)
+	}
+	return segs, nil
+}
+
+func localName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func moduleOf(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// qualify returns name as it is written in a response whose enclosing
+// module is module.
+func qualify(name, module string) string {
+	if moduleOf(name) != "" {
+		return name
+	}
+	return module + ":" + name
+}
+
+// memberKey finds the member of m that name refers to, preferring an
+// exact match over a match on the local name.
+func memberKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	want := localName(name)
+	for k := range m {
+		if localName(k) == want {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// storageKey returns the member key used to store name under a node of
+// module: unqualified when it is in the same module.
+func storageKey(name, module string) string {
+	if moduleOf(name) == module {
+		return localName(name)
+	}
+	return name
+}
+
+// scalarString formats a leaf value as it appears in a list key.
+func scalarString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// keyLeaves returns the key leaves of list name as present in entry.
+func keyLeaves(name string, entry map[string]interface{}) []string {
+	candidates, ok := listKeys[localName(name)]
+	if !ok {
+		return []string{"name"}
+	}
+	for _, k := range candidates {
+		if _, ok := entry[k]; ok {
+			return []string{k}
+		}
+	}
+	return candidates[:1]
+}
+
+// entryMatches reports whether entry of list name has the given keys.
+func entryMatches(name string, entry map[string]interface{}, keys []string) bool {
+	leaves := keyLeaves(name, entry)
+	if len(leaves) != len(keys) {
+		return false
+	}
+	for i, leaf := range leaves {
+		v, ok := entry[leaf]
+		if !ok {
+			// Entries sometimes carry their key only under config.
+			if cfg, isMap := entry["config"].(map[string]interface{}); isMap {
+				v, ok = cfg[leaf]
+			}
+		}
+		if !ok || scalarString(v) != keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func findEntry(name string, list []interface{}, keys []string) int {
+	for i, e := range list {
+		if m, ok := e.(map[string]interface{}); ok && entryMatches(name, m, keys) {
+			return i
+		}
+	}
+	return -1
+}
+
+// entryKeys returns the keys of entry in list name, for comparing entries.
+func entryKeys(name string, entry map[string]interface{}) []string {
+	leaves := keyLeaves(name, entry)
+	keys := make([]string, len(leaves))
+	for i, leaf := range leaves {
+		v, ok := entry[leaf]
+		if !ok {
+			if cfg, isMap := entry["config"].(map[string]interface{}); isMap {
+				v = cfg[leaf]
+			}
+		}
+		keys[i] = scalarString(v)
+	}
+	return keys
+}
+
+// descend resolves segs and returns the node they select, which must be
+// a container or list entry, and the module in effect there. With create
+// set, missing containers are created, as non-presence containers always
+// exist; list entries never are.
+func descend(root map[string]interface{}, segs []segment, create bool) (map[string]interface{}, string, error) {
+	cur, module := root, ""
+	for _, seg := range segs {
+		key, ok := memberKey(cur, seg.name)
+		if !ok {
+			if !create || seg.keyed {
+				return nil, "", errNotFound()
+			}
+			key = storageKey(seg.name, moduleOfParent(module, seg.name))
+			cur[key] = map[string]interface{}{}
+		}
+		module = moduleOrOwn(key, module)
+		switch v := cur[key].(type) {
+		case map[string]interface{}:
+			if seg.keyed {
+				return nil, "", errNotFound()
+			}
+			cur = v
+		case []interface{}:
+			if !seg.keyed {
+				return nil, "", errBadRequest("list %q requires keys", seg.name)
+			}
+			i := findEntry(key, v, seg.keys)
+			if i < 0 {
+				return nil, "", errNotFound()
+			}
+			entry, isMap := v[i].(map[string]interface{})
+			if !isMap {
+				return nil, "", errNotFound()
+			}
+			cur = entry
+		default:
+			return nil, "", errNotFound()
+		}
+	}
+	return cur, module, nil
+}
+
+// walk resolves every segment but the last and returns the node that
+// holds the last one and the module in effect there.
+func walk(root map[string]interface{}, segs []segment, create bool) (map[string]interface{}, string, error) {
+	return descend(root, segs[:len(segs)-1], create)
+}
+
+// moduleOfParent is the module a new child name is stored against. At
+// the top level there is none, so every member keeps its qualifier.
+func moduleOfParent(module, name string) string {
+	if module == "" {
+		return moduleOf(name)
+	}
+	return module
+}
+
+// get returns the node at segs, named as in a GET response.
+func get(root map[string]interface{}, segs []segment) (string, interface{}, error) {
+	parent, module, err := walk(root, segs, false)
+	if err != nil {
+		return "", nil, err
+	}
+	last := segs[len(segs)-1]
+	key, ok := memberKey(parent, last.name)
+	if !ok {
+		return "", nil, errNotFound()
+	}
+	name := qualify(localName(last.name), moduleOrOwn(key, module))
+	v := parent[key]
+	if !last.keyed {
+		return name, v, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return "", nil, errNotFound()
+	}
+	i := findEntry(key, list, last.keys)
+	if i < 0 {
+		return "", nil, errNotFound()
+	}
+	return name, []interface{}{list[i]}, nil
+}
+
+func moduleOrOwn(key, module string) string {
+	if m := moduleOf(key); m != "" {
+		return m
+	}
+	return module
+}
+
+// bodyMember returns the single member of a request body.
+func bodyMember(body []byte) (string, interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", nil, errBadRequest("invalid JSON body: %v", err)
+	}
+	if len(doc) != 1 {
+		return "", nil, errBadRequest("request body must have exactly one member, got %d", len(doc))
+	}
+	for k, v := range doc {
+		return k, v, nil
+	}
+	return "", nil, nil
+}
+
+// targetValue extracts the value for the node at segs from a PUT or
+// PATCH body. The body normally names the target itself; the client also
+// sends the enclosing container for some list entries (e.g. a tenant
+// update), in which case the target is located inside it.
+func targetValue(segs []segment, name string, value interface{}) (interface{}, error) {
+	last := segs[len(segs)-1]
+	if localName(name) != localName(last.name) {
+		start := -1
+		for i := len(segs) - 2; i >= 0; i-- {
+			if localName(segs[i].name) == localName(name) {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, errBadRequest("body member %q does not match target %q", name, last.name)
+		}
+		for _, seg := range segs[start+1:] {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errBadRequest("body does not contain %q", seg.name)
+			}
+			key, ok := memberKey(m, seg.name)
+			if !ok {
+				return nil, errBadRequest("body does not contain %q", seg.name)
+			}
+			value = m[key]
+			if seg.keyed {
+				list, ok := value.([]interface{})
+				if !ok {
+					return nil, errBadRequest("body %q is not a list", seg.name)
+				}
+				i := findEntry(key, list, seg.keys)
+				if i < 0 {
+					return nil, errBadRequest("body does not contain %s", seg)
+				}
+				value = []interface{}{list[i]}
+			}
+		}
+	}
+	if !last.keyed {
+		return value, nil
+	}
+	// A list entry is sent as a one-element list (or a bare object).
+	if list, ok := value.([]interface{}); ok {
+		if len(list) != 1 {
+			return nil, errBadRequest("body for %s must hold exactly one entry", last)
+		}
+		value = list[0]
+	}
+	entry, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errBadRequest("body for %s must be an object", last)
+	}
+	if keys := entryKeys(last.name, entry); strings.Join(keys, ",") != strings.Join(last.keys, ",") && strings.Join(keys, "") != "" {
+		return nil, errBadRequest("list key in body (%s) does not match the URI (%s)", strings.Join(keys, ","), strings.Join(last.keys, ","))
+	}
+	for i, leaf := range keyLeaves(last.name, entry) {
+		if _, ok := entry[leaf]; !ok && i < len(last.keys) {
+			entry[leaf] = keyValue(last.keys[i])
+		}
+	}
+	return entry, nil
+}
+
+// keyValue converts a path key back to the JSON type it most likely has.
+func keyValue(k string) interface{} {
+	if n, err := strconv.ParseFloat(k, 64); err == nil && strconv.FormatFloat(n, 'f', -1, 64) == k {
+		return n
+	}
+	return k
+}
+
+// put replaces (or creates) the node at segs with value. It reports
+// whether the node was created.
+func put(root map[string]interface{}, segs []segment, value interface{}) (bool, error) {
+	parent, module, err := walk(root, segs, true)
+	if err != nil {
+		return false, err
+	}
+	last := segs[len(segs)-1]
+	key, ok := memberKey(parent, last.name)
+	if !last.keyed {
+		if !ok {
+			key = storageKey(last.name, moduleOfParent(module, last.name))
+		}
+		parent[key] = value
+		return !ok, nil
+	}
+	if !ok {
+		key = storageKey(last.name, moduleOfParent(module, last.name))
+		parent[key] = []interface{}{}
+	}
+	list, isList := parent[key].([]interface{})
+	if !isList {
+		return false, errBadRequest("%q is not a list", last.name)
+	}
+	if i := findEntry(key, list, last.keys); i >= 0 {
+		list[i] = value
+		return false, nil
+	}
+	parent[key] = append(list, value)
+	return true, nil
+}
+
+// merge applies a PATCH of value to the node at segs. Containers along
+// the way are created, but a list entry must already exist.
+func merge(root map[string]interface{}, segs []segment, value interface{}) error {
+	parent, module, err := walk(root, segs, true)
+	if err != nil {
+		return err
+	}
+	last := segs[len(segs)-1]
+	key, ok := memberKey(parent, last.name)
+	if !last.keyed {
+		if !ok {
+			parent[storageKey(last.name, moduleOfParent(module, last.name))] = value
+			return nil
+		}
+		parent[key] = mergeValue(key, parent[key], value)
+		return nil
+	}
+	if !ok {
+		return errNotFound()
+	}
+	list, isList := parent[key].([]interface{})
+	if !isList {
+		return errNotFound()
+	}
+	i := findEntry(key, list, last.keys)
+	if i < 0 {
+		return errNotFound()
+	}
+	list[i] = mergeValue(key, list[i], value)
+	return nil
+}
+
+// mergeValue merges src into dst with NETCONF merge semantics: containers
+// merge member by member, list entries merge by key, leaf-lists take the
+// union and leaves are replaced.
+func mergeValue(name string, dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return s
+		}
+		for k, v := range s {
+			if dk, found := memberKey(d, k); found {
+				d[dk] = mergeValue(dk, d[dk], v)
+			} else {
+				d[k] = v
+			}
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			return s
+		}
+		for _, v := range s {
+			if entry, isMap := v.(map[string]interface{}); isMap {
+				if i := findEntry(name, d, entryKeys(name, entry)); i >= 0 {
+					d[i] = mergeValue(name, d[i], entry)
+					continue
+				}
+				d = append(d, entry)
+				continue
+			}
+			if !containsScalar(d, v) {
+				d = append(d, v)
+			}
+		}
+		return d
+	}
+	return src
+}
+
+func containsScalar(list []interface{}, v interface{}) bool {
+	for _, x := range list {
+		if scalarString(x) == scalarString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// create applies a POST of the child named name with value to the node
+// at segs. It fails with data-exists when the child, or any list entry
+// in it, is already present.
+func create(root map[string]interface{}, segs []segment, name string, value interface{}) error {
+	parent, module, err := descend(root, segs, true)
+	if err != nil {
+		return err
+	}
+	key, exists := memberKey(parent, name)
+	if !exists {
+		key = storageKey(name, moduleOfParent(module, name))
+	}
+	path := "/" + joinSegments(segs) + "/" + name
+
+	_, isList := value.([]interface{})
+	if _, ok := parent[key].([]interface{}); ok || knownLists[localName(name)] {
+		isList = true
+	}
+	if !isList {
+		if exists {
+			return errExists(path)
+		}
+		parent[key] = value
+		return nil
+	}
+
+	entries, ok := value.([]interface{})
+	if !ok {
+		entries = []interface{}{value}
+	}
+	list, _ := parent[key].([]interface{})
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return errBadRequest("entries of %q must be objects", name)
+		}
+		keys := entryKeys(key, entry)
+		if findEntry(key, list, keys) >= 0 {
+			return errExists(path + "=" + strings.Join(keys, ","))
+		}
+		list = append(list, entry)
+	}
+	parent[key] = list
+	return nil
+}
+
+// remove deletes the node at segs.
+func remove(root map[string]interface{}, segs []segment) error {
+	parent, _, err := walk(root, segs, false)
+	if err != nil {
+		return err
+	}
+	last := segs[len(segs)-1]
+	key, ok := memberKey(parent, last.name)
+	if !ok {
+		return errNotFound()
+	}
+	if !last.keyed {
+		delete(parent, key)
+		return nil
+	}
+	list, isList := parent[key].([]interface{})
+	if !isList {
+		return errNotFound()
+	}
+	i := findEntry(key, list, last.keys)
+	if i < 0 {
+		return errNotFound()
+	}
+	parent[key] = append(list[:i:i], list[i+1:]...)
+	return nil
+}
+
+func joinSegments(segs []segment) string {
+	parts := make([]string, len(segs))
+	for i, s := range segs {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, "/")
+}
+
+// localPath returns segs as unqualified names with keys, the form used
+// to match actions and asynchronous jobs.
+func localPath(segs []segment) []string {
+	out := make([]string, len(segs))
+	for i, s := range segs {
+		out[i] = segment{name: localName(s.name), keys: s.keys, keyed: s.keyed}.String()
+	}
+	return out
+}
+
+// overlaps reports whether one path is a prefix of the other.
+func overlaps(a, b []string) bool {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// modules returns every module named in the keys of v.
+func modules(v interface{}, into map[string]bool) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, child := range x {
+			if m := moduleOf(k); m != "" {
+				into[m] = true
+			}
+			modules(child, into)
+		}
+	case []interface{}:
+		for _, child := range x {
+			modules(child, into)
+		}
+	}
+}
+
+// deepCopy returns a copy of a decoded JSON value that shares nothing
+// with it.
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, child := range x {
+			out[k] = deepCopy(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, child := range x {
+			out[i] = deepCopy(child)
+		}
+		return out
+	}
+	return v
+}
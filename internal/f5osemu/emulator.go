@@ -0,0 +1,579 @@
+// Package f5osemu emulates the RESTCONF API of an F5OS device closely
+// enough to run the provider against it end to end.
+//
+// Unlike the handler mocks in the provider tests, the emulator is
+// stateful: it keeps a datastore tree, seeded from the JSON files in its
+// fixtures directory, and applies GET, PUT, PATCH, POST and DELETE to it
+// with RESTCONF semantics, so a write is visible to the next read and a
+// change made behind the provider's back shows up as drift. Tenant
+// deployment and upgrade, tenant image import and partition creation run
+// as asynchronous state machines that advance as the client polls them.
+//
+// The emulated platform (rSeries appliance, VELOS controller or VELOS
+// partition) and F5OS version select the seed data and which YANG
+// modules exist, so platform and version gates are exercised as on a
+// real device.
+//
+// Use NewServer in tests; cmd/f5osemu serves the emulator for local
+// demos.
+package f5osemu
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Platform is the kind of F5OS system being emulated.
+type Platform string
+
+const (
+	// RSeries is an rSeries appliance.
+	RSeries Platform = "rseries"
+	// VelosController is a VELOS system controller, which manages
+	// partitions but runs no tenants.
+	VelosController Platform = "velos-controller"
+	// VelosPartition is a VELOS chassis partition, which runs tenants on
+	// its blades.
+	VelosPartition Platform = "velos-partition"
+)
+
+// DefaultAsyncPolls is the number of status reads an asynchronous
+// operation reports as in progress before it completes.
+const DefaultAsyncPolls = 2
+
+// Options configures an Emulator. The zero value emulates an rSeries
+// appliance running F5OS-A 1.8 with admin/admin credentials.
+type Options struct {
+	Platform Platform
+	// Version is the F5OS version reported by the device, e.g.
+	// "2.0.0-1234". It defaults to a 1.8 release.
+	Version string
+	// Username and Password are the credentials accepted at login. They
+	// default to admin/admin.
+	Username string
+	Password string
+	// AsyncPolls is the number of status reads a tenant deployment, image
+	// import or partition creation reports as in progress before it
+	// completes. Zero selects DefaultAsyncPolls; a negative value
+	// completes operations on the first read.
+	AsyncPolls int
+	// CoresPerNode is the number of vCPUs available to tenants on each
+	// node (the appliance, or a blade). A deployment that does not fit
+	// stays Pending. It defaults to 26 on rSeries and 22 on VELOS. An
+	// rSeries appliance reports that many processor threads, and memory
+	// for a tenant of that many vCPUs, in its platform state.
+	CoresPerNode int
+}
+
+var defaultVersions = map[Platform]string{
+	RSeries:         "1.8.0-17191",
+	VelosController: "1.8.0-11003",
+	VelosPartition:  "1.8.0-11003",
+}
+
+var defaultCores = map[Platform]int{
+	RSeries:         26,
+	VelosController: 0,
+	VelosPartition:  22,
+}
+
+// nodeMemory returns the memory of a node with cores vCPUs, in bytes:
+// what the provider sizes a tenant of that many vCPUs to by default on
+// the emulated r5900.
+func nodeMemory(cores int) int {
+	return (int(3.5*1024*float64(cores)) + 512) * 1024 * 1024
+}
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+var seedFiles = map[Platform]string{
+	RSeries:         "fixtures/rseries.json",
+	VelosController: "fixtures/velos_controller.json",
+	VelosPartition:  "fixtures/velos_partition.json",
+}
+
+// dataRoots are the RESTCONF data resources the device serves: 8888
+// exposes /restconf, 443 exposes /api.
+var dataRoots = []string{"/restconf/data", "/api/data"}
+
+// loginPath is the resource the client reads to log in.
+const loginPath = "openconfig-system:system/aaa"
+
+// yangLibraryPath is the module list the client reads to learn which
+// modules the device implements. It is computed rather than stored.
+const yangLibraryPath = "ietf-yang-library:modules-state"
+
+// Emulator is an emulated F5OS device. It implements http.Handler and is
+// safe for concurrent use; requests are applied one at a time.
+type Emulator struct {
+	opts Options
+
+	mu       sync.Mutex
+	data     map[string]interface{}
+	tokens   map[string]bool
+	serial   int
+	jobs     []*job
+	synced   map[string]string
+	uploads  map[string]string
+	failures []failure
+	requests []string
+}
+
+// failure is an error injected with FailNext.
+type failure struct {
+	method string
+	path   string
+	err    *restconfError
+}
+
+// New returns an emulator seeded for opts.Platform.
+func New(opts Options) (*Emulator, error) {
+	if opts.Platform == "" {
+		opts.Platform = RSeries
+	}
+	file, ok := seedFiles[opts.Platform]
+	if !ok {
+		return nil, fmt.Errorf("f5osemu: unknown platform %q", opts.Platform)
+	}
+	if opts.Version == "" {
+		opts.Version = defaultVersions[opts.Platform]
+	}
+	if _, ok := parseVersion(opts.Version); !ok {
+		return nil, fmt.Errorf("f5osemu: invalid version %q", opts.Version)
+	}
+	if opts.Username == "" {
+		opts.Username = "admin"
+	}
+	if opts.Password == "" {
+		opts.Password = "admin"
+	}
+	if opts.AsyncPolls == 0 {
+		opts.AsyncPolls = DefaultAsyncPolls
+	}
+	if opts.CoresPerNode == 0 {
+		opts.CoresPerNode = defaultCores[opts.Platform]
+	}
+
+	seed, err := fixtures.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	seed = []byte(strings.NewReplacer(
+		"@VERSION@", opts.Version,
+		"@CORES@", strconv.Itoa(opts.CoresPerNode),
+		"@MEMORY@", strconv.Itoa(nodeMemory(opts.CoresPerNode)),
+	).Replace(string(seed)))
+	e := &Emulator{
+		opts:    opts,
+		tokens:  map[string]bool{},
+		synced:  map[string]string{},
+		uploads: map[string]string{},
+	}
+	if err := json.Unmarshal(seed, &e.data); err != nil {
+		return nil, fmt.Errorf("f5osemu: parsing %s: %w", file, err)
+	}
+	for k := range e.data {
+		if !e.supports(moduleOf(k)) {
+			delete(e.data, k)
+		}
+	}
+	e.markSynced()
+	return e, nil
+}
+
+// Server is an Emulator served by an httptest.Server.
+type Server struct {
+	*httptest.Server
+	*Emulator
+}
+
+// NewServer starts an emulator for opts on a local HTTP listener. The
+// caller closes it with Close.
+func NewServer(opts Options) (*Server, error) {
+	e, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{Server: httptest.NewServer(e), Emulator: e}, nil
+}
+
+// NewTLSServer is NewServer over HTTPS with httptest's self-signed
+// certificate.
+func NewTLSServer(opts Options) (*Server, error) {
+	e, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{Server: httptest.NewTLSServer(e), Emulator: e}, nil
+}
+
+// Platform returns the emulated platform.
+func (e *Emulator) Platform() Platform { return e.opts.Platform }
+
+// Version returns the emulated F5OS version.
+func (e *Emulator) Version() string { return e.opts.Version }
+
+// Load replaces the data at path with body, a RESTCONF JSON document
+// such as a GET response saved in a fixture. Asynchronous state machines
+// are not started for loaded data; it is taken to be settled already.
+func (e *Emulator) Load(path string, body []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	name, value, err := bodyMember(body)
+	if err != nil {
+		return err
+	}
+	value, err = targetValue(segs, name, normalize(value))
+	if err != nil {
+		return err
+	}
+	if _, err := put(e.data, segs, value); err != nil {
+		return err
+	}
+	e.markSynced()
+	return nil
+}
+
+// Get returns the data at path as the device would answer a GET, without
+// advancing any asynchronous operation.
+func (e *Emulator) Get(path string) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	segs, err := e.parse(path)
+	if err != nil {
+		return nil, err
+	}
+	name, value, err := get(e.data, segs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{name: value})
+}
+
+// Patch merges body into the data at path as if it had been changed on
+// the device directly, for drift scenarios. Tenants and partitions it
+// changes are redeployed.
+func (e *Emulator) Patch(path string, body []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.patch(path, body)
+}
+
+// Delete removes the data at path as if it had been deleted on the device
+// directly.
+func (e *Emulator) Delete(path string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	if err := remove(e.data, segs); err != nil {
+		return err
+	}
+	e.reconcile()
+	return nil
+}
+
+// FailNext makes the next request with method to path (relative to the
+// data root, keys included) fail with status and message instead of
+// being applied.
+func (e *Emulator) FailNext(method, path string, status int, message string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, failure{
+		method: method,
+		path:   strings.Trim(path, "/"),
+		err:    &restconfError{status: status, tag: "operation-failed", message: message},
+	})
+}
+
+// ExpireTokens invalidates every issued token, as a device restart or
+// session timeout would.
+func (e *Emulator) ExpireTokens() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tokens = map[string]bool{}
+}
+
+// Requests returns every request served so far as "METHOD /uri".
+func (e *Emulator) Requests() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.requests...)
+}
+
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, r.Method+" "+r.URL.RequestURI())
+
+	if r.URL.Path == "/api" {
+		// The client pings the API root to keep the session alive.
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ietf-restconf:restconf": map[string]interface{}{}})
+		return
+	}
+	path, ok := "", false
+	for _, root := range dataRoots {
+		if rest := strings.TrimPrefix(r.URL.EscapedPath(), root); rest != r.URL.EscapedPath() {
+			path, ok = strings.Trim(rest, "/"), true
+			break
+		}
+	}
+	if !ok {
+		writeError(w, errNotFound())
+		return
+	}
+
+	token, ok := e.authenticate(r, r.Method == http.MethodGet && path == loginPath)
+	if !ok {
+		writeError(w, &restconfError{status: http.StatusUnauthorized, tag: "access-denied", message: "access denied"})
+		return
+	}
+	if token != "" {
+		w.Header().Set("X-Auth-Token", token)
+	}
+	for i, f := range e.failures {
+		if f.method == r.Method && f.path == path {
+			e.failures = append(e.failures[:i:i], e.failures[i+1:]...)
+			writeError(w, f.err)
+			return
+		}
+	}
+
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		err = e.serveGet(w, path)
+	case http.MethodPut, http.MethodPatch:
+		err = e.serveWrite(w, r, path)
+	case http.MethodPost:
+		err = e.servePost(w, r, path)
+	case http.MethodDelete:
+		err = e.serveDelete(w, path)
+	default:
+		err = &restconfError{status: http.StatusMethodNotAllowed, tag: "operation-not-supported", message: "method not allowed: " + r.Method}
+	}
+	if err != nil {
+		writeError(w, err)
+	}
+}
+
+// authenticate checks the request's token or basic-auth credentials. A
+// basic-auth request, or any request to the login resource, is issued a
+// new token, which is returned.
+func (e *Emulator) authenticate(r *http.Request, login bool) (string, bool) {
+	if user, password, ok := r.BasicAuth(); ok {
+		if user != e.opts.Username || password != e.opts.Password {
+			return "", false
+		}
+		e.serial++
+		token := fmt.Sprintf("emu-token-%d", e.serial)
+		e.tokens[token] = true
+		return token, true
+	}
+	token := r.Header.Get("X-Auth-Token")
+	if !e.tokens[token] {
+		return "", false
+	}
+	if login {
+		return token, true
+	}
+	return "", true
+}
+
+func (e *Emulator) serveGet(w http.ResponseWriter, path string) error {
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	if len(segs) == 1 && segs[0].name == yangLibraryPath {
+		writeJSON(w, http.StatusOK, map[string]interface{}{yangLibraryPath: e.yangLibrary()})
+		return nil
+	}
+	local := localPath(segs)
+	e.runDue(local)
+	name, value, err := get(e.data, segs)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{name: value})
+	e.tick(local)
+	return nil
+}
+
+func (e *Emulator) serveWrite(w http.ResponseWriter, r *http.Request, path string) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if r.Method == http.MethodPatch {
+		if err := e.patch(path, body); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	name, value, err := e.member(body)
+	if err != nil {
+		return err
+	}
+	value, err = targetValue(segs, name, value)
+	if err != nil {
+		return err
+	}
+	created, err := put(e.data, segs, value)
+	if err != nil {
+		return err
+	}
+	e.reconcile()
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return nil
+}
+
+func (e *Emulator) patch(path string, body []byte) error {
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	name, value, err := e.member(body)
+	if err != nil {
+		return err
+	}
+	value, err = targetValue(segs, name, value)
+	if err != nil {
+		return err
+	}
+	if err := merge(e.data, segs, value); err != nil {
+		return err
+	}
+	e.reconcile()
+	return nil
+}
+
+func (e *Emulator) servePost(w http.ResponseWriter, r *http.Request, path string) error {
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	if act := actionFor(segs); act != nil {
+		return act(e, w, r, segs)
+	}
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	name, value, err := e.member(body)
+	if err != nil {
+		return err
+	}
+	if localName(name) == "input" {
+		return &restconfError{
+			status:  http.StatusNotImplemented,
+			tag:     "operation-not-supported",
+			message: fmt.Sprintf("f5osemu does not implement the %s action", joinSegments(segs)),
+		}
+	}
+	if err := create(e.data, segs, name, value); err != nil {
+		return err
+	}
+	e.reconcile()
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+func (e *Emulator) serveDelete(w http.ResponseWriter, path string) error {
+	segs, err := e.parse(path)
+	if err != nil {
+		return err
+	}
+	if err := remove(e.data, segs); err != nil {
+		return err
+	}
+	e.reconcile()
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// parse parses a data path and rejects paths into modules the emulated
+// device does not have, as confd does.
+func (e *Emulator) parse(path string) ([]segment, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range segs {
+		if m := moduleOf(s.name); m != "" && !e.supports(m) {
+			return nil, errNotFound()
+		}
+	}
+	return segs, nil
+}
+
+// member decodes a request body and rejects elements from modules the
+// emulated device does not have.
+func (e *Emulator) member(body []byte) (string, interface{}, error) {
+	name, value, err := bodyMember(body)
+	if err != nil {
+		return "", nil, err
+	}
+	used := map[string]bool{moduleOf(name): true}
+	modules(value, used)
+	for m := range used {
+		if m != "" && !e.supports(m) {
+			return "", nil, errBadRequest("unknown element: namespace %s is not supported on this device", m)
+		}
+	}
+	if leaf := e.unknownLeaf(value); leaf != "" {
+		return "", nil, errBadRequest("unknown element: %s is not supported in F5OS %s", leaf, e.opts.Version)
+	}
+	return name, normalize(value), nil
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, errBadRequest("missing request body")
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errBadRequest("reading request body: %v", err)
+	}
+	return body, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/yang-data+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	rcErr, ok := err.(*restconfError)
+	if !ok {
+		rcErr = &restconfError{status: http.StatusInternalServerError, tag: "operation-failed", message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/yang-data+json")
+	w.WriteHeader(rcErr.status)
+	_, _ = w.Write(rcErr.document())
+}
@@ -0,0 +1,462 @@
+package f5osemu
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// newSession starts an emulator for opts and logs a client session in to
+// it.
+func newSession(t *testing.T, opts Options) (*Server, *f5os.F5os) {
+	t.Helper()
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	server, err := NewServer(opts)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(server.Close)
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: server.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	return server, session
+}
+
+func vlanConfig(id int, name string) *f5os.F5ReqVlansConfig {
+	cfg := &f5os.F5ReqVlansConfig{}
+	v := f5os.F5ReqVlanConfig{VlanId: strconv.Itoa(id)}
+	v.Config.VlanId = id
+	v.Config.Name = name
+	cfg.OpenconfigVlanVlans.Vlan = append(cfg.OpenconfigVlanVlans.Vlan, v)
+	return cfg
+}
+
+func statusOf(err error) int {
+	var apiErr *f5os.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestEmulator_PlatformDetection(t *testing.T) {
+	for _, tc := range []struct {
+		platform Platform
+		version  string
+		wantType string
+	}{
+		{RSeries, "1.8.0-17191", "r5900"},
+		{RSeries, "2.0.0-3012", "r5900"},
+		{VelosController, "1.8.0-11003", "Velos Controller"},
+		{VelosPartition, "1.6.2-2345", "Velos Partition"},
+	} {
+		t.Run(string(tc.platform)+"/"+tc.version, func(t *testing.T) {
+			_, session := newSession(t, Options{Platform: tc.platform, Version: tc.version})
+			if session.PlatformType != tc.wantType {
+				t.Errorf("expected platform type %q, got %q", tc.wantType, session.PlatformType)
+			}
+			if session.PlatformVersion != tc.version {
+				t.Errorf("expected platform version %q, got %q", tc.version, session.PlatformVersion)
+			}
+		})
+	}
+}
+
+func TestEmulator_VlanLifecycle(t *testing.T) {
+	server, session := newSession(t, Options{})
+
+	if _, err := session.GetVlan(400); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 before create, got %v", err)
+	}
+	for _, name := range []string{"mytestvlan2", "mytestvlan3"} {
+		if _, err := session.VlanConfig(vlanConfig(400, name)); err != nil {
+			t.Fatalf("VlanConfig(%s) failed: %v", name, err)
+		}
+		vlan, err := session.GetVlan(400)
+		if err != nil {
+			t.Fatalf("GetVlan failed: %v", err)
+		}
+		if len(vlan.OpenconfigVlanVlan) != 1 || vlan.OpenconfigVlanVlan[0].Config.Name != name {
+			t.Fatalf("expected VLAN 400 named %q, got %+v", name, vlan)
+		}
+	}
+
+	// A change made on the device shows up on the next read.
+	if err := server.Patch("openconfig-vlan:vlans/vlan=400/config", []byte(`{"openconfig-vlan:config":{"name":"changed-on-box"}}`)); err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	vlan, err := session.GetVlan(400)
+	if err != nil {
+		t.Fatalf("GetVlan failed: %v", err)
+	}
+	if got := vlan.OpenconfigVlanVlan[0].Config.Name; got != "changed-on-box" {
+		t.Fatalf("expected the drifted name, got %q", got)
+	}
+
+	if err := session.DeleteVlan(400); err != nil {
+		t.Fatalf("DeleteVlan failed: %v", err)
+	}
+	if _, err := session.GetVlan(400); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %v", err)
+	}
+}
+
+// do sends a RESTCONF request with basic auth and returns the status and
+// body. The client treats some errors as success, so RESTCONF semantics
+// are checked on the wire.
+func do(t *testing.T, server *Server, method, path, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+"/restconf/data/"+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.SetBasicAuth("admin", "admin")
+	req.Header.Set("Content-Type", "application/yang-data+json")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestEmulator_RestconfErrors(t *testing.T) {
+	server, _ := newSession(t, Options{})
+
+	vlan := `{"openconfig-vlan:vlan":[{"vlan-id":10,"config":{"vlan-id":10,"name":"ten"}}]}`
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+		wantBody           string
+	}{
+		{http.MethodPost, "openconfig-vlan:vlans", vlan, http.StatusCreated, ""},
+		{http.MethodPost, "openconfig-vlan:vlans", vlan, http.StatusConflict, `"error-tag":"data-exists"`},
+		{http.MethodGet, "openconfig-vlan:vlans/vlan=10/config/name", "", http.StatusOK, `{"openconfig-vlan:name":"ten"}`},
+		{http.MethodPatch, "openconfig-vlan:vlans/vlan=11/config", `{"openconfig-vlan:config":{"name":"eleven"}}`, http.StatusNotFound, "uri keypath not found"},
+		{http.MethodDelete, "openconfig-vlan:vlans/vlan=11", "", http.StatusNotFound, "uri keypath not found"},
+		{http.MethodPut, "openconfig-vlan:vlans/vlan=11", `{"openconfig-vlan:vlan":[{"vlan-id":11,"config":{"name":"eleven"}}]}`, http.StatusCreated, ""},
+		{http.MethodPut, "openconfig-vlan:vlans/vlan=11", `{"openconfig-vlan:vlan":[{"vlan-id":11,"config":{"name":"eleven"}}]}`, http.StatusNoContent, ""},
+		{http.MethodPut, "openconfig-vlan:vlans/vlan=12", `{"openconfig-vlan:vlan":[{"vlan-id":11}]}`, http.StatusBadRequest, "does not match the URI"},
+		{http.MethodPost, "f5-tenant-images:images/f5-tenant-images:rename", `{"f5-tenant-images:input":{}}`, http.StatusNotImplemented, "does not implement"},
+		{http.MethodGet, "vlans", "", http.StatusBadRequest, "module-qualified"},
+	} {
+		status, body := do(t, server, tc.method, tc.path, tc.body)
+		if status != tc.want || !strings.Contains(body, tc.wantBody) {
+			t.Errorf("%s %s: expected %d with %q, got %d: %s", tc.method, tc.path, tc.want, tc.wantBody, status, body)
+		}
+	}
+}
+
+func TestEmulator_ModuleGating(t *testing.T) {
+	login := `{"f5-openconfig-aaa-login-policy:config":{"max-login-failures":5}}`
+	loginPath := "openconfig-system:system/aaa/f5-openconfig-aaa-login-policy:login-policy/config"
+	for _, tc := range []struct {
+		opts               Options
+		method, path, body string
+		want               int
+	}{
+		{Options{}, http.MethodGet, "f5-system-partition:partitions", "", http.StatusNotFound},
+		{Options{Platform: VelosController}, http.MethodGet, "f5-tenants:tenants", "", http.StatusNotFound},
+		{Options{Platform: VelosController}, http.MethodGet, "f5-system-partition:partitions", "", http.StatusOK},
+		{Options{}, http.MethodPatch, loginPath, login, http.StatusNotFound},
+		{Options{}, http.MethodPatch, "openconfig-system:system/aaa", `{"openconfig-system:aaa":{"f5-openconfig-aaa-login-policy:login-policy":{}}}`, http.StatusBadRequest},
+		{Options{Version: "2.0.0-3012"}, http.MethodPatch, loginPath, login, http.StatusNoContent},
+	} {
+		server, err := NewServer(tc.opts)
+		if err != nil {
+			t.Fatalf("NewServer failed: %v", err)
+		}
+		if status, body := do(t, server, tc.method, tc.path, tc.body); status != tc.want {
+			t.Errorf("%s %s on %s %s: expected %d, got %d: %s", tc.method, tc.path, server.Platform(), server.Version(), tc.want, status, body)
+		}
+		server.Close()
+	}
+}
+
+func TestEmulator_YangLibrary(t *testing.T) {
+	for _, tc := range []struct {
+		opts   Options
+		module string
+		want   bool
+	}{
+		{Options{}, "f5-tenants", true},
+		{Options{}, "f5-openconfig-aaa-login-policy", false},
+		{Options{Version: "2.0.0-3012"}, "f5-openconfig-aaa-login-policy", true},
+		{Options{Platform: VelosController}, "f5-tenants", false},
+		{Options{Platform: VelosController}, "f5-system-partition", true},
+		{Options{}, "f5-tenant-vwire", true},
+		{Options{Platform: VelosPartition}, "f5-tenant-vwire", false},
+	} {
+		t.Run(string(tc.opts.Platform)+tc.opts.Version+"/"+tc.module, func(t *testing.T) {
+			server, session := newSession(t, tc.opts)
+			got, err := session.Supports(tc.module, "")
+			if err != nil {
+				t.Fatalf("Supports failed: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected Supports(%s) = %v, got %v", tc.module, tc.want, got)
+			}
+			// The library is read once per session, also through copies.
+			before := len(server.Requests())
+			if _, err := session.WithContext(context.Background()).Supports("openconfig-vlan", "missing-feature"); err != nil {
+				t.Fatalf("Supports failed: %v", err)
+			}
+			if after := server.Requests(); len(after) != before {
+				t.Fatalf("expected the library to be cached, got %v", after[before:])
+			}
+		})
+	}
+}
+
+// importImage imports a tenant image and waits for it to be verified.
+func importImage(t *testing.T, session *f5os.F5os, name string) {
+	t.Helper()
+	req := &f5os.F5ReqTenantImage{LocalFile: "images/tenant", RemoteFile: "/images/" + name, RemoteHost: "files.example.net", Protocol: "https"}
+	resp, err := session.ImportImage(req, 60)
+	if err != nil || string(resp) != "Import Image Transfer Success" {
+		t.Fatalf("ImportImage failed: %s, %v", resp, err)
+	}
+	for i := 0; i < 10; i++ {
+		img, err := session.GetImage(name)
+		if err != nil {
+			t.Fatalf("GetImage failed: %v", err)
+		}
+		if status := img.TenantImages[0].Status; status == "verified" || status == "replicated" {
+			return
+		}
+	}
+	t.Fatalf("image %s was not verified", name)
+}
+
+func tenant(name, image string, cores int, nodes ...int) *f5os.F5ReqTenants {
+	tn := f5os.F5ReqTenant{Name: name}
+	tn.Config.Name = name
+	tn.Config.Image = image
+	tn.Config.Type = "BIG-IP"
+	tn.Config.MgmtIp = "192.0.2.10"
+	tn.Config.PrefixLength = 24
+	tn.Config.Gateway = "192.0.2.1"
+	tn.Config.VcpuCoresPerNode = cores
+	tn.Config.Memory = 3584 * cores
+	tn.Config.Nodes = nodes
+	tn.Config.RunningState = "deployed"
+	tn.Config.Storage.Size = 76
+	return &f5os.F5ReqTenants{F5TenantsTenant: []f5os.F5ReqTenant{tn}}
+}
+
+func TestEmulator_TenantDeployment(t *testing.T) {
+	server, session := newSession(t, Options{})
+	const image = "BIGIP-17.1.0-0.0.16.ALL-F5OS.qcow2.zip.bundle"
+
+	if _, err := session.CreateTenant(tenant("early", image, 4, 1), 60); err == nil || !strings.Contains(err.Error(), "is not present") {
+		t.Fatalf("expected a Pending tenant without its image, got %v", err)
+	}
+	if err := server.Delete("f5-tenants:tenants/tenant=early"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	importImage(t, session, image)
+	if _, err := session.ImportImage(&f5os.F5ReqTenantImage{LocalFile: "images/tenant", RemoteFile: "/images/" + image, RemoteHost: "files.example.net"}, 60); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected a second import to abort, got %v", err)
+	}
+
+	if resp, err := session.CreateTenant(tenant("tenant1", image, 8, 1), 60); err != nil {
+		t.Fatalf("CreateTenant failed: %s, %v", resp, err)
+	}
+	got, err := session.GetTenant("tenant1")
+	if err != nil {
+		t.Fatalf("GetTenant failed: %v", err)
+	}
+	state := got.F5TenantsTenant[0].State
+	if state.Status != "Running" || state.RunningState != "deployed" || state.VcpuCoresPerNode != 8 || state.Memory == "" {
+		t.Fatalf("unexpected tenant state %+v", state)
+	}
+	if len(state.Instances.Instance) != 1 {
+		t.Fatalf("expected 1.x tenant state to report instances, got %+v", state.Instances)
+	}
+	img, _ := session.GetImage(image)
+	if !img.TenantImages[0].InUse {
+		t.Fatalf("expected the image to be in use, got %+v", img.TenantImages[0])
+	}
+
+	// A second tenant that does not fit next to the first stays Pending.
+	_, err = session.CreateTenant(tenant("tenant2", image, 20, 1), 60)
+	if err == nil || !strings.Contains(err.Error(), "Tenant Deployment Pending") || !strings.Contains(err.Error(), "Insufficient vCPUs") {
+		t.Fatalf("expected a Pending deployment for lack of vCPUs, got %v", err)
+	}
+
+	if err := session.DeleteTenant("tenant2"); err != nil {
+		t.Fatalf("DeleteTenant failed: %v", err)
+	}
+	if _, err := session.GetTenant("tenant2"); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %v", err)
+	}
+}
+
+func TestEmulator_TenantStateOn2_0(t *testing.T) {
+	_, session := newSession(t, Options{Version: "2.0.0-3012", AsyncPolls: -1})
+	const image = "BIGIP-17.5.0-0.0.5.ALL-F5OS.qcow2.zip.bundle"
+	importImage(t, session, image)
+
+	req := tenant("tenant1", image, 4, 1)
+	req.F5TenantsTenant[0].Config.MaxNodes = 8
+	if resp, err := session.CreateTenant(req, 60); err != nil {
+		t.Fatalf("CreateTenant failed: %s, %v", resp, err)
+	}
+	raw, err := session.GetRequest("/f5-tenants:tenants/tenant=tenant1/state")
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if strings.Contains(string(raw), `"instances"`) || !strings.Contains(string(raw), `"max-nodes":8`) {
+		t.Fatalf("expected 2.0 tenant state without instances and with max-nodes, got %s", raw)
+	}
+
+	_, old := newSession(t, Options{Version: "1.8.0-17191"})
+	if _, err := old.CreateTenant(req, 60); statusOf(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "max-nodes") {
+		t.Fatalf("expected 1.8 to reject max-nodes, got %v", err)
+	}
+}
+
+func TestEmulator_TenantUpgrade(t *testing.T) {
+	_, session := newSession(t, Options{})
+	const from = "BIGIP-17.1.0-0.0.16.ALL-F5OS.qcow2.zip.bundle"
+	const to = "BIGIP-17.1.1-0.0.4.ALL-F5OS.qcow2.zip.bundle"
+	importImage(t, session, from)
+	importImage(t, session, to)
+	if resp, err := session.CreateTenant(tenant("tenant1", from, 4, 1), 60); err != nil {
+		t.Fatalf("CreateTenant failed: %s, %v", resp, err)
+	}
+
+	if err := session.UpgradeTenant(&f5os.TenantUpgrade{Name: "tenant1", Image: to}, 60); err != nil {
+		t.Fatalf("UpgradeTenant failed: %v", err)
+	}
+	got, err := session.GetTenant("tenant1")
+	if err != nil {
+		t.Fatalf("GetTenant failed: %v", err)
+	}
+	tn := got.F5TenantsTenant[0]
+	if tn.Config.Image != to || tn.State.Image != to || tn.Config.TargetImage != "" || tn.Config.UpgradeStatus != "completed" {
+		t.Fatalf("expected the tenant to run %s, got config %+v", to, tn.Config)
+	}
+	if tn.State.Status != "Running" || tn.State.RunningState != "deployed" {
+		t.Fatalf("expected the tenant to be deployed again, got %+v", tn.State)
+	}
+
+	// An image the device does not have fails the upgrade, and a rollback
+	// returns the tenant to the image it ran.
+	err = session.UpgradeTenant(&f5os.TenantUpgrade{Name: "tenant1", Image: "missing.bundle", Rollback: true}, 60)
+	var upgradeErr *f5os.TenantUpgradeError
+	if !errors.As(err, &upgradeErr) || !upgradeErr.RolledBack || !strings.Contains(upgradeErr.Status, "not present") {
+		t.Fatalf("expected a rolled back *TenantUpgradeError, got %v", err)
+	}
+	got, _ = session.GetTenant("tenant1")
+	if tn := got.F5TenantsTenant[0]; tn.Config.Image != to || tn.Config.TargetImage != "" || tn.State.Status != "Running" {
+		t.Fatalf("expected the tenant back on %s and running, got config %+v state %+v", to, tn.Config, tn.State)
+	}
+}
+
+func TestEmulator_PartitionLifecycle(t *testing.T) {
+	_, session := newSession(t, Options{Platform: VelosController})
+
+	partition := &f5os.F5ReqPartitions{}
+	partition.Partition.Name = "blue"
+	partition.Partition.Config.Enabled = true
+	partition.Partition.Config.IsoVersion = "1.8.0-11003"
+	partition.Partition.Config.MgmtIp.Ipv4.Address = "192.0.2.50"
+	partition.Partition.Config.MgmtIp.Ipv4.PrefixLength = 24
+	partition.Partition.Config.MgmtIp.Ipv4.Gateway = "192.0.2.1"
+	if _, err := session.CreatePartition(partition); err != nil {
+		t.Fatalf("CreatePartition failed: %v", err)
+	}
+	if _, err := session.CreatePartition(partition); statusOf(err) != http.StatusConflict {
+		t.Fatalf("expected a 409 creating the partition twice, got %v", err)
+	}
+	if _, err := session.SetSlot("blue", []int64{3, 4}); err != nil {
+		t.Fatalf("SetSlot failed: %v", err)
+	}
+
+	raw, err := session.GetRequest("/f5-system-partition:partitions/partition=blue/state")
+	if err != nil || !strings.Contains(string(raw), `"starting"`) {
+		t.Fatalf("expected the new partition to be starting, got %s, %v", raw, err)
+	}
+	if resp, err := session.CheckPartitionState("blue", 60); err != nil {
+		t.Fatalf("CheckPartitionState failed: %s, %v", resp, err)
+	}
+	got, err := session.GetPartition("blue")
+	if err != nil {
+		t.Fatalf("GetPartition failed: %v", err)
+	}
+	if got.Partition[0].Config.IsoVersion != "1.8.0-11003" {
+		t.Fatalf("unexpected partition %+v", got.Partition[0])
+	}
+	slots, err := session.GetPartitionSlots("blue")
+	if err != nil || len(slots) != 2 {
+		t.Fatalf("expected slots 3 and 4, got %v, %v", slots, err)
+	}
+
+	if err := session.DeletePartition("blue"); err != nil {
+		t.Fatalf("DeletePartition failed: %v", err)
+	}
+	if slots, err := session.GetPartitionSlots("blue"); err != nil || len(slots) != 0 {
+		t.Fatalf("expected the slots to be released, got %v, %v", slots, err)
+	}
+}
+
+func TestEmulator_FailuresAndTokens(t *testing.T) {
+	server, session := newSession(t, Options{})
+
+	// doRequest retries a failed write, so a one-off failure is absorbed.
+	server.FailNext(http.MethodPatch, "openconfig-vlan:vlans", http.StatusServiceUnavailable, "injected failure")
+	if _, err := session.VlanConfig(vlanConfig(20, "twenty")); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	server.FailNext(http.MethodGet, "openconfig-vlan:vlans/vlan=20", http.StatusBadRequest, "injected failure")
+	if _, err := session.GetVlan(20); statusOf(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "injected failure") {
+		t.Fatalf("expected the injected failure, got %v", err)
+	}
+	if _, err := session.GetVlan(20); err != nil {
+		t.Fatalf("expected the failure to apply once, got %v", err)
+	}
+
+	// The client logs in again when its token expires.
+	server.ExpireTokens()
+	if _, err := session.GetVlan(20); err != nil {
+		t.Fatalf("GetVlan after token expiry failed: %v", err)
+	}
+	logins := 0
+	for _, r := range server.Requests() {
+		if r == "GET /restconf/data/openconfig-system:system/aaa" {
+			logins++
+		}
+	}
+	if logins != 2 {
+		t.Fatalf("expected a second login after expiry, got %d in %v", logins, server.Requests())
+	}
+}
+
+func TestEmulator_Load(t *testing.T) {
+	e, err := New(Options{Platform: VelosPartition})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	fixture := []byte(`{"f5-tenants:tenant":[{"name":"loaded","config":{"name":"loaded","image":"img","running-state":"deployed"},"state":{"name":"loaded","status":"Running"}}]}`)
+	if err := e.Load("f5-tenants:tenants/tenant=loaded", fixture); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// Loaded data is taken as settled rather than redeployed.
+	got, err := e.Get("f5-tenants:tenants/tenant=loaded/state/status")
+	if err != nil || string(got) != `{"f5-tenants:status":"Running"}` {
+		t.Fatalf("unexpected loaded state %s, %v", got, err)
+	}
+	if _, err := New(Options{Platform: "bogus"}); err == nil {
+		t.Fatal("expected an unknown platform to be rejected")
+	}
+}
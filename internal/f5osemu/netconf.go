@@ -0,0 +1,860 @@
+package f5osemu
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// NETCONF stand-in: NetconfServer serves an Emulator over NETCONF on SSH
+// (RFC 6241, RFC 6242), for testing the client's NETCONF transport. Each
+// operation is translated into the RESTCONF request it corresponds to and
+// applied through ServeHTTP, so the datastore, FailNext and Requests
+// behave as they do over RESTCONF.
+//
+// The translation knows the emulated modules by name only: their XML
+// namespace is "urn:f5os-emulator:" followed by the module name, as the
+// hello advertises.
+
+const (
+	netconfBaseNS  = "urn:ietf:params:xml:ns:netconf:base:1.0"
+	netconfYang1NS = "urn:ietf:params:xml:ns:yang:1"
+	netconfBase10  = "urn:ietf:params:netconf:base:1.0"
+	netconfBase11  = "urn:ietf:params:netconf:base:1.1"
+	netconfEOM     = "]]>]]>"
+	// netconfModuleNS prefixes the namespace of every emulated module.
+	netconfModuleNS = "urn:f5os-emulator:"
+)
+
+// netconfLeafLists are the leaf-lists of the emulated models, which XML
+// cannot tell apart from leaves when they hold one value.
+var netconfLeafLists = map[string]bool{
+	"add-on-keys": true, "authentication-method": true, "authorized-keys": true,
+	"ciphers": true, "cpus": true, "host-key-algorithms": true,
+	"kexalgorithms": true, "macs": true, "nodes": true, "search": true,
+	"trunk-vlans": true, "users": true, "virtual-wires": true, "vlans": true,
+}
+
+// netconfStringLeaves hold strings that may look like numbers.
+var netconfStringLeaves = map[string]bool{
+	"contact": true, "description": true, "hostname": true, "key": true,
+	"location": true, "name": true, "password": true, "rolename": true,
+	"secret": true, "username": true, "value": true, "version": true,
+}
+
+// isList reports whether name is a list of the emulated models.
+func isList(name string) bool {
+	_, keyed := listKeys[name]
+	return keyed || knownLists[name] || map[string]bool{
+		"community": true, "member": true, "node": true, "remote-server": true,
+		"service": true, "target": true,
+	}[name]
+}
+
+// isListEntry reports whether n is a list entry. Leaves may share a
+// list's name, such as a tenant's image.
+func isListEntry(n *xmlNode) bool {
+	return isList(n.local) && len(n.elems) > 0
+}
+
+// isLeafList reports whether n is a leaf-list entry. Some names are a
+// leaf-list in one model and a container in another, such as a tenant's
+// vlans and openconfig-vlan's.
+func isLeafList(n *xmlNode) bool {
+	return netconfLeafLists[n.local] && len(n.elems) == 0
+}
+
+// NetconfServer is an Emulator served over NETCONF on a local SSH
+// listener.
+type NetconfServer struct {
+	*Emulator
+
+	listener net.Listener
+	config   *ssh.ServerConfig
+	hostKey  ssh.PublicKey
+
+	mu     sync.Mutex
+	conns  map[net.Conn]bool
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewNetconfServer serves e over NETCONF on 127.0.0.1 with a new ed25519
+// host key. The emulator's credentials are accepted as SSH passwords. The
+// caller closes it with Close.
+func NewNetconfServer(e *Emulator) (*NetconfServer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	s := &NetconfServer{Emulator: e, hostKey: signer.PublicKey(), conns: map[net.Conn]bool{}}
+	s.config = &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() != e.opts.Username || string(password) != e.opts.Password {
+				return nil, fmt.Errorf("access denied for %s", c.User())
+			}
+			return nil, nil
+		},
+	}
+	s.config.AddHostKey(signer)
+	if s.listener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return nil, err
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on.
+func (s *NetconfServer) Addr() string { return s.listener.Addr().String() }
+
+// Port returns the port the server listens on.
+func (s *NetconfServer) Port() int { return s.listener.Addr().(*net.TCPAddr).Port }
+
+// HostKey returns the server's SSH host key, for ssh.FixedHostKey.
+func (s *NetconfServer) HostKey() ssh.PublicKey { return s.hostKey }
+
+// Close stops the listener and closes every connection.
+func (s *NetconfServer) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *NetconfServer) accept() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(c)
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+			c.Close()
+		}()
+	}
+}
+
+func (s *NetconfServer) serveConn(c net.Conn) {
+	conn, chans, reqs, err := ssh.NewServerConn(c, s.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			_ = nc.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "netconf"
+				_ = req.Reply(ok, nil)
+				if ok {
+					go func() {
+						defer ch.Close()
+						s.serveSession(ch)
+					}()
+				}
+			}
+		}()
+	}
+}
+
+// netconfSession is the state of one NETCONF session.
+type netconfSession struct {
+	s       *NetconfServer
+	rw      io.ReadWriter
+	r       *bufio.Reader
+	chunked bool
+	token   string
+}
+
+func (s *NetconfServer) serveSession(rw io.ReadWriter) {
+	sess := &netconfSession{s: s, rw: rw, r: bufio.NewReader(rw), token: s.issueToken()}
+	var caps strings.Builder
+	for _, c := range append([]string{netconfBase10, netconfBase11, "urn:ietf:params:netconf:capability:writable-running:1.0"}, s.capabilities()...) {
+		caps.WriteString("<capability>" + xmlEscape(c) + "</capability>")
+	}
+	hello := `<?xml version="1.0" encoding="UTF-8"?><hello xmlns="` + netconfBaseNS + `"><capabilities>` + caps.String() +
+		`</capabilities><session-id>` + strings.TrimPrefix(sess.token, "emu-token-") + `</session-id></hello>` + netconfEOM
+	if _, err := io.WriteString(rw, hello); err != nil {
+		return
+	}
+	msg, err := sess.readEOM()
+	if err != nil {
+		return
+	}
+	peer, err := parseXML(msg)
+	if err != nil || peer.local != "hello" {
+		return
+	}
+	for _, c := range peer.child("capabilities").children() {
+		sess.chunked = sess.chunked || strings.TrimSpace(c.text) == netconfBase11
+	}
+	for {
+		msg, err := sess.read()
+		if err != nil {
+			return
+		}
+		rpc, err := parseXML(msg)
+		if err != nil || rpc.local != "rpc" || len(rpc.elems) == 0 {
+			continue
+		}
+		reply, closing := sess.handle(rpc.elems[0])
+		out := `<?xml version="1.0" encoding="UTF-8"?><rpc-reply xmlns="` + netconfBaseNS + `" message-id="` + xmlEscape(rpc.attrs["message-id"]) + `">` + reply + `</rpc-reply>`
+		if err := sess.write(out); err != nil || closing {
+			return
+		}
+	}
+}
+
+// capabilities returns the module capabilities of the hello.
+func (s *NetconfServer) capabilities() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Emulator.mu.Lock()
+	names := s.Emulator.implemented()
+	s.Emulator.mu.Unlock()
+	caps := make([]string, len(names))
+	for i, m := range names {
+		caps[i] = netconfModuleNS + m + "?module=" + m
+	}
+	return caps
+}
+
+// issueToken returns a new session token, which the session uses for the
+// RESTCONF requests its operations turn into.
+func (e *Emulator) issueToken() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.serial++
+	token := fmt.Sprintf("emu-token-%d", e.serial)
+	e.tokens[token] = true
+	return token
+}
+
+func (sess *netconfSession) write(msg string) error {
+	if sess.chunked {
+		msg = fmt.Sprintf("\n#%d\n%s\n##\n", len(msg), msg)
+	} else {
+		msg += netconfEOM
+	}
+	_, err := io.WriteString(sess.rw, msg)
+	return err
+}
+
+func (sess *netconfSession) read() ([]byte, error) {
+	if !sess.chunked {
+		return sess.readEOM()
+	}
+	var msg bytes.Buffer
+	for {
+		header, err := sess.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if header == "\n" {
+			header, err = sess.r.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+		}
+		if header == "##\n" {
+			return msg.Bytes(), nil
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(header, "#"), "\n"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk header %q", header)
+		}
+		if _, err := io.CopyN(&msg, sess.r, int64(size)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (sess *netconfSession) readEOM() ([]byte, error) {
+	var msg []byte
+	for !bytes.HasSuffix(msg, []byte(netconfEOM)) {
+		b, err := sess.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, b)
+	}
+	return msg[:len(msg)-len(netconfEOM)], nil
+}
+
+// handle runs an operation and returns the content of its reply, and
+// whether the session ends.
+func (sess *netconfSession) handle(op *xmlNode) (string, bool) {
+	if op.space == netconfBaseNS {
+		switch op.local {
+		case "get", "get-config":
+			return sess.get(op.child("filter"))
+		case "edit-config":
+			return sess.editConfig(op.child("config"))
+		case "close-session":
+			return "<ok/>", true
+		case "commit", "discard-changes", "lock", "unlock", "validate":
+			return "<ok/>", false
+		}
+		return rpcError(http.StatusBadRequest, "operation-not-supported", "", op.local+" is not supported"), false
+	}
+	if op.space == netconfYang1NS && op.local == "action" {
+		return sess.action(op), false
+	}
+	// A top-level RPC.
+	module := moduleOfNS(op.space)
+	return sess.invoke([]string{module + ":" + op.local}, module, op.children()), false
+}
+
+// get answers a get or get-config with the data the subtree filter
+// selects. The filter is expected to select one node: a chain of
+// containers and list entries, as the client builds them.
+func (sess *netconfSession) get(filter *xmlNode) (string, bool) {
+	var data strings.Builder
+	for _, top := range filter.children() {
+		segs, chain := filterPath(top)
+		status, body := sess.restconf(http.MethodGet, strings.Join(segs, "/"), nil)
+		if status == http.StatusNotFound {
+			continue
+		}
+		if status != http.StatusOK {
+			return restconfToRPCError(status, body), false
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return rpcError(http.StatusInternalServerError, "operation-failed", "", err.Error()), false
+		}
+		var inner strings.Builder
+		for name, value := range doc {
+			for _, n := range toXML(name, value, "") {
+				inner.WriteString(n)
+			}
+		}
+		// Wrap the answer in the filter's ancestors, with their keys.
+		out := inner.String()
+		for i := len(chain) - 2; i >= 0; i-- {
+			n := chain[i]
+			var keys strings.Builder
+			for _, k := range n.children() {
+				if len(k.elems) == 0 && strings.TrimSpace(k.text) != "" {
+					keys.WriteString("<" + k.local + ">" + xmlEscape(strings.TrimSpace(k.text)) + "</" + k.local + ">")
+				}
+			}
+			ns := ""
+			if i == 0 || n.space != chain[i-1].space {
+				ns = ` xmlns="` + xmlEscape(n.space) + `"`
+			}
+			out = "<" + n.local + ns + ">" + keys.String() + out + "</" + n.local + ">"
+		}
+		data.WriteString(out)
+	}
+	return "<data>" + data.String() + "</data>", false
+}
+
+// filterPath returns the RESTCONF path of the node a filter subtree
+// selects, and the filter nodes along it.
+func filterPath(n *xmlNode) ([]string, []*xmlNode) {
+	var segs []string
+	var chain []*xmlNode
+	module := ""
+	for n != nil {
+		chain = append(chain, n)
+		seg := n.local
+		if m := moduleOfNS(n.space); m != module {
+			module, seg = m, m+":"+n.local
+		}
+		var keys []string
+		var next []*xmlNode
+		for _, c := range n.children() {
+			if len(c.elems) == 0 && strings.TrimSpace(c.text) != "" {
+				keys = append(keys, url.PathEscape(strings.TrimSpace(c.text)))
+			} else {
+				next = append(next, c)
+			}
+		}
+		if len(n.elems) == 0 && strings.TrimSpace(n.text) != "" {
+			// A leaf-list entry.
+			keys = []string{url.PathEscape(strings.TrimSpace(n.text))}
+		}
+		if len(keys) > 0 {
+			seg += "=" + strings.Join(keys, ",")
+		}
+		segs = append(segs, seg)
+		n = nil
+		if len(next) == 1 {
+			n = next[0]
+		}
+	}
+	return segs, chain
+}
+
+// editConfig applies the operations in config: each node carrying an
+// operation attribute becomes a PUT (replace), POST (create) or DELETE
+// (delete, remove) of its path, and a tree without any is merged with a
+// PATCH of its top node.
+func (sess *netconfSession) editConfig(config *xmlNode) (string, bool) {
+	for _, top := range config.children() {
+		var ops []netconfOp
+		collectOps(top, nil, "", &ops)
+		if len(ops) == 0 {
+			module := moduleOfNS(top.space)
+			ops = append(ops, netconfOp{op: "merge", path: []string{module + ":" + top.local}, node: top, module: module})
+		}
+		for _, op := range ops {
+			if reply := sess.apply(op); reply != "" {
+				return reply, false
+			}
+		}
+	}
+	return "<ok/>", false
+}
+
+// netconfOp is an edit to apply to the node at path.
+type netconfOp struct {
+	op     string
+	path   []string
+	node   *xmlNode
+	module string
+}
+
+// collectOps appends the operations in the tree of n, whose parent is at
+// parent in module.
+func collectOps(n *xmlNode, parent []string, module string, ops *[]netconfOp) {
+	seg := n.local
+	if m := moduleOfNS(n.space); m != module {
+		module, seg = m, m+":"+n.local
+	}
+	if key := entryKey(n); key != "" {
+		seg += "=" + url.PathEscape(key)
+	}
+	path := append(append([]string(nil), parent...), seg)
+	if op := n.attrs["operation"]; op != "" {
+		*ops = append(*ops, netconfOp{op: op, path: path, node: n, module: module})
+		return
+	}
+	for _, c := range n.children() {
+		if len(c.elems) > 0 || c.attrs["operation"] != "" {
+			collectOps(c, path, module, ops)
+		}
+	}
+}
+
+// entryKey returns the key of n when it is a list or leaf-list entry.
+func entryKey(n *xmlNode) string {
+	if isLeafList(n) {
+		return strings.TrimSpace(n.text)
+	}
+	if !isListEntry(n) {
+		return ""
+	}
+	candidates, ok := listKeys[n.local]
+	if !ok {
+		candidates = []string{"name"}
+	}
+	for _, k := range candidates {
+		if c := n.child(k); c != nil {
+			return strings.TrimSpace(c.text)
+		}
+	}
+	return ""
+}
+
+func (sess *netconfSession) apply(op netconfOp) string {
+	name := op.module + ":" + op.node.local
+	value := jsonOf(op.node, op.module)
+	if isListEntry(op.node) || isLeafList(op.node) {
+		value = []interface{}{value}
+	}
+	body := map[string]interface{}{name: value}
+	var status int
+	var resp []byte
+	switch op.op {
+	case "merge":
+		status, resp = sess.restconf(http.MethodPatch, strings.Join(op.path, "/"), body)
+	case "replace":
+		status, resp = sess.restconf(http.MethodPut, strings.Join(op.path, "/"), body)
+	case "create":
+		status, resp = sess.restconf(http.MethodPost, strings.Join(op.path[:len(op.path)-1], "/"), body)
+	case "delete", "remove":
+		status, resp = sess.restconf(http.MethodDelete, strings.Join(op.path, "/"), nil)
+		if status == http.StatusNotFound && op.op == "remove" {
+			return ""
+		}
+	default:
+		return rpcError(http.StatusBadRequest, "bad-attribute", "", "unknown operation "+op.op)
+	}
+	if status >= 300 {
+		return restconfToRPCError(status, resp)
+	}
+	return ""
+}
+
+// action invokes the YANG action under an <action> element. The action
+// node is the one the emulator implements, or else the last node of the
+// chain; its children are the input.
+func (sess *netconfSession) action(a *xmlNode) string {
+	var path []string
+	var segs []segment
+	module := ""
+	n := a.child("")
+	var act *xmlNode
+	for n != nil {
+		seg := n.local
+		if m := moduleOfNS(n.space); m != module {
+			module, seg = m, m+":"+n.local
+		}
+		key := entryKey(n)
+		segs = append(segs, segment{name: seg})
+		if key != "" {
+			seg += "=" + url.PathEscape(key)
+		}
+		path = append(path, seg)
+		act = n
+		if actionFor(segs) != nil {
+			break
+		}
+		// Descend while the node holds nothing but its key and one child.
+		var next []*xmlNode
+		input := false
+		for _, c := range n.children() {
+			switch {
+			case len(c.elems) == 0 && strings.TrimSpace(c.text) != "":
+				input = input || key == "" || strings.TrimSpace(c.text) != key
+			default:
+				next = append(next, c)
+			}
+		}
+		n = nil
+		if !input && len(next) == 1 {
+			n = next[0]
+		}
+	}
+	if act == nil {
+		return rpcError(http.StatusBadRequest, "missing-element", "", "action has no target")
+	}
+	return sess.invoke(path, module, act.children())
+}
+
+// invoke POSTs input to the action or RPC at path and returns its output.
+func (sess *netconfSession) invoke(path []string, module string, input []*xmlNode) string {
+	in := map[string]interface{}{}
+	for _, c := range input {
+		name := c.local
+		if m := moduleOfNS(c.space); m != module {
+			name = m + ":" + c.local
+		}
+		value := jsonOf(c, module)
+		if isListEntry(c) || isLeafList(c) {
+			list, _ := in[name].([]interface{})
+			in[name] = append(list, value)
+			continue
+		}
+		in[name] = value
+	}
+	status, resp := sess.restconf(http.MethodPost, strings.Join(path, "/"), map[string]interface{}{module + ":input": in})
+	if status >= 300 {
+		return restconfToRPCError(status, resp)
+	}
+	var doc map[string]interface{}
+	if len(bytes.TrimSpace(resp)) == 0 || json.Unmarshal(resp, &doc) != nil {
+		return "<ok/>"
+	}
+	var out strings.Builder
+	for name, value := range doc {
+		output, ok := value.(map[string]interface{})
+		if !ok || localName(name) != "output" {
+			continue
+		}
+		for _, k := range sortedMembers(output) {
+			for _, n := range toXML(k, output[k], moduleOf(name)) {
+				out.WriteString(n)
+			}
+		}
+	}
+	if out.Len() == 0 {
+		return "<ok/>"
+	}
+	return out.String()
+}
+
+// restconf applies a RESTCONF request to the emulator as the session, and
+// returns the status and body of the answer.
+func (sess *netconfSession) restconf(method, path string, body interface{}) (int, []byte) {
+	for attempt := 0; ; attempt++ {
+		var r io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			r = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, dataRoots[0]+"/"+path, r)
+		req.Header.Set("X-Auth-Token", sess.token)
+		req.Header.Set("Content-Type", "application/yang-data+json")
+		rec := httptest.NewRecorder()
+		sess.s.Emulator.ServeHTTP(rec, req)
+		if rec.Code == http.StatusUnauthorized && attempt == 0 {
+			// ExpireTokens ends RESTCONF sessions, not this one.
+			sess.token = sess.s.issueToken()
+			continue
+		}
+		return rec.Code, rec.Body.Bytes()
+	}
+}
+
+// restconfToRPCError converts a RESTCONF error answer to an rpc-error. A
+// missing object is reported as data-missing.
+func restconfToRPCError(status int, body []byte) string {
+	var doc struct {
+		Errors struct {
+			Error []struct {
+				Tag     string `json:"error-tag"`
+				Path    string `json:"error-path"`
+				Message string `json:"error-message"`
+			} `json:"error"`
+		} `json:"ietf-restconf:errors"`
+	}
+	tag, path, message := "operation-failed", "", http.StatusText(status)
+	if json.Unmarshal(body, &doc) == nil && len(doc.Errors.Error) > 0 {
+		e := doc.Errors.Error[0]
+		tag, path, message = e.Tag, e.Path, e.Message
+	}
+	if status == http.StatusNotFound {
+		tag = "data-missing"
+	}
+	return rpcError(status, tag, path, message)
+}
+
+func rpcError(status int, tag, path, message string) string {
+	typ := "application"
+	if status >= 500 {
+		typ = "protocol"
+	}
+	out := "<rpc-error><error-type>" + typ + "</error-type><error-tag>" + xmlEscape(tag) + "</error-tag><error-severity>error</error-severity>"
+	if path != "" {
+		out += "<error-path>" + xmlEscape(path) + "</error-path>"
+	}
+	return out + "<error-message>" + xmlEscape(message) + "</error-message></rpc-error>"
+}
+
+// toXML converts the JSON member name: value, in a node of module, to
+// XML elements: one per entry for a list or leaf-list.
+func toXML(name string, value interface{}, module string) []string {
+	local := localName(name)
+	ns := ""
+	if m := moduleOf(name); m != "" && m != module {
+		module, ns = m, ` xmlns="`+xmlEscape(netconfModuleNS+m)+`"`
+	}
+	entries, ok := value.([]interface{})
+	if !ok {
+		entries = []interface{}{value}
+	}
+	var out []string
+	for _, entry := range entries {
+		var b strings.Builder
+		b.WriteString("<" + local + ns + ">")
+		switch x := entry.(type) {
+		case map[string]interface{}:
+			members := sortedMembers(x)
+			keys := listKeys[local]
+			if keys == nil {
+				keys = []string{"name"}
+			}
+			sort.SliceStable(members, func(i, j int) bool {
+				return keyRank(keys, members[i]) < keyRank(keys, members[j])
+			})
+			for _, k := range members {
+				for _, c := range toXML(k, x[k], module) {
+					b.WriteString(c)
+				}
+			}
+		default:
+			b.WriteString(xmlEscape(scalarString(x)))
+		}
+		b.WriteString("</" + local + ">")
+		out = append(out, b.String())
+	}
+	return out
+}
+
+func keyRank(keys []string, member string) int {
+	for i, k := range keys {
+		if localName(member) == k {
+			return i
+		}
+	}
+	return len(keys)
+}
+
+// jsonOf converts n, a node of module, to JSON as encoding/json decodes
+// it.
+func jsonOf(n *xmlNode, module string) interface{} {
+	if len(n.elems) == 0 {
+		text := strings.TrimSpace(n.text)
+		switch {
+		case text == "":
+			return nil
+		case text == "true" || text == "false":
+			return text == "true"
+		case !netconfStringLeaves[n.local]:
+			if i, err := strconv.ParseInt(text, 10, 64); err == nil && strconv.FormatInt(i, 10) == text {
+				return float64(i)
+			}
+		}
+		return text
+	}
+	counts := map[string]int{}
+	for _, c := range n.children() {
+		counts[c.space+" "+c.local]++
+	}
+	obj := map[string]interface{}{}
+	for _, c := range n.children() {
+		name := c.local
+		childModule := module
+		if m := moduleOfNS(c.space); m != module {
+			childModule, name = m, m+":"+c.local
+		}
+		value := jsonOf(c, childModule)
+		if isListEntry(c) || isLeafList(c) || counts[c.space+" "+c.local] > 1 {
+			list, _ := obj[name].([]interface{})
+			obj[name] = append(list, value)
+			continue
+		}
+		obj[name] = value
+	}
+	return obj
+}
+
+func sortedMembers(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// moduleOfNS returns the module of an emulated namespace.
+func moduleOfNS(ns string) string {
+	return strings.TrimPrefix(ns, netconfModuleNS)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xmlNode is an element of a NETCONF message.
+type xmlNode struct {
+	space, local string
+	attrs        map[string]string
+	text         string
+	elems        []*xmlNode
+}
+
+// child returns the first child element named local, or any first child
+// for "". It returns nil when there is none.
+func (n *xmlNode) child(local string) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.elems {
+		if local == "" || c.local == local {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) children() []*xmlNode {
+	if n == nil {
+		return nil
+	}
+	return n.elems
+}
+
+func parseXML(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{space: t.Name.Space, local: t.Name.Local, attrs: map[string]string{}}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+					continue
+				}
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.elems = append(parent.elems, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no element")
+	}
+	return root, nil
+}
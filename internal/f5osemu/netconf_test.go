@@ -0,0 +1,161 @@
+package f5osemu
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"golang.org/x/crypto/ssh"
+)
+
+// newNetconfSession starts an emulator for opts served over NETCONF and
+// logs a client session in to it.
+func newNetconfSession(t *testing.T, opts Options) (*NetconfServer, *f5os.F5os) {
+	t.Helper()
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	e, err := New(opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server, err := NewNetconfServer(e)
+	if err != nil {
+		t.Fatalf("NewNetconfServer failed: %v", err)
+	}
+	t.Cleanup(server.Close)
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:     "127.0.0.1",
+		User:     "admin",
+		Password: "admin",
+		Netconf:  &f5os.NetconfConfig{Port: server.Port(), HostKeyCallback: ssh.FixedHostKey(server.HostKey())},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return server, session
+}
+
+func TestNetconf_PlatformDetection(t *testing.T) {
+	for _, tc := range []struct {
+		platform Platform
+		wantType string
+	}{
+		{RSeries, "r5900"},
+		{VelosPartition, "Velos Partition"},
+	} {
+		t.Run(string(tc.platform), func(t *testing.T) {
+			_, session := newNetconfSession(t, Options{Platform: tc.platform})
+			if session.PlatformType != tc.wantType {
+				t.Errorf("expected platform type %q, got %q", tc.wantType, session.PlatformType)
+			}
+			if session.PlatformVersion != defaultVersions[tc.platform] {
+				t.Errorf("expected platform version %q, got %q", defaultVersions[tc.platform], session.PlatformVersion)
+			}
+		})
+	}
+}
+
+func TestNetconf_VlanLifecycle(t *testing.T) {
+	server, session := newNetconfSession(t, Options{})
+
+	if _, err := session.GetVlan(400); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 before create, got %v", err)
+	}
+	for _, name := range []string{"mytestvlan2", "mytestvlan3"} {
+		if _, err := session.VlanConfig(vlanConfig(400, name)); err != nil {
+			t.Fatalf("VlanConfig(%s) failed: %v", name, err)
+		}
+		vlan, err := session.GetVlan(400)
+		if err != nil {
+			t.Fatalf("GetVlan failed: %v", err)
+		}
+		if len(vlan.OpenconfigVlanVlan) != 1 || vlan.OpenconfigVlanVlan[0].Config.Name != name || vlan.OpenconfigVlanVlan[0].Config.VlanID != 400 {
+			t.Fatalf("expected VLAN 400 named %q, got %+v", name, vlan)
+		}
+	}
+	if err := session.DeleteVlan(400); err != nil {
+		t.Fatalf("DeleteVlan failed: %v", err)
+	}
+	if _, err := session.GetVlan(400); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %v", err)
+	}
+
+	// The edits reached the datastore as the RESTCONF requests they
+	// stand for.
+	requests := strings.Join(server.Requests(), "\n")
+	for _, want := range []string{
+		"GET /restconf/data/openconfig-platform:components/component",
+		"PATCH /restconf/data/openconfig-vlan:vlans",
+		"DELETE /restconf/data/openconfig-vlan:vlans/vlan=400",
+	} {
+		if !strings.Contains(requests, want) {
+			t.Errorf("expected %q among the requests:\n%s", want, requests)
+		}
+	}
+}
+
+func TestNetconf_TenantDeployment(t *testing.T) {
+	_, session := newNetconfSession(t, Options{})
+	const image = "BIGIP-17.1.0-0.0.16.ALL-F5OS.qcow2.zip.bundle"
+
+	// The import is an action; the image list is read back from XML.
+	importImage(t, session, image)
+	if resp, err := session.CreateTenant(tenant("tenant1", image, 8, 1), 60); err != nil {
+		t.Fatalf("CreateTenant failed: %s, %v", resp, err)
+	}
+	got, err := session.GetTenant("tenant1")
+	if err != nil {
+		t.Fatalf("GetTenant failed: %v", err)
+	}
+	tn := got.F5TenantsTenant[0]
+	if tn.State.Status != "Running" || tn.State.VcpuCoresPerNode != 8 || len(tn.Config.Nodes) != 1 || tn.Config.Nodes[0] != 1 {
+		t.Fatalf("unexpected tenant %+v", tn)
+	}
+	if err := session.DeleteTenant("tenant1"); err != nil {
+		t.Fatalf("DeleteTenant failed: %v", err)
+	}
+	if _, err := session.GetTenant("tenant1"); statusOf(err) != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %v", err)
+	}
+}
+
+func TestNetconf_Errors(t *testing.T) {
+	server, session := newNetconfSession(t, Options{})
+
+	server.FailNext(http.MethodGet, "openconfig-vlan:vlans/vlan=20", http.StatusBadRequest, "injected failure")
+	if _, err := session.GetVlan(20); err == nil || !strings.Contains(err.Error(), "injected failure") {
+		t.Fatalf("expected the injected failure as an rpc-error, got %v", err)
+	}
+
+	// RESTCONF token expiry does not end NETCONF sessions.
+	server.ExpireTokens()
+	if _, err := session.VlanConfig(vlanConfig(20, "twenty")); err != nil {
+		t.Fatalf("VlanConfig after token expiry failed: %v", err)
+	}
+
+	cfg := func(password string, hostKey ssh.PublicKey) *f5os.F5osConfig {
+		return &f5os.F5osConfig{
+			Host:     "127.0.0.1",
+			User:     "admin",
+			Password: password,
+			Retry:    &f5os.RetryPolicy{MaxAttempts: 1},
+			Netconf:  &f5os.NetconfConfig{Port: server.Port(), HostKeyCallback: ssh.FixedHostKey(hostKey)},
+		}
+	}
+	if _, err := f5os.NewSession(cfg("wrong", server.HostKey())); statusOf(err) != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 for a wrong password, got %v", err)
+	}
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	other, _ := ssh.NewPublicKey(pub)
+	if _, err := f5os.NewSession(cfg("admin", other)); err == nil || !strings.Contains(err.Error(), "host key") {
+		t.Fatalf("expected a host key mismatch, got %v", err)
+	}
+	token := cfg("admin", server.HostKey())
+	token.Token = "abc"
+	if _, err := f5os.NewSession(token); err == nil || !strings.Contains(err.Error(), "auth token") {
+		t.Fatalf("expected a token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+const testRestconfConflict = `{"ietf-restconf:errors":{"error":[` +
+	`{"error-type":"application","error-tag":"invalid-value","error-path":"/f5-tenants:tenants/tenant[name='t1']/config/vcpu-cores-per-node","error-message":"vcpu-cores-per-node must be even"},` +
+	`{"error-type":"application","error-tag":"data-exists","error-path":"/f5-tenants:tenants/tenant[name='t1']","error-message":"object already exists"}]}}`
+
+// TestAPIError_DoRequest verifies that an HTTP error from doRequest is an
+// *f5os.APIError carrying every RESTCONF error plus the request details,
+// while its text remains the first error-message.
+func TestAPIError_DoRequest(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(testRestconfConflict))
+	})
+	defer backend.Close()
+	session := newRetrySession(t, backend.URL, nil)
+
+	_, err := session.PatchRequest("/f5-tenants:tenants", []byte(`{}`))
+	var apiErr *f5os.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *f5os.APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusConflict || apiErr.Status != "409 Conflict" {
+		t.Fatalf("unexpected status %d %q", apiErr.StatusCode, apiErr.Status)
+	}
+	if apiErr.Method != "PATCH" || !strings.HasSuffix(apiErr.URI, "/f5-tenants:tenants") {
+		t.Fatalf("unexpected request %s %s", apiErr.Method, apiErr.URI)
+	}
+	if len(apiErr.Errors) != 2 || apiErr.Errors[1].Tag != "data-exists" || !apiErr.HasTag("invalid-value") {
+		t.Fatalf("expected both RESTCONF errors, got %+v", apiErr.Errors)
+	}
+	if err.Error() != "vcpu-cores-per-node must be even" {
+		t.Fatalf("expected the first error-message as the error text, got %q", err)
+	}
+}
+
+// TestAPIError_TenantRequest verifies that tenant calls keep their JSON
+// error text while exposing the underlying *f5os.APIError.
+func TestAPIError_TenantRequest(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(testRestconfConflict))
+	})
+	defer backend.Close()
+	session := newRetrySession(t, backend.URL, nil)
+
+	_, err := session.PostTenantRequest("/f5-tenants:tenants", []byte(`{}`))
+	if !strings.Contains(err.Error(), `"status":"400 Bad Request"`) {
+		t.Fatalf("expected the historical JSON error text, got %q", err)
+	}
+	var apiErr *f5os.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || len(apiErr.Errors) != 2 {
+		t.Fatalf("expected a 400 *f5os.APIError with two errors, got %v", err)
+	}
+
+	_, err = session.GetTenant("t1")
+	if !strings.Contains(err.Error(), "Tenant (t1) not found") {
+		t.Fatalf("expected GetTenant's not found text, got %q", err)
+	}
+	if !errors.As(err, &apiErr) || apiErr.Method != "GET" {
+		t.Fatalf("expected GetTenant to wrap the *f5os.APIError, got %v", err)
+	}
+}
+
+// TestUnitErrorPathAttribute verifies that RESTCONF error-paths resolve to
+// the Terraform attribute that configures the offending node.
+func TestUnitErrorPathAttribute(t *testing.T) {
+	cases := []struct {
+		name      string
+		attrs     map[string]path.Path
+		errorPath string
+		want      string
+	}{
+		{"renamed leaf", tenantErrorAttributes, "/f5-tenants:tenants/tenant[name='t1']/config/vcpu-cores-per-node", "cpu_cores"},
+		{"kebab leaf", tenantErrorAttributes, "/f5-tenants:tenants/tenant[name='t1']/config/running-state", "running_state"},
+		{"parent qualified", partitionErrorAttributes, "/f5-system-partition:partitions/partition[name='p1']/config/mgmt-ip/ipv4/gateway", "ipv4_mgmt_gateway"},
+		{"list key with slash", vlanErrorAttributes, "/openconfig-vlan:vlans/vlan[name='a/b']/config/vlan-id", "vlan_id"},
+		{"nearest ancestor", dnsErrorAttributes, "/openconfig-system:system/dns/servers/server[address='10.0.0.1']/config/port", "dns_servers"},
+		{"unmapped", tenantErrorAttributes, "/f5-tenants:tenants/tenant[name='t1']", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := &f5os.APIError{Errors: []f5os.RestconfError{{Path: tc.errorPath}}}
+			p, ok := errorPathAttribute(err, tc.attrs)
+			if tc.want == "" {
+				if ok {
+					t.Fatalf("expected no attribute, got %s", p)
+				}
+				return
+			}
+			if !ok || !p.Equal(path.Root(tc.want)) {
+				t.Fatalf("expected %s, got %s (ok=%v)", tc.want, p, ok)
+			}
+		})
+	}
+}
+
+// TestUnitErrorPathAttributeRequestURI verifies that an error without an
+// error-path resolves through the path of the request that failed.
+func TestUnitErrorPathAttributeRequestURI(t *testing.T) {
+	err := &f5os.APIError{
+		StatusCode: 400,
+		URI:        "https://10.0.0.1:8888/restconf/data/openconfig-system:system/aaa/authentication/f5-system-aaa:users/f5-system-aaa:user=bob/f5-system-aaa:config/f5-system-aaa:set-password",
+		Errors:     []f5os.RestconfError{{Tag: "operation-failed", Message: "BAD PASSWORD: it is based on a dictionary word"}},
+	}
+	p, ok := errorPathAttribute(err, userErrorAttributes)
+	if !ok || !p.Equal(path.Root("password")) {
+		t.Fatalf("expected password, got %s (ok=%v)", p, ok)
+	}
+
+	err.URI = "https://10.0.0.1:8888/restconf/data/openconfig-system:system/aaa/authentication/f5-system-aaa:users"
+	if p, ok := errorPathAttribute(err, userErrorAttributes); ok {
+		t.Fatalf("expected no attribute for the users collection, got %s", p)
+	}
+}
+
+// TestUnitAddClientErrorDiagnostic verifies that mapped errors become
+// attribute diagnostics and anything else stays a general error.
+func TestUnitAddClientErrorDiagnostic(t *testing.T) {
+	apiErr := &f5os.APIError{Errors: []f5os.RestconfError{{Path: "/openconfig-vlan:vlans/vlan[vlan-id='5']/config/name"}}}
+
+	var diags diag.Diagnostics
+	addClientErrorDiagnostic(&diags, vlanErrorAttributes, apiErr, "F5OS Client Error:", "bad name")
+	withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("name")) {
+		t.Fatalf("expected an attribute diagnostic on name, got %#v", diags.Errors()[0])
+	}
+
+	diags = nil
+	addClientErrorDiagnostic(&diags, vlanErrorAttributes, errors.New("connection refused"), "F5OS Client Error:", "down")
+	if _, ok := diags.Errors()[0].(diag.DiagnosticWithPath); ok {
+		t.Fatal("expected a general diagnostic for a non-API error")
+	}
+}
@@ -105,3 +105,35 @@ func (apm *boolDefaultValuePlanModifier) PlanModifyBool(ctx context.Context, req
 	}
 	res.PlanValue = apm.DefaultValue
 }
+
+// SetUseStateForUnknown keeps the prior state value of a computed Set
+// attribute that is not configured, instead of planning it as unknown
+func SetUseStateForUnknown() planmodifier.Set {
+	return setUseStateForUnknownPlanModifier{}
+}
+
+type setUseStateForUnknownPlanModifier struct{}
+
+var _ planmodifier.Set = setUseStateForUnknownPlanModifier{}
+
+func (apm setUseStateForUnknownPlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm setUseStateForUnknownPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return "Once set, the value of this attribute in state will not change unless it is configured."
+}
+
+func (apm setUseStateForUnknownPlanModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, res *planmodifier.SetResponse) {
+	// Nothing to keep on create
+	if req.StateValue.IsNull() {
+		return
+	}
+
+	// A known plan value was configured or set by an earlier modifier,
+	// and an unknown configuration value must stay unknown.
+	if !req.PlanValue.IsUnknown() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	res.PlanValue = req.StateValue
+}
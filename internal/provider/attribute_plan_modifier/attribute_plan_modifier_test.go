@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -337,3 +338,32 @@ func TestPlanModifyBoolAppliesDefaultWhenUnknown(t *testing.T) {
 		t.Errorf("expected default true, got %v", resp.PlanValue.ValueBool())
 	}
 }
+
+// ---------------------------------------------------------------------------
+// SetUseStateForUnknown
+// ---------------------------------------------------------------------------
+
+func TestPlanModifySetUseStateForUnknown(t *testing.T) {
+	pm := SetUseStateForUnknown()
+	ctx := context.Background()
+	state := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("vw1")})
+	empty := types.SetValueMust(types.StringType, []attr.Value{})
+
+	for name, tc := range map[string]struct {
+		config, plan, state, want types.Set
+	}{
+		"not configured":    {types.SetNull(types.StringType), types.SetUnknown(types.StringType), state, state},
+		"configured empty":  {empty, empty, state, empty},
+		"create":            {types.SetNull(types.StringType), types.SetUnknown(types.StringType), types.SetNull(types.StringType), types.SetUnknown(types.StringType)},
+		"unknown in config": {types.SetUnknown(types.StringType), types.SetUnknown(types.StringType), state, types.SetUnknown(types.StringType)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := planmodifier.SetRequest{ConfigValue: tc.config, PlanValue: tc.plan, StateValue: tc.state}
+			resp := &planmodifier.SetResponse{PlanValue: req.PlanValue}
+			pm.PlanModifySet(ctx, req, resp)
+			if !resp.PlanValue.Equal(tc.want) {
+				t.Errorf("expected %s, got %s", tc.want, resp.PlanValue)
+			}
+		})
+	}
+}
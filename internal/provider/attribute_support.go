@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// attributeRequirement is what a device must offer to accept a schema
+// attribute: a YANG module, and optionally one of its features, listed in
+// the device's ietf-yang-library, and the first F5OS release that has it.
+// The release decides when the module is not set or the library cannot
+// be read, and is named in the error either way.
+type attributeRequirement struct {
+	Module  string
+	Feature string
+	Since   string
+}
+
+// attributeRequirements maps resource type names to the attributes that
+// only some devices accept, keyed by their dotted path in the resource
+// schema. Resources consult it from ValidateConfig and ModifyPlan, so an
+// unsupported attribute is rejected at plan time rather than by the
+// device during apply, and again through checkAttributeSupport before
+// writing, for plans made while the device could not be reached.
+//
+// It is the only record of which attributes depend on the device: only
+// the resources listed here have such attributes, so only they call
+// validateAttributeSupport. A resource that gains one adds it here and
+// calls validateAttributeSupport from ValidateConfig and ModifyPlan.
+var attributeRequirements = map[string]map[string]attributeRequirement{
+	"f5os_auth": {
+		"password_policy.max_letter_repeat":   {Since: "1.7.0"},
+		"password_policy.max_sequence_repeat": {Since: "1.7.0"},
+		"password_policy.max_class_repeat":    {Since: "1.7.0"},
+		"password_policy.min_days":            {Since: "2.0.0"},
+		"password_policy.remember":            {Since: "2.0.0"},
+		"password_policy.warn_age":            {Since: "2.0.0"},
+		"login_policy":                        {Module: "f5-openconfig-aaa-login-policy", Since: "2.0.0"},
+		"ldap":                                {Module: "f5-openconfig-aaa-ldap", Since: "2.0.0"},
+	},
+	"f5os_interface": {
+		"description": {Since: "2.0.0"},
+	},
+	"f5os_ntp_server": {
+		"association_type": {Since: "2.0.0"},
+		"version":          {Since: "2.0.0"},
+		"port":             {Since: "2.0.0"},
+	},
+	"f5os_tenant": {
+		"max_nodes":        {Since: "2.0.0"},
+		"virtual_wires":    {Module: "f5-tenant-vwire", Since: "1.5.0"},
+		"mac_ndi_set":      {Since: "1.7.0"},
+		"ha_state":         {Since: "1.7.0"},
+		"floating_address": {Since: "1.7.0"},
+		"trust_mode":       {Since: "1.7.0"},
+		"reserved_cpus":    {Since: "1.7.0"},
+	},
+	"f5os_tls_cert_key": {
+		"certificate":              {Since: "2.0.0"},
+		"key":                      {Since: "2.0.0"},
+		"state_certificate":        {Since: "2.0.0"},
+		"subject_alternative_name": {Since: "1.8.0"},
+	},
+}
+
+// validateAttributeSupport adds an "Unsupported attribute" error for each
+// attribute of typeName that is set in config but not supported by the
+// device behind client. It does nothing without a client, as when
+// Terraform validates before configuring the provider, or when the device
+// cannot be reached; Create and Update still check before writing, with
+// checkAttributeSupport.
+func validateAttributeSupport(ctx context.Context, client *f5ossdk.F5os, typeName string, config tfsdk.Config, diags *diag.Diagnostics) {
+	requirements := attributeRequirements[typeName]
+	if client == nil || len(requirements) == 0 || config.Raw.IsNull() {
+		return
+	}
+	var set []string
+	for name := range requirements {
+		if attributeSet(config, name) {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		return
+	}
+	client = client.WithContext(ctx)
+	if client.Connect() != nil {
+		return
+	}
+	sort.Strings(set)
+	checkAttributeSupport(ctx, client, typeName, diags, set...)
+}
+
+// checkAttributeSupport adds an "Unsupported attribute" error for each of
+// names, attributes of typeName the configuration sets, that the device
+// behind client does not support. Write paths call it before building a
+// payload, so an attribute is never dropped or sent to a device that
+// would reject it.
+func checkAttributeSupport(ctx context.Context, client *f5ossdk.F5os, typeName string, diags *diag.Diagnostics, names ...string) {
+	client = client.WithContext(ctx)
+	for _, name := range names {
+		req, ok := attributeRequirements[typeName][name]
+		if !ok {
+			continue
+		}
+		if supported, reason := req.supportedBy(client); !supported && reason != "" {
+			diags.AddAttributeError(attributePath(name), "Unsupported attribute",
+				fmt.Sprintf("%s is not supported on F5OS versions below %s: %s. Remove %s from the configuration or target an F5OS %s or later device.",
+					name, req.Since, reason, name, req.Since))
+		}
+	}
+}
+
+// attributeSupported reports whether the device behind client accepts the
+// attribute name of typeName. Attributes without a requirement, and
+// devices that do not tell, are taken to accept it.
+func attributeSupported(ctx context.Context, client *f5ossdk.F5os, typeName, name string) bool {
+	req, ok := attributeRequirements[typeName][name]
+	if !ok {
+		return true
+	}
+	supported, _ := req.supportedBy(client.WithContext(ctx))
+	return supported
+}
+
+// supportedBy reports whether the device behind client meets r and, when
+// it does not, why. The reason is empty when neither the YANG library nor
+// the device version tell.
+func (r attributeRequirement) supportedBy(client *f5ossdk.F5os) (bool, string) {
+	version := client.Version()
+	if r.Module != "" {
+		if ok, err := client.Supports(r.Module, r.Feature); err == nil {
+			switch {
+			case ok:
+				return true, ""
+			case r.Feature != "":
+				return false, fmt.Sprintf("the device (F5OS %s) does not advertise the %s feature of the %s YANG module", version, r.Feature, r.Module)
+			}
+			return false, fmt.Sprintf("the device (F5OS %s) does not implement the %s YANG module", version, r.Module)
+		}
+	}
+	if version == "" || r.Since == "" {
+		return true, ""
+	}
+	if platformVersionAtLeast(version, "v"+r.Since) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("the device runs F5OS %s", version)
+}
+
+// attributeSet reports whether the attribute at the dotted path name has
+// a known, non-null value in config. An attribute inside an unset object
+// is not set.
+func attributeSet(config tfsdk.Config, name string) bool {
+	p := tftypes.NewAttributePath()
+	for _, step := range strings.Split(name, ".") {
+		p = p.WithAttributeName(step)
+	}
+	v, _, err := tftypes.WalkAttributePath(config.Raw, p)
+	if err != nil {
+		return false
+	}
+	value, ok := v.(tftypes.Value)
+	return ok && value.IsKnown() && !value.IsNull()
+}
+
+// attributePath converts a dotted attribute name to a path.
+func attributePath(name string) path.Path {
+	steps := strings.Split(name, ".")
+	p := path.Root(steps[0])
+	for _, step := range steps[1:] {
+		p = p.AtName(step)
+	}
+	return p
+}
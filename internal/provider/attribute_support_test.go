@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+// attributeSupportConfig returns a configuration for r with attrs set and
+// every other attribute null. A nested attribute is given as a map of its
+// own attributes.
+func attributeSupportConfig(t *testing.T, r fwresource.Resource, attrs map[string]interface{}) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	return tfsdk.Config{Schema: schemaResp.Schema, Raw: attributeSupportValue(objType, attrs)}
+}
+
+func attributeSupportValue(objType tftypes.Object, attrs map[string]interface{}) tftypes.Value {
+	vals := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+	for name, typ := range objType.AttributeTypes {
+		switch v := attrs[name].(type) {
+		case tftypes.Value:
+			vals[name] = v
+		case map[string]interface{}:
+			vals[name] = attributeSupportValue(typ.(tftypes.Object), v)
+		default:
+			vals[name] = tftypes.NewValue(typ, nil)
+		}
+	}
+	return tftypes.NewValue(objType, vals)
+}
+
+// attributeSupportPlan runs ModifyPlan for a create of config.
+func attributeSupportPlan(r fwresource.ResourceWithModifyPlan, config tfsdk.Config) *fwresource.ModifyPlanResponse {
+	ctx := context.Background()
+	plan := tfsdk.Plan{Schema: config.Schema, Raw: config.Raw}
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: config,
+		Plan:   plan,
+		State:  tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(config.Raw.Type(), nil)},
+	}, resp)
+	return resp
+}
+
+func attributeSupportSession(t *testing.T, url string) *f5os.F5os {
+	t.Helper()
+	session, err := f5os.NewLazySession(&f5os.F5osConfig{Host: url, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewLazySession failed: %v", err)
+	}
+	return session
+}
+
+// TestUnitAttributeSupportByVersion verifies that attributes gated on a
+// release are rejected at plan time on older devices only.
+func TestUnitAttributeSupportByVersion(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	for _, tc := range []struct {
+		version string
+		want    string
+	}{
+		{"1.8.0-17191", "max_nodes is not supported on F5OS versions below 2.0.0: the device runs F5OS 1.8.0-17191"},
+		{"2.0.0-3012", ""},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			server, err := f5osemu.NewServer(f5osemu.Options{Version: tc.version})
+			if err != nil {
+				t.Fatalf("NewServer failed: %v", err)
+			}
+			defer server.Close()
+			r := &TenantResource{client: attributeSupportSession(t, server.URL)}
+			config := attributeSupportConfig(t, r, map[string]interface{}{
+				"name":      tftypes.NewValue(tftypes.String, "tenant1"),
+				"max_nodes": tftypes.NewValue(tftypes.Number, 2),
+			})
+			resp := attributeSupportPlan(r, config)
+			if tc.want == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), tc.want) {
+				t.Fatalf("expected %q, got %v", tc.want, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// TestUnitAttributeSupportByModule verifies that the YANG library decides
+// for attributes gated on a module, ahead of the version.
+func TestUnitAttributeSupportByModule(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	e, err := f5osemu.New(f5osemu.Options{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	config := func(r *AuthResource) tfsdk.Config {
+		return attributeSupportConfig(t, r, map[string]interface{}{
+			"login_policy": map[string]interface{}{
+				"admin_role_limit": tftypes.NewValue(tftypes.Bool, true),
+			},
+		})
+	}
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+	r := &AuthResource{client: attributeSupportSession(t, server.URL)}
+	resp := attributeSupportPlan(r, config(r))
+	want := "login_policy is not supported on F5OS versions below 2.0.0: the device (F5OS 1.8.0-17191) does not implement the f5-openconfig-aaa-login-policy YANG module"
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), want) {
+		t.Fatalf("expected %q, got %v", want, resp.Diagnostics)
+	}
+
+	// A 1.8 device that lists the module, e.g. through an engineering
+	// build, accepts the attribute.
+	backported := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/ietf-yang-library:modules-state") {
+			w.Header().Set("Content-Type", "application/yang-data+json")
+			_, _ = w.Write([]byte(`{"ietf-yang-library:modules-state":{"module":[{"name":"f5-openconfig-aaa-login-policy","revision":"2024-01-01"}]}}`))
+			return
+		}
+		e.ServeHTTP(w, req)
+	}))
+	defer backported.Close()
+	r = &AuthResource{client: attributeSupportSession(t, backported.URL)}
+	if resp := attributeSupportPlan(r, config(r)); resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+// TestUnitAttributeSupportWrite verifies that the write paths consult the
+// same requirements as the plan-time check, so a module-gated attribute is
+// written to a device that lists the module whatever its version.
+func TestUnitAttributeSupportWrite(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	e, err := f5osemu.New(f5osemu.Options{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	lp := &loginPolicyModel{
+		AdminRoleLimit:          types.BoolValue(true),
+		RestconfMaxSessionLimit: types.Int64Null(),
+		SSHMaxSessionLimit:      types.Int64Null(),
+	}
+	for _, backport := range []bool{false, true} {
+		var patched bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case backport && strings.HasSuffix(req.URL.Path, "/ietf-yang-library:modules-state"):
+				w.Header().Set("Content-Type", "application/yang-data+json")
+				_, _ = w.Write([]byte(`{"ietf-yang-library:modules-state":{"module":[{"name":"f5-openconfig-aaa-login-policy","revision":"2024-01-01"}]}}`))
+			case strings.Contains(req.URL.Path, "login-policy") && req.Method == http.MethodPatch:
+				patched = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				e.ServeHTTP(w, req)
+			}
+		}))
+		r := &AuthResource{client: attributeSupportSession(t, server.URL)}
+		diags := r.writeLoginPolicy(context.Background(), lp)
+		server.Close()
+		if backport {
+			if diags.HasError() || !patched {
+				t.Fatalf("expected the login policy to be written to a device listing the module, got patched=%t %v", patched, diags)
+			}
+			continue
+		}
+		if !diags.HasError() || diags.Errors()[0].Summary() != "Unsupported attribute" {
+			t.Fatalf("expected an unsupported attribute error, got %v", diags)
+		}
+		if patched {
+			t.Fatal("expected no login policy write to a device without the module")
+		}
+	}
+}
+
+// TestUnitAttributeSupportFeature verifies that a requirement naming a
+// YANG feature needs the module to advertise it.
+func TestUnitAttributeSupportFeature(t *testing.T) {
+	library := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/ietf-yang-library:modules-state"):
+			_, _ = w.Write([]byte(`{"ietf-yang-library:modules-state":{"module":[{"name":"f5-tenants","feature":["appliance-mode"]}]}}`))
+		default:
+			w.Header().Set("X-Auth-Token", "token")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer library.Close()
+	client, err := f5os.NewLazySession(&f5os.F5osConfig{Host: library.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewLazySession failed: %v", err)
+	}
+	if ok, reason := (attributeRequirement{Module: "f5-tenants", Feature: "appliance-mode", Since: "1.5.0"}).supportedBy(client); !ok {
+		t.Fatalf("expected the feature to be supported, got %q", reason)
+	}
+	_, reason := (attributeRequirement{Module: "f5-tenants", Feature: "trust-mode", Since: "1.5.0"}).supportedBy(client)
+	if !strings.Contains(reason, "does not advertise the trust-mode feature of the f5-tenants YANG module") {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+}
+
+// TestUnitAttributeSupportWithoutDevice verifies that the check is skipped
+// before the provider is configured and when the device is not known.
+func TestUnitAttributeSupportWithoutDevice(t *testing.T) {
+	for name, client := range map[string]*f5os.F5os{
+		"unconfigured": nil,
+		"deferred":     f5os.NewDeferredSession(errors.New("host is not known yet")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &InterfaceResource{client: client}
+			config := attributeSupportConfig(t, r, map[string]interface{}{
+				"description": tftypes.NewValue(tftypes.String, "uplink"),
+			})
+			validate := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(context.Background(), fwresource.ValidateConfigRequest{Config: config}, validate)
+			if validate.Diagnostics.HasError() {
+				t.Fatalf("unexpected ValidateConfig diagnostics: %v", validate.Diagnostics)
+			}
+			if resp := attributeSupportPlan(r, config); resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected ModifyPlan diagnostics: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// vlanCassette is a cassette of runVlanLifecycle recorded against
+// newVlanDevice.
+var vlanCassette = filepath.Join("fixtures", "cassettes", "vlan_lifecycle.json")
+
+// newVlanDevice is a fake F5OS endpoint with a VLAN datastore, used to
+// record cassettes.
+func newVlanDevice() *httptest.Server {
+	var mu sync.Mutex
+	vlans := map[string]string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/yang-data+json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa"):
+			w.Header().Set("X-Auth-Token", "device-token-8f3a")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"openconfig-system:aaa":{"authentication":{"users":{"user":[{"username":"admin","config":{"password":"device-hash"}}]}}}}`))
+		case strings.Contains(r.URL.Path, "openconfig-platform:components"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/openconfig-vlan:vlans"):
+			var req f5os.F5ReqVlansConfig
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &req)
+			for _, v := range req.OpenconfigVlanVlans.Vlan {
+				vlans[fmt.Sprint(v.Config.VlanId)] = v.Config.Name
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(r.URL.Path, "/openconfig-vlan:vlans/vlan="):
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "=")+1:]
+			name, ok := vlans[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"ietf-restconf:errors":{"error":[{"error-type":"application","error-tag":"invalid-value","error-message":"uri keypath not found"}]}}`))
+				return
+			}
+			if r.Method == http.MethodDelete {
+				delete(vlans, id)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintf(w, `{"openconfig-vlan:vlan":[{"vlan-id":%s,"config":{"vlan-id":%s,"name":%q}}]}`, id, id, name)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func vlanConfig(id int, name string) *f5os.F5ReqVlansConfig {
+	cfg := &f5os.F5ReqVlansConfig{}
+	v := f5os.F5ReqVlanConfig{VlanId: fmt.Sprint(id)}
+	v.Config.VlanId = id
+	v.Config.Name = name
+	cfg.OpenconfigVlanVlans.Vlan = append(cfg.OpenconfigVlanVlans.Vlan, v)
+	return cfg
+}
+
+// runVlanLifecycle drives the calls f5os_vlan makes over a create,
+// update and destroy. reads is how many times the VLAN is read after
+// each write, standing in for Terraform's refreshes.
+func runVlanLifecycle(t *testing.T, session *f5os.F5os, reads int) {
+	t.Helper()
+	for _, name := range []string{"mytestvlan2", "mytestvlan3"} {
+		if _, err := session.VlanConfig(vlanConfig(400, name)); err != nil {
+			t.Fatalf("VlanConfig(%s) failed: %v", name, err)
+		}
+		for i := 0; i < reads; i++ {
+			vlan, err := session.GetVlan(400)
+			if err != nil {
+				t.Fatalf("GetVlan failed: %v", err)
+			}
+			if got := vlan.OpenconfigVlanVlan[0].Config.Name; got != name {
+				t.Fatalf("expected VLAN name %q, got %q", name, got)
+			}
+		}
+	}
+	if err := session.DeleteVlan(400); err != nil {
+		t.Fatalf("DeleteVlan failed: %v", err)
+	}
+	for i := 0; i < reads; i++ {
+		if _, err := session.GetVlan(400); err == nil || !strings.Contains(err.Error(), "404") {
+			t.Fatalf("expected a 404 after delete, got %v", err)
+		}
+	}
+}
+
+// TestCassette_RecordRedacts verifies that recording captures every
+// exchange without the credentials or the device's token.
+func TestCassette_RecordRedacts(t *testing.T) {
+	device := newVlanDevice()
+	defer device.Close()
+
+	file := filepath.Join(t.TempDir(), "vlan.json")
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:         device.URL,
+		User:         "admin",
+		Password:     "admin-secret",
+		CassetteFile: file,
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	runVlanLifecycle(t, session, 1)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	for _, secret := range []string{"admin-secret", "device-token-8f3a", "device-hash", device.URL} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette leaks %q:\n%s", secret, data)
+		}
+	}
+
+	// The recording replays the same lifecycle it captured.
+	cassette, err := f5os.LoadCassette(file)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	replay, err := f5os.NewSession(&f5os.F5osConfig{Host: "replay.invalid", User: "admin", Password: "x", RoundTripper: cassette})
+	if err != nil {
+		t.Fatalf("NewSession over the cassette failed: %v", err)
+	}
+	runVlanLifecycle(t, replay, 1)
+	if u := cassette.Unmatched(); len(u) > 0 {
+		t.Fatalf("unexpected unmatched requests: %v", u)
+	}
+}
+
+// TestCassette_RecordLongSession verifies that the cassette on disk is
+// complete after every interaction of a long recording.
+func TestCassette_RecordLongSession(t *testing.T) {
+	device := newVlanDevice()
+	defer device.Close()
+
+	file := filepath.Join(t.TempDir(), "long.json")
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: device.URL, User: "admin", Password: "admin", CassetteFile: file})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	count := func() int {
+		t.Helper()
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("reading cassette: %v", err)
+		}
+		var recorded struct {
+			Version      int               `json:"version"`
+			Interactions []json.RawMessage `json:"interactions"`
+		}
+		if err := json.Unmarshal(data, &recorded); err != nil || recorded.Version != 1 {
+			t.Fatalf("cassette is not a complete version 1 document (%v):\n%s", err, data)
+		}
+		return len(recorded.Interactions)
+	}
+	before := count()
+	for i := 0; i < 25; i++ {
+		if _, err := session.GetVlan(400); err == nil {
+			t.Fatal("expected a 404 for a VLAN that was never created")
+		}
+		if got := count(); got <= before {
+			t.Fatalf("expected read %d to be recorded, still %d interactions", i+1, got)
+		} else {
+			before = got
+		}
+	}
+
+	cassette, err := f5os.LoadCassette(file)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	replay, err := f5os.NewSession(&f5os.F5osConfig{Host: "replay.invalid", User: "admin", Password: "x", RoundTripper: cassette})
+	if err != nil {
+		t.Fatalf("NewSession over the cassette failed: %v", err)
+	}
+	if _, err := replay.GetVlan(400); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the recorded 404, got %v", err)
+	}
+}
+
+// TestCassette_RecordContinuesCassette verifies that a cassette left by
+// an earlier plugin process, such as the one that ran the plan, is
+// continued rather than replaced, so it replays both processes' calls.
+func TestCassette_RecordContinuesCassette(t *testing.T) {
+	device := newVlanDevice()
+	defer device.Close()
+
+	record := func(file string) {
+		session, err := f5os.NewSession(&f5os.F5osConfig{Host: device.URL, User: "admin", Password: "admin", CassetteFile: file})
+		if err != nil {
+			t.Fatalf("NewSession failed: %v", err)
+		}
+		runVlanLifecycle(t, session, 1)
+	}
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plan.json")
+	record(plan)
+	data, err := os.ReadFile(plan)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	// A path this process has not recorded to stands in for the file of
+	// the earlier process.
+	apply := filepath.Join(dir, "apply.json")
+	if err := os.WriteFile(apply, data, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	record(apply)
+
+	cassette, err := f5os.LoadCassette(apply)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		replay, err := f5os.NewSession(&f5os.F5osConfig{Host: "replay.invalid", User: "admin", Password: "x", RoundTripper: cassette})
+		if err != nil {
+			t.Fatalf("NewSession over the cassette failed: %v", err)
+		}
+		runVlanLifecycle(t, replay, 1)
+	}
+	if u := cassette.Unmatched(); len(u) > 0 {
+		t.Fatalf("unexpected unmatched requests: %v", u)
+	}
+}
+
+// TestCassette_RecordRedactsXML verifies that secret leaves of XML bodies,
+// which are stored as text, are masked.
+func TestCassette_RecordRedactsXML(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/yang-data+xml")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<user><username>u1</username><config><password>device-secret</password><role>admin</role></config></user>`))
+	})
+	defer backend.Close()
+
+	file := filepath.Join(t.TempDir(), "xml.json")
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: backend.URL, User: "admin", Password: "admin", CassetteFile: file})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if _, err := session.GetRequest("/openconfig-system:system/aaa/authentication/users"); err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	var recorded struct {
+		Interactions []struct {
+			Response struct {
+				Body json.RawMessage `json:"body"`
+			} `json:"response"`
+		} `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &recorded); err != nil || len(recorded.Interactions) == 0 {
+		t.Fatalf("cassette is not a complete document (%v):\n%s", err, data)
+	}
+	var body string
+	_ = json.Unmarshal(recorded.Interactions[len(recorded.Interactions)-1].Response.Body, &body)
+	if strings.Contains(string(data), "device-secret") || !strings.Contains(body, `<password>REDACTED</password><role>admin</role>`) {
+		t.Fatalf("expected the XML password to be masked and the rest kept, got %s", body)
+	}
+}
+
+// TestCassette_ReplayVlanLifecycle replays the committed VLAN cassette
+// with more reads than were recorded, as a Terraform refresh would.
+func TestCassette_ReplayVlanLifecycle(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	cassette, err := f5os.LoadCassette(vlanCassette)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: "replay.invalid", User: "admin", Password: "admin", RoundTripper: cassette})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	runVlanLifecycle(t, session, 3)
+	if u := cassette.Unmatched(); len(u) > 0 {
+		t.Fatalf("unexpected unmatched requests: %v", u)
+	}
+}
+
+// TestCassette_ReportsUnmatched verifies that a request the cassette does
+// not hold fails clearly, both as a transport and behind a test server.
+func TestCassette_ReportsUnmatched(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	cassette, err := f5os.LoadCassette(vlanCassette)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: "replay.invalid", User: "admin", Password: "admin", RoundTripper: cassette})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+
+	_, err = session.VlanConfig(vlanConfig(401, "other"))
+	var unmatched *f5os.UnmatchedRequestError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("expected *f5os.UnmatchedRequestError, got %T: %v", err, err)
+	}
+	if unmatched.Method != http.MethodPatch || len(unmatched.Recorded) == 0 || !strings.Contains(err.Error(), `"vlan-id":401`) {
+		t.Fatalf("expected the request body and the recorded alternatives, got %v", err)
+	}
+
+	server := httptest.NewServer(cassette)
+	defer server.Close()
+	served, err := f5os.NewSession(&f5os.F5osConfig{Host: server.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewSession against the replay server failed: %v", err)
+	}
+	_, err = served.GetRequest("/openconfig-lacp:lacp")
+	var apiErr *f5os.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented || !strings.Contains(err.Error(), "never recorded") {
+		t.Fatalf("expected a 501 naming the unrecorded URI, got %v", err)
+	}
+	// GET retries server errors, so the unrecorded read is listed once
+	// per attempt after the unrecorded write.
+	unmatchedList := cassette.Unmatched()
+	if len(unmatchedList) < 2 || !strings.Contains(unmatchedList[0], "PATCH") || !strings.Contains(unmatchedList[len(unmatchedList)-1], "openconfig-lacp:lacp") {
+		t.Fatalf("expected the write and the read to be listed as unmatched, got %v", unmatchedList)
+	}
+}
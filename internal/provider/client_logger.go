@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// clientLogSubsystem is the tflog subsystem the F5OS client logs to. Its
+// level can be set apart from the provider's with
+// TF_LOG_PROVIDER_F5OS_CLIENT.
+const clientLogSubsystem = "client"
+
+// clientLogger routes the client's log entries to the clientLogSubsystem
+// of the context it is bound to. WithContext binds it to the context of
+// each resource operation, so entries carry that resource's fields,
+// including its f5os_request_id.
+type clientLogger struct {
+	ctx context.Context
+}
+
+var _ f5ossdk.ContextLogger = clientLogger{}
+
+// newClientLogger returns a clientLogger bound to ctx.
+func newClientLogger(ctx context.Context) clientLogger {
+	return clientLogger{ctx: tflog.NewSubsystem(ctx, clientLogSubsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_F5OS", clientLogSubsystem),
+		tflog.WithRootFields(),
+		// Report the client's call site rather than this file.
+		tflog.WithAdditionalLocationOffset(1),
+	)}
+}
+
+func (l clientLogger) WithContext(ctx context.Context) f5ossdk.Logger {
+	return newClientLogger(ctx)
+}
+
+func (l clientLogger) Trace(msg string, args ...interface{}) {
+	tflog.SubsystemTrace(l.ctx, clientLogSubsystem, msg, f5ossdk.Fields(args))
+}
+
+func (l clientLogger) Debug(msg string, args ...interface{}) {
+	tflog.SubsystemDebug(l.ctx, clientLogSubsystem, msg, f5ossdk.Fields(args))
+}
+
+func (l clientLogger) Info(msg string, args ...interface{}) {
+	tflog.SubsystemInfo(l.ctx, clientLogSubsystem, msg, f5ossdk.Fields(args))
+}
+
+func (l clientLogger) Warn(msg string, args ...interface{}) {
+	tflog.SubsystemWarn(l.ctx, clientLogSubsystem, msg, f5ossdk.Fields(args))
+}
+
+func (l clientLogger) Error(msg string, args ...interface{}) {
+	tflog.SubsystemError(l.ctx, clientLogSubsystem, msg, f5ossdk.Fields(args))
+}
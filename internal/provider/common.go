@@ -1,12 +1,216 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
 	"golang.org/x/mod/semver"
 )
 
+// durationValidator checks that a string attribute is a positive Go
+// duration such as "500ms", "10s" or "2m".
+type durationValidator struct{}
+
+var _ validator.String = durationValidator{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "Ensures the value is a positive duration such as '500ms', '10s' or '2m'."
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures the value is a positive duration such as `500ms`, `10s` or `2m`."
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	input := req.ConfigValue.ValueString()
+	if d, err := time.ParseDuration(input); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration Value",
+			fmt.Sprintf("The value '%s' is not a positive duration. Use a number with a unit suffix, for example '500ms', '10s' or '2m'.", input),
+		)
+	}
+}
+
+// resourcePollInterval returns how long a resource waits between status
+// reads: its own interval attribute when set, otherwise the provider's
+// poll_interval, otherwise def.
+func resourcePollInterval(own types.String, client *f5ossdk.F5os, def time.Duration) time.Duration {
+	if !own.IsNull() && !own.IsUnknown() {
+		// The value is checked by durationValidator.
+		if d, err := time.ParseDuration(own.ValueString()); err == nil && d > 0 {
+			return d
+		}
+	}
+	if client != nil && client.PollInterval > 0 {
+		return client.PollInterval
+	}
+	return def
+}
+
+// pauseContext sleeps for d as part of the wait named by operation, which
+// began at start with the given timeout budget. If ctx ends first it
+// returns an *f5ossdk.WaitCanceledError recording the budget that was left.
+func pauseContext(ctx context.Context, d time.Duration, operation string, start time.Time, timeout time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return &f5ossdk.WaitCanceledError{
+			Operation: operation,
+			Elapsed:   time.Since(start),
+			Timeout:   timeout,
+			Err:       ctx.Err(),
+		}
+	case <-t.C:
+		return nil
+	}
+}
+
+// restconfAttributes maps RESTCONF node names to the Terraform attributes
+// they configure, for addClientErrorDiagnostic. Each attribute in names is
+// registered under its kebab-case node name (cpu_cores as cpu-cores).
+// overrides adds nodes whose names differ from the attribute; a key may be
+// a bare node name or "parent/node" for nodes that are ambiguous alone.
+func restconfAttributes(overrides map[string]string, names ...string) map[string]path.Path {
+	attrs := make(map[string]path.Path, len(names)+len(overrides))
+	for _, name := range names {
+		attrs[strings.ReplaceAll(name, "_", "-")] = path.Root(name)
+	}
+	for node, name := range overrides {
+		attrs[node] = path.Root(name)
+	}
+	return attrs
+}
+
+// restconfPathNodes splits a RESTCONF error-path such as
+// "/f5-tenants:tenants/tenant[name='t1']/config/vlans" into its node names
+// without module prefixes or list keys: tenants, tenant, config, vlans.
+func restconfPathNodes(errorPath string) []string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range errorPath {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	var nodes []string
+	for _, node := range strings.Split(b.String(), "/") {
+		// A request path gives list keys as "tenant=t1".
+		if i := strings.Index(node, "="); i >= 0 {
+			node = node[:i]
+		}
+		if i := strings.LastIndex(node, ":"); i >= 0 {
+			node = node[i+1:]
+		}
+		if node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// restconfRequestPath returns the part of a request URL below the
+// RESTCONF datastore root, e.g.
+// "/f5-tenants:tenants/tenant=t1" for
+// "https://host:8888/restconf/data/f5-tenants:tenants/tenant=t1".
+func restconfRequestPath(uri string) string {
+	for _, root := range []string{"/restconf/data", "/api/data"} {
+		if i := strings.Index(uri, root); i >= 0 {
+			uri = uri[i+len(root):]
+			break
+		}
+	}
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		uri = uri[:i]
+	}
+	return uri
+}
+
+// errorPathAttribute returns the attribute named by the first RESTCONF
+// error-path in err that attrs knows about. The path is matched from its
+// deepest node upwards, so an error on a leaf inside a container maps to
+// the attribute for the container when the leaf itself is not listed.
+// When the device reported no error-path, the path of the request is
+// used instead, e.g. for an action such as set-password that fails as a
+// whole.
+func errorPathAttribute(err error, attrs map[string]path.Path) (path.Path, bool) {
+	var apiErr *f5ossdk.APIError
+	if !errors.As(err, &apiErr) {
+		return path.Empty(), false
+	}
+	var errorPaths []string
+	for _, re := range apiErr.Errors {
+		if re.Path != "" {
+			errorPaths = append(errorPaths, re.Path)
+		}
+	}
+	if len(errorPaths) == 0 {
+		errorPaths = append(errorPaths, restconfRequestPath(apiErr.URI))
+	}
+	for _, errorPath := range errorPaths {
+		nodes := restconfPathNodes(errorPath)
+		for i := len(nodes) - 1; i >= 0; i-- {
+			if i > 0 {
+				if p, ok := attrs[nodes[i-1]+"/"+nodes[i]]; ok {
+					return p, true
+				}
+			}
+			if p, ok := attrs[nodes[i]]; ok {
+				return p, true
+			}
+		}
+	}
+	return path.Empty(), false
+}
+
+// addClientErrorDiagnostic records a failed client call. When the device
+// reported an error-path that maps to one of attrs, the error is attached
+// to that attribute so Terraform points at the offending field; otherwise
+// it is added as a general error. A login failure on the session's first
+// request gets the dedicated summary and hint of sessionErrorDiagnostic.
+func addClientErrorDiagnostic(diags *diag.Diagnostics, attrs map[string]path.Path, err error, summary, detail string) {
+	if sessionSummary, sessionDetail, ok := sessionErrorDiagnostic(err); ok {
+		diags.AddError(sessionSummary, sessionDetail)
+		return
+	}
+	if p, ok := errorPathAttribute(err, attrs); ok {
+		diags.AddAttributeError(p, summary, detail)
+		return
+	}
+	diags.AddError(summary, detail)
+}
+
+// rejectNetconfTransport fails the configuration of typeName when the
+// provider uses the NETCONF transport. typeName sends paths and payloads
+// of any model, and the client translates JSON to XML and back only for
+// the models of the dedicated resources; for others lists, keys and leaf
+// types would come out wrong.
+func rejectNetconfTransport(client *f5ossdk.F5os, typeName string, diags *diag.Diagnostics) {
+	if client == nil || client.Netconf == nil {
+		return
+	}
+	diags.AddError("Unsupported Transport",
+		fmt.Sprintf("%s is not supported with the provider's transport set to \"netconf\". Use a provider with transport \"restconf\" for it.", typeName))
+}
+
 // platformVersionAtLeast returns true if the device platform version is >= the
 // given minimum version. Both the platform version (e.g., "1.8.3-23453") and
 // the minimum (e.g., "v1.7") are normalized to semver for comparison.
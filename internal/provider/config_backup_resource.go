@@ -5,12 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -20,6 +19,11 @@ import (
 )
 
 var _ resource.Resource = &CfgBackupResource{}
+var _ resource.ResourceWithUpgradeState = &CfgBackupResource{}
+
+// configBackupDefaultWait is how long create waits for the backup export
+// when neither the resource nor the provider set a limit.
+const configBackupDefaultWait = 150 * time.Second
 
 // var _ resource.ResourceWithImportState = &CfgBackupResource{}
 
@@ -38,7 +42,7 @@ type CfgBackupResourceModel struct {
 	RemotePassword types.String `tfsdk:"remote_password"`
 	RemotePath     types.String `tfsdk:"remote_path"`
 	Protocol       types.String `tfsdk:"protocol"`
-	Timeout        types.Int64  `tfsdk:"timeout"`
+	Timeouts       types.Object `tfsdk:"timeouts"`
 	Id             types.String `tfsdk:"id"`
 }
 
@@ -79,15 +83,6 @@ func (r *CfgBackupResource) Schema(ctx context.Context, req resource.SchemaReque
 					stringvalidator.OneOf("scp", "https", "sftp"),
 				},
 			},
-			"timeout": schema.Int64Attribute{
-				MarkdownDescription: "The number of seconds to wait for config backup file export to finish. The value must be between 150 and 3600",
-				Optional:            true,
-				Default:             int64default.StaticInt64(150),
-				Computed:            true,
-				Validators: []validator.Int64{
-					int64validator.Between(150, 3600),
-				},
-			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Unique identifier for resource.",
 				Computed:            true,
@@ -96,6 +91,10 @@ func (r *CfgBackupResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock("the config backup file export to finish", configBackupDefaultWait),
+		},
+		Version: 1,
 	}
 }
 
@@ -115,8 +114,11 @@ func (r *CfgBackupResource) Create(ctx context.Context, req resource.CreateReque
 
 	name := data.Name.ValueString()
 	exportConfig := backupModelToExportConfig(data)
-	timeout := data.Timeout.ValueInt64()
-	_, err := r.client.CreateConfigBackup(name, timeout, exportConfig)
+	wait, diags := operationTimeout(ctx, data.Timeouts, "create", r.client, configBackupDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	_, err := r.client.WithContext(ctx).CreateConfigBackup(name, int64(timeoutSeconds(wait)), exportConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("failure while creating config backup, got error: %s", err))
 		return
@@ -137,7 +139,7 @@ func (r *CfgBackupResource) Read(ctx context.Context, req resource.ReadRequest,
 	tflog.Info(ctx, fmt.Sprintf("[READ] Reading Config Backups :%+v", data.Id.ValueString()))
 	name := data.Name.ValueString()
 
-	res, err := r.client.GetConfigBackup()
+	res, err := r.client.WithContext(ctx).GetConfigBackup()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Config Backups",
@@ -205,8 +207,13 @@ func (r *CfgBackupResource) Update(ctx context.Context, req resource.UpdateReque
 func (r *CfgBackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data *CfgBackupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "delete", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 	fileName := fmt.Sprintf("configs/%s", data.Name.ValueString())
-	err := r.client.DeleteConfigBackup(fileName)
+	err := r.client.WithContext(ctx).DeleteConfigBackup(fileName)
 
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("failure while destroying config backup, got error: %s", err))
@@ -214,6 +221,31 @@ func (r *CfgBackupResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 }
 
+// UpgradeState moves the integer `timeout` of schema version 0 into the
+// `timeouts` block.
+func (r *CfgBackupResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	return map[int64]resource.StateUpgrader{
+		0: timeoutsStateUpgrader(configBackupSchemaV0, schemaResp.Schema, int64(configBackupDefaultWait/time.Second), "create"),
+	}
+}
+
+// configBackupSchemaV0 is the shape of schema version 0, kept to read
+// state written by provider releases before 1.14.0.
+var configBackupSchemaV0 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"name":            schema.StringAttribute{Required: true},
+		"remote_host":     schema.StringAttribute{Required: true},
+		"remote_user":     schema.StringAttribute{Required: true},
+		"remote_password": schema.StringAttribute{Required: true, Sensitive: true},
+		"remote_path":     schema.StringAttribute{Required: true},
+		"protocol":        schema.StringAttribute{Required: true},
+		"timeout":         schema.Int64Attribute{Optional: true, Computed: true},
+		"id":              schema.StringAttribute{Computed: true},
+	},
+}
+
 func backupModelToExportConfig(model *CfgBackupResourceModel) f5ossdk.FileExport {
 	exportConfig := f5ossdk.FileExport{}
 	exportConfig.Insecure = ""
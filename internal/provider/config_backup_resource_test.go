@@ -155,7 +155,7 @@ func TestAccCfgBackupCreate(t *testing.T) {
 					resource.TestCheckResourceAttr("f5os_config_backup.test", "protocol", "https"),
 					resource.TestCheckResourceAttr("f5os_config_backup.test", "remote_user", "corpuser"),
 					resource.TestCheckResourceAttr("f5os_config_backup.test", "remote_password", "password"),
-					resource.TestCheckResourceAttr("f5os_config_backup.test", "timeout", "300"),
+					resource.TestCheckResourceAttr("f5os_config_backup.test", "timeouts.create", "300s"),
 					resource.TestCheckResourceAttr("f5os_config_backup.test", "id", name),
 					testAccCheckCfgBackupExists(client, name),
 				),
@@ -599,7 +599,9 @@ resource "f5os_config_backup" "test" {
   # 300s (vs. the schema default of 150s) absorbs transient slowness on
   # the shared upload target 10.145.42.244 that has caused the client's
   # "export operation timed out" retry loop to exit prematurely on CI.
-  timeout         = 300
+  timeouts {
+    create = "300s"
+  }
 }
 `
 
@@ -611,6 +613,8 @@ resource "f5os_config_backup" "test" {
   remote_password = "password"
   remote_path     = "/upload/upload_v2.php"
   protocol        = "https"
-  timeout         = 300
+  timeouts {
+    create = "300s"
+  }
 }
 `
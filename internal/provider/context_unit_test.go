@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// TestWithContext_CancelStopsPartitionWait verifies that a partition
+// deployment wait returns as soon as its context ends, rather than after
+// the poll interval, and reports the unused part of its timeout.
+func TestWithContext_CancelStopsPartitionWait(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"f5-system-partition:state":{"controllers":{"controller":[{"partition-status":"starting"}]}}}`))
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, nil)
+	session.PollInterval = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := session.WithContext(ctx).CheckPartitionState("p1", 600)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the wait to stop promptly, took %s", elapsed)
+	}
+	var waitErr *f5os.WaitCanceledError
+	if !errors.As(err, &waitErr) {
+		t.Fatalf("expected *f5os.WaitCanceledError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	if waitErr.Timeout != 600*time.Second {
+		t.Fatalf("expected a 600s budget, got %s", waitErr.Timeout)
+	}
+	if !strings.Contains(err.Error(), `partition "p1" deployment`) || !strings.Contains(err.Error(), "remaining") {
+		t.Fatalf("expected the operation and remaining budget in the error, got %q", err)
+	}
+}
+
+// TestWithContext_CancelStopsRetries verifies that a cancelled context
+// interrupts the backoff between retries of a single request.
+func TestWithContext_CancelStopsRetries(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:          5,
+		InitialBackoff:       time.Hour,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := session.WithContext(ctx).GetRequest("/openconfig-vlan:vlans")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the retry wait to stop promptly, took %s", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error to wrap context.Canceled, got %v", err)
+	}
+	if n := backend.hitCount(); n != 1 {
+		t.Fatalf("expected a single attempt before cancellation, got %d", n)
+	}
+}
+
+// TestWithContext_CancelStopsSessionRefreshRetries verifies that a
+// cancelled context ends the wait after a 401 without another attempt,
+// and that the error keeps the last failure.
+func TestWithContext_CancelStopsSessionRefreshRetries(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := session.WithContext(ctx).GetRequest("/openconfig-vlan:vlans")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error to wrap context.Canceled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "stopped after 1 attempts") || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Fatalf("expected the attempt count and the last error, got %v", err)
+	}
+	if n := backend.hitCount(); n != 1 {
+		t.Fatalf("expected a single attempt before cancellation, got %d", n)
+	}
+}
+
+// TestWithContext_SharesRefreshedToken verifies that a token refreshed
+// through a WithContext copy is seen by the session it was derived from.
+func TestWithContext_SharesRefreshedToken(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	var (
+		mu     sync.Mutex
+		logins int
+		valid  string
+	)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa") {
+			logins++
+			valid = fmt.Sprintf("token-%d", logins)
+			w.Header().Set("X-Auth-Token", valid)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"openconfig-system:aaa":{}}`))
+			return
+		}
+		if strings.Contains(r.URL.Path, "openconfig-platform:components") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Auth-Token") != valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, nil)
+	before := session.Token
+
+	// Invalidate the session's token so the next request must log in again.
+	mu.Lock()
+	valid = "revoked"
+	mu.Unlock()
+
+	if _, err := session.WithContext(context.Background()).GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if session.Token == before {
+		t.Fatalf("expected the parent session to see the refreshed token, still %q", session.Token)
+	}
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("parent GetRequest with the refreshed token failed: %v", err)
+	}
+	if logins != 2 {
+		t.Fatalf("expected exactly one re-login, got %d logins", logins)
+	}
+}
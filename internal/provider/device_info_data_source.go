@@ -258,7 +258,7 @@ func (d *DeviceInfoDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	for _, item := range gather_subsets {
 
 		if item == "interfaces" {
-			interfacesResp, err := d.client.GetInterfaceInfo()
+			interfacesResp, err := d.client.WithContext(ctx).GetInterfaceInfo()
 			if err != nil {
 				resp.Diagnostics.AddError("Error getting interface info", err.Error())
 				return
@@ -269,7 +269,7 @@ func (d *DeviceInfoDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 
 		if item == "vlans" {
-			vlansResp, err := d.client.GetVlansInfo()
+			vlansResp, err := d.client.WithContext(ctx).GetVlansInfo()
 			if err != nil {
 				resp.Diagnostics.AddError("Error getting vlans info", err.Error())
 				return
@@ -280,7 +280,7 @@ func (d *DeviceInfoDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 
 		if item == "controller_images" {
-			controllerImagesResp, err := d.client.GetControllerImagesInfo()
+			controllerImagesResp, err := d.client.WithContext(ctx).GetControllerImagesInfo()
 			if err != nil {
 				resp.Diagnostics.AddError("Error getting controller images info", err.Error())
 				return
@@ -291,7 +291,7 @@ func (d *DeviceInfoDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 
 		if item == "partition_images" {
-			partitionImagesResp, err := d.client.GetPartitionImagesInfo()
+			partitionImagesResp, err := d.client.WithContext(ctx).GetPartitionImagesInfo()
 			if err != nil {
 				resp.Diagnostics.AddError("Error getting partition images info", err.Error())
 				return
@@ -302,7 +302,7 @@ func (d *DeviceInfoDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 
 		if item == "tenant_images" {
-			tenantImagesResp, err := d.client.GetTenantImagesInfo()
+			tenantImagesResp, err := d.client.WithContext(ctx).GetTenantImagesInfo()
 			if err != nil {
 				resp.Diagnostics.AddError("Error getting tenant images info", err.Error())
 				return
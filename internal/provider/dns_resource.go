@@ -119,6 +119,13 @@ type DNSResource struct {
 }
 
 // NewDNSResource creates a new instance of the resource
+// dnsErrorAttributes maps DNS RESTCONF nodes to the attributes that set
+// them, so device errors are reported against the offending field.
+var dnsErrorAttributes = restconfAttributes(map[string]string{
+	"server": "dns_servers",
+	"search": "dns_domains",
+})
+
 func NewDNSResource() resource.Resource {
 	return &DNSResource{}
 }
@@ -246,7 +253,7 @@ func (r *DNSResource) Create(ctx context.Context, req resource.CreateRequest, re
 	// additive — it merges with existing config rather than replacing it.
 	// Without this step, pre-existing servers/domains would persist on the
 	// device and cause drift on the next refresh.
-	existing, err := r.client.ReadDNSConfig()
+	existing, err := r.client.WithContext(ctx).ReadDNSConfig()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"DNS Configuration Error",
@@ -268,7 +275,7 @@ func (r *DNSResource) Create(ctx context.Context, req resource.CreateRequest, re
 			"stale_servers": staleServers,
 			"stale_domains": staleDomains,
 		})
-		if err := r.client.DeleteDNSConfig(staleServers, staleDomains); err != nil {
+		if err := r.client.WithContext(ctx).DeleteDNSConfig(staleServers, staleDomains); err != nil {
 			resp.Diagnostics.AddError(
 				"DNS Configuration Error",
 				fmt.Sprintf("Failed to remove pre-existing DNS entries: %s", err),
@@ -278,8 +285,8 @@ func (r *DNSResource) Create(ctx context.Context, req resource.CreateRequest, re
 	}
 
 	// Call client to PATCH DNS config
-	if err := r.client.PatchDNSConfig(dnsServers, dnsDomains); err != nil {
-		resp.Diagnostics.AddError(
+	if err := r.client.WithContext(ctx).PatchDNSConfig(dnsServers, dnsDomains); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, dnsErrorAttributes, err,
 			"DNS Configuration Error",
 			fmt.Sprintf("Failed to configure DNS: %s", err),
 		)
@@ -324,7 +331,7 @@ func (r *DNSResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	tflog.Info(ctx, "Reading DNS configuration from F5OS")
 
 	// Fetch DNS config from F5OS API
-	config, err := r.client.ReadDNSConfig()
+	config, err := r.client.WithContext(ctx).ReadDNSConfig()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"DNS Read Error",
@@ -412,15 +419,15 @@ func (r *DNSResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	// Delete servers and domains that were removed from the config
 	removed := removedEntries(oldServers, dnsServers)
 	removedDoms := removedEntries(oldDomains, dnsDomains)
-	if err := r.client.DeleteDNSConfig(removed, removedDoms); err != nil {
+	if err := r.client.WithContext(ctx).DeleteDNSConfig(removed, removedDoms); err != nil {
 		resp.Diagnostics.AddError("DNS Update Error",
 			fmt.Sprintf("Failed to remove stale DNS entries: %s", err))
 		return
 	}
 
 	// Patch remaining / newly added entries
-	if err := r.client.PatchDNSConfig(dnsServers, dnsDomains); err != nil {
-		resp.Diagnostics.AddError(
+	if err := r.client.WithContext(ctx).PatchDNSConfig(dnsServers, dnsDomains); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, dnsErrorAttributes, err,
 			"DNS Update Error",
 			fmt.Sprintf("Failed to update DNS configuration: %s", err),
 		)
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+	"golang.org/x/crypto/ssh"
+)
+
+// TestUnitProviderConfigureEmulatedPlatforms verifies that Configure
+// detects each platform and version served by the emulator.
+func TestUnitProviderConfigureEmulatedPlatforms(t *testing.T) {
+	for _, tc := range []struct {
+		opts     f5osemu.Options
+		wantType string
+	}{
+		{f5osemu.Options{Platform: f5osemu.RSeries, Version: "1.8.0-17191"}, "r5900"},
+		{f5osemu.Options{Platform: f5osemu.VelosController, Version: "1.6.2-4421"}, "Velos Controller"},
+		{f5osemu.Options{Platform: f5osemu.VelosPartition, Version: "2.0.0-3012"}, "Velos Partition"},
+	} {
+		t.Run(string(tc.opts.Platform), func(t *testing.T) {
+			client, _ := testEmulatorClient(t, tc.opts)
+			if client.Platform() != tc.wantType || client.Version() != tc.opts.Version {
+				t.Fatalf("expected %s %s, got %s %s", tc.wantType, tc.opts.Version, client.Platform(), client.Version())
+			}
+		})
+	}
+}
+
+// TestUnitProviderConfigureLazyLogin verifies that Configure does not
+// contact the device, and that the platform is detected on first use and
+// then reused by every copy of the client.
+func TestUnitProviderConfigureLazyLogin(t *testing.T) {
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.VelosPartition, Version: "2.0.0-3012"})
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Fatalf("expected Configure not to contact the device, got %v", requests)
+	}
+
+	copied := client.WithContext(context.Background())
+	if copied.Version() != "2.0.0-3012" || client.Platform() != "Velos Partition" {
+		t.Fatalf("unexpected platform %q %q", client.Platform(), copied.Version())
+	}
+	detected := len(server.Requests())
+	if _, err := client.WithContext(context.Background()).GetVlansInfo(); err != nil {
+		t.Fatalf("GetVlansInfo failed: %v", err)
+	}
+	if requests := server.Requests(); len(requests) != detected+1 {
+		t.Fatalf("expected a single request after detection, got %v", requests[detected:])
+	}
+}
+
+// netconfTestAttrs returns the provider configuration for server over
+// NETCONF, with its host key pinned.
+func netconfTestAttrs(server *f5osemu.NetconfServer) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"host":         tftypes.NewValue(tftypes.String, "127.0.0.1"),
+		"username":     tftypes.NewValue(tftypes.String, "admin"),
+		"password":     tftypes.NewValue(tftypes.String, "admin"),
+		"transport":    tftypes.NewValue(tftypes.String, "netconf"),
+		"netconf_port": tftypes.NewValue(tftypes.Number, server.Port()),
+		"ssh_host_key": tftypes.NewValue(tftypes.String, string(ssh.MarshalAuthorizedKey(server.HostKey()))),
+	}
+}
+
+// TestUnitProviderNetconfTransport configures the provider for NETCONF
+// against the emulator's SSH stand-in and drives a VLAN and an action
+// through it.
+func TestUnitProviderNetconfTransport(t *testing.T) {
+	t.Setenv("F5OS_SESSION_CACHE", "false")
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	e, err := f5osemu.New(f5osemu.Options{Platform: f5osemu.RSeries})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server, err := f5osemu.NewNetconfServer(e)
+	if err != nil {
+		t.Fatalf("NewNetconfServer failed: %v", err)
+	}
+	defer server.Close()
+
+	resp := testProviderConnect(t, netconfTestAttrs(server))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	if client.Platform() != "r5900" {
+		t.Fatalf("expected the platform to be detected over NETCONF, got %q", client.Platform())
+	}
+
+	vlans := &f5os.F5ReqVlansConfig{}
+	vlan := f5os.F5ReqVlanConfig{VlanId: "300"}
+	vlan.Config.VlanId = 300
+	vlan.Config.Name = "netconf-vlan"
+	vlans.OpenconfigVlanVlans.Vlan = append(vlans.OpenconfigVlanVlans.Vlan, vlan)
+	if _, err := client.VlanConfig(vlans); err != nil {
+		t.Fatalf("VlanConfig failed: %v", err)
+	}
+	got, err := client.GetVlan(300)
+	if err != nil || len(got.OpenconfigVlanVlan) != 1 || got.OpenconfigVlanVlan[0].Config.Name != "netconf-vlan" {
+		t.Fatalf("expected VLAN 300 to be read back, got %+v, %v", got, err)
+	}
+
+	r := &RpcResource{client: client}
+	m := rpcTestModel("/f5-utils-file-transfer:file/import",
+		`{"f5-utils-file-transfer:remote-host":"files.example.net","f5-utils-file-transfer:remote-file":"/images/BIGIP-17.1.0.ALL-F5OS.qcow2.zip.bundle","f5-utils-file-transfer:local-file":"images/tenant","f5-utils-file-transfer:protocol":"https"}`)
+	m.StatusPath = types.StringValue("/f5-utils-file-transfer:file/transfer-operations")
+	m.StatusCondition = types.StringValue("$..transfer-operation[?(@.status == 'Completed')]")
+	var state RpcResourceModel
+	rpcResp := testResourceCreate(t, r, m, &state)
+	if rpcResp.Diagnostics.HasError() {
+		t.Fatalf("Create returned diagnostics: %v", rpcResp.Diagnostics)
+	}
+	if !strings.Contains(state.Output.ValueString(), `"operation-id"`) {
+		t.Fatalf("expected the action output, got %s", state.Output.ValueString())
+	}
+
+	requests := strings.Join(server.Requests(), "\n")
+	for _, want := range []string{
+		"PATCH /restconf/data/openconfig-vlan:vlans",
+		"POST /restconf/data/f5-utils-file-transfer:file/import",
+	} {
+		if !strings.Contains(requests, want) {
+			t.Errorf("expected %q among the requests:\n%s", want, requests)
+		}
+	}
+}
+
+// TestUnitNetconfTransportRejectsRawRestconf verifies that f5os_restconf,
+// f5os_rpc and the f5os_restconf data source, which send paths and
+// payloads of any model, are rejected over NETCONF.
+func TestUnitNetconfTransportRejectsRawRestconf(t *testing.T) {
+	e, err := f5osemu.New(f5osemu.Options{Platform: f5osemu.RSeries})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server, err := f5osemu.NewNetconfServer(e)
+	if err != nil {
+		t.Fatalf("NewNetconfServer failed: %v", err)
+	}
+	defer server.Close()
+	configured := testProviderConfigure(t, netconfTestAttrs(server))
+	if configured.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", configured.Diagnostics)
+	}
+
+	ctx := context.Background()
+	for name, diags := range map[string]diag.Diagnostics{
+		"f5os_restconf": func() diag.Diagnostics {
+			resp := &fwresource.ConfigureResponse{}
+			(&RestconfResource{}).Configure(ctx, fwresource.ConfigureRequest{ProviderData: configured.ResourceData}, resp)
+			return resp.Diagnostics
+		}(),
+		"f5os_rpc": func() diag.Diagnostics {
+			resp := &fwresource.ConfigureResponse{}
+			(&RpcResource{}).Configure(ctx, fwresource.ConfigureRequest{ProviderData: configured.ResourceData}, resp)
+			return resp.Diagnostics
+		}(),
+		"data source f5os_restconf": func() diag.Diagnostics {
+			resp := &fwdatasource.ConfigureResponse{}
+			(&RestconfDataSource{}).Configure(ctx, fwdatasource.ConfigureRequest{ProviderData: configured.DataSourceData}, resp)
+			return resp.Diagnostics
+		}(),
+	} {
+		if !diags.HasError() || diags[0].Summary() != "Unsupported Transport" || !strings.HasPrefix(diags[0].Detail(), name+" is not supported") {
+			t.Fatalf("expected %s to be rejected over NETCONF, got %v", name, diags)
+		}
+	}
+
+	resp := &fwresource.ConfigureResponse{}
+	(&VlanResource{}).Configure(ctx, fwresource.ConfigureRequest{ProviderData: configured.ResourceData}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected f5os_vlan to be supported over NETCONF, got %v", resp.Diagnostics)
+	}
+}
+
+// TestUnitProviderNetconfTransportValidation verifies that NETCONF
+// configuration errors surface as provider diagnostics.
+func TestUnitProviderNetconfTransportValidation(t *testing.T) {
+	t.Setenv("F5OS_SESSION_CACHE", "false")
+	e, err := f5osemu.New(f5osemu.Options{Platform: f5osemu.RSeries})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server, err := f5osemu.NewNetconfServer(e)
+	if err != nil {
+		t.Fatalf("NewNetconfServer failed: %v", err)
+	}
+	defer server.Close()
+
+	for name, tc := range map[string]struct {
+		attrs map[string]tftypes.Value
+		want  string
+	}{
+		"auth token": {
+			attrs: map[string]tftypes.Value{"auth_token": tftypes.NewValue(tftypes.String, "token")},
+			want:  "Auth token not supported over NETCONF",
+		},
+		"malformed host key": {
+			attrs: map[string]tftypes.Value{"ssh_host_key": tftypes.NewValue(tftypes.String, "not a key")},
+			want:  "Invalid SSH host key",
+		},
+		"wrong host key": {
+			attrs: map[string]tftypes.Value{"ssh_host_key": tftypes.NewValue(tftypes.String, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIE4x2hUTsT8/NJ0Nn3GTGPy4H8mDkQUAsGw1oV7jZkFq")},
+			want:  "host key mismatch",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			attrs := netconfTestAttrs(server)
+			for k, v := range tc.attrs {
+				attrs[k] = v
+			}
+			resp := testProviderConnect(t, attrs)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error")
+			}
+			var all []string
+			for _, d := range resp.Diagnostics.Errors() {
+				all = append(all, d.Summary()+": "+d.Detail())
+			}
+			if !strings.Contains(strings.Join(all, "\n"), tc.want) {
+				t.Fatalf("expected %q, got %v", tc.want, all)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -43,6 +44,8 @@ type privateStateGetter interface {
 // Ensure interface satisfaction
 var _ resource.Resource = &AuthResource{}
 var _ resource.ResourceWithImportState = &AuthResource{}
+var _ resource.ResourceWithValidateConfig = &AuthResource{}
+var _ resource.ResourceWithModifyPlan = &AuthResource{}
 
 func NewAuthResource() resource.Resource { return &AuthResource{} }
 
@@ -116,6 +119,21 @@ func (r *AuthResource) Configure(_ context.Context, req resource.ConfigureReques
 	}
 }
 
+// ValidateConfig rejects attributes the device does not support, once
+// the provider is configured; see attributeRequirements.
+func (r *AuthResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateAttributeSupport(ctx, r.client, "f5os_auth", req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan rejects attributes the device does not support at plan
+// time; see attributeRequirements.
+func (r *AuthResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_auth", req.Config, &resp.Diagnostics)
+}
+
 func (r *AuthResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manage AAA authentication on F5OS. Includes authentication method order, role GID mappings, and password policy.\n\n" +
@@ -500,11 +518,11 @@ func (r *AuthResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		}
 	}
 
-	// Read login_policy from device when managed or during import. Login
-	// policy is only available on F5OS 2.0.0+; on older devices the read is
-	// skipped during import (nothing to import) and, when managed, the write
-	// would already have failed at Create/Update time.
-	if !state.LoginPolicy.IsNull() || (isImport && platformVersionAtLeast(r.client.PlatformVersion, "v2.0")) {
+	// Read login_policy from device when managed or during import. On a
+	// device without login policy the read is skipped during import
+	// (nothing to import) and, when managed, the write would already have
+	// failed at Create/Update time.
+	if !state.LoginPolicy.IsNull() || (isImport && attributeSupported(ctx, r.client, "f5os_auth", "login_policy")) {
 		resp.Diagnostics.Append(r.readLoginPolicy(ctx, &state, isImport)...)
 		if resp.Diagnostics.HasError() {
 			return
@@ -512,10 +530,10 @@ func (r *AuthResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	// Read ldap config from device when managed or during import. Like
-	// login_policy, the LDAP object classes are only available on F5OS
-	// 2.0.0+; on older devices the import read is skipped and a managed
-	// write would already have failed at Create/Update time.
-	if !state.Ldap.IsNull() || (isImport && platformVersionAtLeast(r.client.PlatformVersion, "v2.0")) {
+	// login_policy, on a device without the LDAP module the import read is
+	// skipped and a managed write would already have failed at
+	// Create/Update time.
+	if !state.Ldap.IsNull() || (isImport && attributeSupported(ctx, r.client, "f5os_auth", "ldap")) {
 		resp.Diagnostics.Append(r.readLdapConfig(ctx, &state, isImport)...)
 		if resp.Diagnostics.HasError() {
 			return
@@ -743,14 +761,14 @@ func (r *AuthResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 					// The role had no remote-gid before Terraform managed it;
 					// delete the leaf to restore the unset state.
 					tflog.Debug(ctx, "Clearing role remote-gid", map[string]any{"rolename": rolename})
-					if err := r.client.ClearRoleRemoteGID(rolename); err != nil {
+					if err := r.client.WithContext(ctx).ClearRoleRemoteGID(rolename); err != nil {
 						tflog.Warn(ctx, "failed clearing role remote-gid",
 							map[string]any{"rolename": rolename, "error": err.Error()})
 					}
 				} else {
 					gid64 := int64(gid)
 					tflog.Debug(ctx, "Restoring role config", map[string]any{"rolename": rolename, "gid": gid64})
-					if err := r.client.SetRoleConfig(rolename, &gid64); err != nil {
+					if err := r.client.WithContext(ctx).SetRoleConfig(rolename, &gid64); err != nil {
 						tflog.Warn(ctx, "failed restoring original role GID",
 							map[string]any{"rolename": rolename, "gid": gid64, "error": err.Error()})
 					}
@@ -773,7 +791,7 @@ func (r *AuthResource) ImportState(ctx context.Context, req resource.ImportState
 
 //go:cover ignore
 func (r *AuthResource) getAuthOrder(ctx context.Context) ([]string, error) {
-	openConfigMethods, err := r.client.GetAuthOrder()
+	openConfigMethods, err := r.client.WithContext(ctx).GetAuthOrder()
 	if err != nil {
 		return nil, err
 	}
@@ -803,7 +821,7 @@ func (r *AuthResource) getAuthOrder(ctx context.Context) ([]string, error) {
 
 //go:cover ignore
 func (r *AuthResource) listRoles(ctx context.Context) (map[string]int, error) {
-	return r.client.GetRoles()
+	return r.client.WithContext(ctx).GetRoles()
 }
 
 // snapshotAuthOrder reads the current auth_order from the device and saves it
@@ -931,13 +949,13 @@ func (r *AuthResource) ensureRoleGIDsSnapshotted(ctx context.Context, privateRea
 // createAuthOrder sets the authentication method order
 func (r *AuthResource) createAuthOrder(ctx context.Context, methods []string) error {
 	tflog.Debug(ctx, "Creating auth order", map[string]any{"methods": methods})
-	return r.client.SetAuthOrder(methods)
+	return r.client.WithContext(ctx).SetAuthOrder(methods)
 }
 
 // createRoleConfig creates a role with specific gid
 func (r *AuthResource) createRoleConfig(ctx context.Context, rolename string, gid *int64) error {
 	tflog.Debug(ctx, "Creating role config", map[string]any{"rolename": rolename, "gid": gid})
-	return r.client.SetRoleConfig(rolename, gid)
+	return r.client.WithContext(ctx).SetRoleConfig(rolename, gid)
 }
 
 //go:cover ignore
@@ -1005,25 +1023,25 @@ func (r *AuthResource) readRoleConfig(ctx context.Context, state *AuthResourceMo
 // updateAuthOrder updates the authentication method order
 func (r *AuthResource) updateAuthOrder(ctx context.Context, methods []string) error {
 	tflog.Debug(ctx, "Updating auth order", map[string]any{"methods": methods})
-	return r.client.SetAuthOrder(methods)
+	return r.client.WithContext(ctx).SetAuthOrder(methods)
 }
 
 // updateRoleConfig updates a role with specific gid
 func (r *AuthResource) updateRoleConfig(ctx context.Context, rolename string, gid *int64) error {
 	tflog.Debug(ctx, "Updating role config", map[string]any{"rolename": rolename, "gid": gid})
-	return r.client.SetRoleConfig(rolename, gid)
+	return r.client.WithContext(ctx).SetRoleConfig(rolename, gid)
 }
 
 // restoreAuthOrder restores the authentication method order to a previous state
 func (r *AuthResource) restoreAuthOrder(ctx context.Context, methods []string) error {
 	tflog.Debug(ctx, "Restoring auth order", map[string]any{"methods": methods})
-	return r.client.SetAuthOrder(methods)
+	return r.client.WithContext(ctx).SetAuthOrder(methods)
 }
 
 // deleteAuthOrder removes the authentication method order
 func (r *AuthResource) deleteAuthOrder(ctx context.Context) error {
 	tflog.Debug(ctx, "Deleting auth order")
-	return r.client.ClearAuthOrder()
+	return r.client.WithContext(ctx).ClearAuthOrder()
 } // Simple validator to restrict auth_order values
 type listAuthOrderValidator struct{}
 
@@ -1099,47 +1117,39 @@ func passwordPolicyAttrTypes() map[string]attr.Type {
 	}
 }
 
-// validateV17Fields checks whether the user configured v1.7+ fields on a
-// device that doesn't support them. Returns diagnostics with errors if so.
+// validateV17Fields rejects the password policy fields added in F5OS 1.7
+// that pp sets but the device does not support; see attributeRequirements.
 func (r *AuthResource) validateV17Fields(ctx context.Context, pp *passwordPolicyModel) diag.Diagnostics {
-	var diags diag.Diagnostics
-	if platformVersionAtLeast(r.client.PlatformVersion, "v1.7") {
-		return diags
-	}
-	if !pp.MaxLetterRepeat.IsNull() && !pp.MaxLetterRepeat.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"max_letter_repeat is not supported on F5OS versions below v1.7")
-	}
-	if !pp.MaxSequenceRepeat.IsNull() && !pp.MaxSequenceRepeat.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"max_sequence_repeat is not supported on F5OS versions below v1.7")
-	}
-	if !pp.MaxClassRepeat.IsNull() && !pp.MaxClassRepeat.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"max_class_repeat is not supported on F5OS versions below v1.7")
-	}
-	return diags
+	return r.checkPasswordPolicySupport(ctx, map[string]types.Int64{
+		"max_letter_repeat":   pp.MaxLetterRepeat,
+		"max_sequence_repeat": pp.MaxSequenceRepeat,
+		"max_class_repeat":    pp.MaxClassRepeat,
+	})
 }
 
-// validateV20Fields checks whether the user configured v2.0.0+ fields on a
-// device that doesn't support them. Returns diagnostics with errors if so.
+// validateV20Fields rejects the password policy fields added in F5OS
+// 2.0.0 that pp sets but the device does not support; see
+// attributeRequirements.
 func (r *AuthResource) validateV20Fields(ctx context.Context, pp *passwordPolicyModel) diag.Diagnostics {
+	return r.checkPasswordPolicySupport(ctx, map[string]types.Int64{
+		"min_days": pp.MinDays,
+		"remember": pp.Remember,
+		"warn_age": pp.WarnAge,
+	})
+}
+
+// checkPasswordPolicySupport runs checkAttributeSupport for the
+// password_policy fields that are set.
+func (r *AuthResource) checkPasswordPolicySupport(ctx context.Context, fields map[string]types.Int64) diag.Diagnostics {
 	var diags diag.Diagnostics
-	if platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		return diags
-	}
-	if !pp.MinDays.IsNull() && !pp.MinDays.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"min_days is not supported on F5OS versions below 2.0.0")
-	}
-	if !pp.Remember.IsNull() && !pp.Remember.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"remember is not supported on F5OS versions below 2.0.0")
-	}
-	if !pp.WarnAge.IsNull() && !pp.WarnAge.IsUnknown() {
-		diags.AddError("Unsupported attribute",
-			"warn_age is not supported on F5OS versions below 2.0.0")
+	var set []string
+	for name, value := range fields {
+		if !value.IsNull() && !value.IsUnknown() {
+			set = append(set, "password_policy."+name)
+		}
 	}
+	sort.Strings(set)
+	checkAttributeSupport(ctx, r.client, "f5os_auth", &diags, set...)
 	return diags
 }
 
@@ -1151,7 +1161,7 @@ func (r *AuthResource) validateV20Fields(ctx context.Context, pp *passwordPolicy
 // are refreshed — this avoids adding fields the user didn't declare.
 func (r *AuthResource) readPasswordPolicy(ctx context.Context, state *AuthResourceModel, isImport bool) diag.Diagnostics {
 	var diags diag.Diagnostics
-	policy, err := r.client.GetPasswordPolicy()
+	policy, err := r.client.WithContext(ctx).GetPasswordPolicy()
 	if err != nil {
 		diags.AddError("Failed to read password policy from device", err.Error())
 		return diags
@@ -1159,7 +1169,7 @@ func (r *AuthResource) readPasswordPolicy(ctx context.Context, state *AuthResour
 
 	if isImport {
 		// Import: populate all fields from device
-		model := passwordPolicyConfigToModel(policy, r.client.PlatformVersion)
+		model := passwordPolicyConfigToModel(policy)
 		obj, d := types.ObjectValueFrom(ctx, passwordPolicyAttrTypes(), model)
 		diags.Append(d...)
 		if !diags.HasError() {
@@ -1248,9 +1258,9 @@ func (r *AuthResource) readPasswordPolicy(ctx context.Context, state *AuthResour
 // and sends it to the device via PATCH.
 func (r *AuthResource) writePasswordPolicy(ctx context.Context, pp *passwordPolicyModel) diag.Diagnostics {
 	var diags diag.Diagnostics
-	config := passwordPolicyModelToConfig(pp, r.client.PlatformVersion)
+	config := passwordPolicyModelToConfig(pp)
 	tflog.Debug(ctx, "Writing password policy to device")
-	if err := r.client.SetPasswordPolicy(config); err != nil {
+	if err := r.client.WithContext(ctx).SetPasswordPolicy(config); err != nil {
 		diags.AddError("Failed to set password policy", err.Error())
 	}
 	return diags
@@ -1258,8 +1268,7 @@ func (r *AuthResource) writePasswordPolicy(ctx context.Context, pp *passwordPoli
 
 // passwordPolicyModelToConfig converts a Terraform passwordPolicyModel to
 // an f5osclient PasswordPolicyConfig struct. Only non-null fields are set.
-// The deviceVersion parameter controls which version-specific fields are included.
-func passwordPolicyModelToConfig(pp *passwordPolicyModel, deviceVersion string) *f5os.PasswordPolicyConfig {
+func passwordPolicyModelToConfig(pp *passwordPolicyModel) *f5os.PasswordPolicyConfig {
 	config := &f5os.PasswordPolicyConfig{}
 
 	if !pp.MinLength.IsNull() && !pp.MinLength.IsUnknown() {
@@ -1319,36 +1328,31 @@ func passwordPolicyModelToConfig(pp *passwordPolicyModel, deviceVersion string)
 		config.MaxAge = &v
 	}
 
-	// v1.7+ fields — only include if device supports them
-	if platformVersionAtLeast(deviceVersion, "v1.7") {
-		if !pp.MaxLetterRepeat.IsNull() && !pp.MaxLetterRepeat.IsUnknown() {
-			v := pp.MaxLetterRepeat.ValueInt64()
-			config.MaxLetterRepeat = &v
-		}
-		if !pp.MaxSequenceRepeat.IsNull() && !pp.MaxSequenceRepeat.IsUnknown() {
-			v := pp.MaxSequenceRepeat.ValueInt64()
-			config.MaxSequenceRepeat = &v
-		}
-		if !pp.MaxClassRepeat.IsNull() && !pp.MaxClassRepeat.IsUnknown() {
-			v := pp.MaxClassRepeat.ValueInt64()
-			config.MaxClassRepeat = &v
-		}
+	// The F5OS 1.7 and 2.0 fields are checked against the device by
+	// validateV17Fields and validateV20Fields before the policy is written.
+	if !pp.MaxLetterRepeat.IsNull() && !pp.MaxLetterRepeat.IsUnknown() {
+		v := pp.MaxLetterRepeat.ValueInt64()
+		config.MaxLetterRepeat = &v
 	}
-
-	// v2.0.0+ fields — only include if device supports them
-	if platformVersionAtLeast(deviceVersion, "v2.0") {
-		if !pp.MinDays.IsNull() && !pp.MinDays.IsUnknown() {
-			v := pp.MinDays.ValueInt64()
-			config.MinDays = &v
-		}
-		if !pp.Remember.IsNull() && !pp.Remember.IsUnknown() {
-			v := pp.Remember.ValueInt64()
-			config.Remember = &v
-		}
-		if !pp.WarnAge.IsNull() && !pp.WarnAge.IsUnknown() {
-			v := pp.WarnAge.ValueInt64()
-			config.WarnAge = &v
-		}
+	if !pp.MaxSequenceRepeat.IsNull() && !pp.MaxSequenceRepeat.IsUnknown() {
+		v := pp.MaxSequenceRepeat.ValueInt64()
+		config.MaxSequenceRepeat = &v
+	}
+	if !pp.MaxClassRepeat.IsNull() && !pp.MaxClassRepeat.IsUnknown() {
+		v := pp.MaxClassRepeat.ValueInt64()
+		config.MaxClassRepeat = &v
+	}
+	if !pp.MinDays.IsNull() && !pp.MinDays.IsUnknown() {
+		v := pp.MinDays.ValueInt64()
+		config.MinDays = &v
+	}
+	if !pp.Remember.IsNull() && !pp.Remember.IsUnknown() {
+		v := pp.Remember.ValueInt64()
+		config.Remember = &v
+	}
+	if !pp.WarnAge.IsNull() && !pp.WarnAge.IsUnknown() {
+		v := pp.WarnAge.ValueInt64()
+		config.WarnAge = &v
 	}
 
 	return config
@@ -1356,8 +1360,7 @@ func passwordPolicyModelToConfig(pp *passwordPolicyModel, deviceVersion string)
 
 // passwordPolicyConfigToModel converts an f5osclient PasswordPolicyConfig
 // to a Terraform passwordPolicyModel for populating state.
-// The deviceVersion parameter controls which version-specific fields are populated.
-func passwordPolicyConfigToModel(config *f5os.PasswordPolicyConfig, deviceVersion string) passwordPolicyModel {
+func passwordPolicyConfigToModel(config *f5os.PasswordPolicyConfig) passwordPolicyModel {
 	model := passwordPolicyModel{}
 
 	if config.MinLength != nil {
@@ -1431,51 +1434,36 @@ func passwordPolicyConfigToModel(config *f5os.PasswordPolicyConfig, deviceVersio
 		model.MaxAge = types.Int64Null()
 	}
 
-	// v1.7+ fields — only populate if device supports them
-	if platformVersionAtLeast(deviceVersion, "v1.7") {
-		if config.MaxLetterRepeat != nil {
-			model.MaxLetterRepeat = types.Int64Value(*config.MaxLetterRepeat)
-		} else {
-			model.MaxLetterRepeat = types.Int64Null()
-		}
-		if config.MaxSequenceRepeat != nil {
-			model.MaxSequenceRepeat = types.Int64Value(*config.MaxSequenceRepeat)
-		} else {
-			model.MaxSequenceRepeat = types.Int64Null()
-		}
-		if config.MaxClassRepeat != nil {
-			model.MaxClassRepeat = types.Int64Value(*config.MaxClassRepeat)
-		} else {
-			model.MaxClassRepeat = types.Int64Null()
-		}
+	// Devices before F5OS 1.7 and 2.0 do not report the fields added in
+	// those releases, which leaves them null.
+	if config.MaxLetterRepeat != nil {
+		model.MaxLetterRepeat = types.Int64Value(*config.MaxLetterRepeat)
 	} else {
-		// Pre-v1.7: these fields don't exist on the device
 		model.MaxLetterRepeat = types.Int64Null()
+	}
+	if config.MaxSequenceRepeat != nil {
+		model.MaxSequenceRepeat = types.Int64Value(*config.MaxSequenceRepeat)
+	} else {
 		model.MaxSequenceRepeat = types.Int64Null()
+	}
+	if config.MaxClassRepeat != nil {
+		model.MaxClassRepeat = types.Int64Value(*config.MaxClassRepeat)
+	} else {
 		model.MaxClassRepeat = types.Int64Null()
 	}
-
-	// v2.0.0+ fields — only populate if device supports them
-	if platformVersionAtLeast(deviceVersion, "v2.0") {
-		if config.MinDays != nil {
-			model.MinDays = types.Int64Value(*config.MinDays)
-		} else {
-			model.MinDays = types.Int64Null()
-		}
-		if config.Remember != nil {
-			model.Remember = types.Int64Value(*config.Remember)
-		} else {
-			model.Remember = types.Int64Null()
-		}
-		if config.WarnAge != nil {
-			model.WarnAge = types.Int64Value(*config.WarnAge)
-		} else {
-			model.WarnAge = types.Int64Null()
-		}
+	if config.MinDays != nil {
+		model.MinDays = types.Int64Value(*config.MinDays)
 	} else {
-		// Pre-2.0.0: these fields don't exist on the device
 		model.MinDays = types.Int64Null()
+	}
+	if config.Remember != nil {
+		model.Remember = types.Int64Value(*config.Remember)
+	} else {
 		model.Remember = types.Int64Null()
+	}
+	if config.WarnAge != nil {
+		model.WarnAge = types.Int64Value(*config.WarnAge)
+	} else {
 		model.WarnAge = types.Int64Null()
 	}
 
@@ -1497,18 +1485,16 @@ func loginPolicyAttrTypes() map[string]attr.Type {
 }
 
 // writeLoginPolicy converts the Terraform model to an API config struct and
-// sends it to the device via PATCH. Login policy is only available on F5OS
-// 2.0.0+, so writing to an older device is rejected with a clear error.
+// sends it to the device via PATCH. A device without the login policy
+// module is rejected with a clear error; see attributeRequirements.
 func (r *AuthResource) writeLoginPolicy(ctx context.Context, lp *loginPolicyModel) diag.Diagnostics {
 	var diags diag.Diagnostics
-	if !platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		diags.AddError("Unsupported attribute",
-			"login_policy is not supported on F5OS versions below 2.0.0")
+	if checkAttributeSupport(ctx, r.client, "f5os_auth", &diags, "login_policy"); diags.HasError() {
 		return diags
 	}
 	config := loginPolicyModelToConfig(lp)
 	tflog.Debug(ctx, "Writing login policy to device")
-	if err := r.client.SetLoginPolicy(config); err != nil {
+	if err := r.client.WithContext(ctx).SetLoginPolicy(config); err != nil {
 		diags.AddError("Failed to set login policy", err.Error())
 	}
 	return diags
@@ -1522,7 +1508,7 @@ func (r *AuthResource) writeLoginPolicy(ctx context.Context, lp *loginPolicyMode
 // refreshed — this avoids adding fields the user didn't declare.
 func (r *AuthResource) readLoginPolicy(ctx context.Context, state *AuthResourceModel, isImport bool) diag.Diagnostics {
 	var diags diag.Diagnostics
-	policy, err := r.client.GetLoginPolicy()
+	policy, err := r.client.WithContext(ctx).GetLoginPolicy()
 	if err != nil {
 		diags.AddError("Failed to read login policy from device", err.Error())
 		return diags
@@ -1618,14 +1604,12 @@ func ldapAttrTypes() map[string]attr.Type {
 }
 
 // writeLdapConfig converts the Terraform model to an API config struct and
-// sends it to the device via PATCH. The LDAP object-class leaf-lists are only
-// available on F5OS 2.0.0+, so writing to an older device is rejected with a
-// clear error.
+// sends it to the device via PATCH. A device without the LDAP module, which
+// carries the object-class leaf-lists, is rejected with a clear error; see
+// attributeRequirements.
 func (r *AuthResource) writeLdapConfig(ctx context.Context, lc *ldapConfigModel) diag.Diagnostics {
 	var diags diag.Diagnostics
-	if !platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		diags.AddError("Unsupported attribute",
-			"ldap configuration (user_object_class/group_object_class) is not supported on F5OS versions below 2.0.0")
+	if checkAttributeSupport(ctx, r.client, "f5os_auth", &diags, "ldap"); diags.HasError() {
 		return diags
 	}
 	config, d := ldapModelToConfig(ctx, lc)
@@ -1634,7 +1618,7 @@ func (r *AuthResource) writeLdapConfig(ctx context.Context, lc *ldapConfigModel)
 		return diags
 	}
 	tflog.Debug(ctx, "Writing ldap config to device")
-	if err := r.client.SetLdapConfig(config); err != nil {
+	if err := r.client.WithContext(ctx).SetLdapConfig(config); err != nil {
 		diags.AddError("Failed to set ldap config", err.Error())
 	}
 	return diags
@@ -1648,7 +1632,7 @@ func (r *AuthResource) writeLdapConfig(ctx context.Context, lc *ldapConfigModel)
 // refreshed — this avoids adding fields the user didn't declare.
 func (r *AuthResource) readLdapConfig(ctx context.Context, state *AuthResourceModel, isImport bool) diag.Diagnostics {
 	var diags diag.Diagnostics
-	config, err := r.client.GetLdapConfig()
+	config, err := r.client.WithContext(ctx).GetLdapConfig()
 	if err != nil {
 		diags.AddError("Failed to read ldap config from device", err.Error())
 		return diags
@@ -2046,8 +2046,9 @@ func TestPasswordPolicyModelToConfig(t *testing.T) {
 		MaxClassRepeat:      types.Int64Value(4),
 	}
 
-	// Test v1.7+ (includes v1.7+ fields)
-	configV17 := passwordPolicyModelToConfig(model, "1.7.0")
+	// Set fields are all included; the device's support for the v1.7+
+	// fields is checked before the policy is written.
+	configV17 := passwordPolicyModelToConfig(model)
 	assert.NotNil(t, configV17)
 	assert.Equal(t, int64(10), *configV17.MinLength)
 	assert.Equal(t, int64(2), *configV17.RequiredNumeric)
@@ -2067,13 +2068,16 @@ func TestPasswordPolicyModelToConfig(t *testing.T) {
 	assert.Equal(t, int64(2), *configV17.MaxSequenceRepeat)
 	assert.Equal(t, int64(4), *configV17.MaxClassRepeat)
 
-	// Test base case (omits v1.7+ fields)
-	config := passwordPolicyModelToConfig(model, "1.5.0")
+	// Test base case (unset v1.7+ fields are omitted)
+	model.MaxLetterRepeat = types.Int64Null()
+	model.MaxSequenceRepeat = types.Int64Null()
+	model.MaxClassRepeat = types.Int64Null()
+	config := passwordPolicyModelToConfig(model)
 	assert.NotNil(t, config)
 	assert.Equal(t, int64(10), *config.MinLength)
-	assert.Nil(t, config.MaxLetterRepeat, "v1.7+ fields should be nil on base config")
-	assert.Nil(t, config.MaxSequenceRepeat, "v1.7+ fields should be nil on base config")
-	assert.Nil(t, config.MaxClassRepeat, "v1.7+ fields should be nil on base config")
+	assert.Nil(t, config.MaxLetterRepeat, "unset v1.7+ fields should be nil")
+	assert.Nil(t, config.MaxSequenceRepeat, "unset v1.7+ fields should be nil")
+	assert.Nil(t, config.MaxClassRepeat, "unset v1.7+ fields should be nil")
 }
 
 func TestPasswordPolicyConfigToModel(t *testing.T) {
@@ -2117,7 +2121,7 @@ func TestPasswordPolicyConfigToModel(t *testing.T) {
 	}
 
 	// Test v1.7+
-	modelV17 := passwordPolicyConfigToModel(config, "1.7.0")
+	modelV17 := passwordPolicyConfigToModel(config)
 	assert.Equal(t, int64(8), modelV17.MinLength.ValueInt64())
 	assert.Equal(t, int64(1), modelV17.RequiredNumeric.ValueInt64())
 	assert.Equal(t, int64(1), modelV17.RequiredUppercase.ValueInt64())
@@ -2136,8 +2140,10 @@ func TestPasswordPolicyConfigToModel(t *testing.T) {
 	assert.Equal(t, int64(2), modelV17.MaxSequenceRepeat.ValueInt64())
 	assert.Equal(t, int64(4), modelV17.MaxClassRepeat.ValueInt64())
 
-	// Test base case (v1.7+ fields should be null)
-	model := passwordPolicyConfigToModel(config, "1.5.0")
+	// Test base case (a device before 1.7 does not report the v1.7+
+	// fields, which should be null)
+	config.MaxLetterRepeat, config.MaxSequenceRepeat, config.MaxClassRepeat = nil, nil, nil
+	model := passwordPolicyConfigToModel(config)
 	assert.Equal(t, int64(8), model.MinLength.ValueInt64())
 	assert.True(t, model.MaxLetterRepeat.IsNull(), "v1.7+ fields should be null on base config")
 	assert.True(t, model.MaxSequenceRepeat.IsNull(), "v1.7+ fields should be null on base config")
@@ -2552,7 +2558,7 @@ func TestAccAuthResourcePasswordPolicy(t *testing.T) {
 		t.Skipf("Cannot create f5os client: %v", err)
 	}
 
-	deviceVersion := client.PlatformVersion
+	deviceVersion := client.Version()
 	t.Logf("Device version: %s", deviceVersion)
 
 	// Capture the true device baseline before we touch anything
@@ -2937,8 +2943,7 @@ func TestAuthResourceUnit_ValidateV17FieldsOnV17(t *testing.T) {
 func TestPasswordPolicyConfigToModel_AllNilFields(t *testing.T) {
 	config := &f5os.PasswordPolicyConfig{}
 
-	// Test with pre-v1.7
-	model := passwordPolicyConfigToModel(config, "1.5.0")
+	model := passwordPolicyConfigToModel(config)
 	assert.True(t, model.MinLength.IsNull())
 	assert.True(t, model.RequiredNumeric.IsNull())
 	assert.True(t, model.RequiredUppercase.IsNull())
@@ -2956,13 +2961,6 @@ func TestPasswordPolicyConfigToModel_AllNilFields(t *testing.T) {
 	assert.True(t, model.MaxLetterRepeat.IsNull())
 	assert.True(t, model.MaxSequenceRepeat.IsNull())
 	assert.True(t, model.MaxClassRepeat.IsNull())
-
-	// Test with v1.7+ (nil fields should still be null)
-	modelV17 := passwordPolicyConfigToModel(config, "1.7.0")
-	assert.True(t, modelV17.MinLength.IsNull())
-	assert.True(t, modelV17.MaxLetterRepeat.IsNull())
-	assert.True(t, modelV17.MaxSequenceRepeat.IsNull())
-	assert.True(t, modelV17.MaxClassRepeat.IsNull())
 }
 
 // TestPasswordPolicyModelToConfig_AllNullFields verifies that when all model
@@ -2988,7 +2986,7 @@ func TestPasswordPolicyModelToConfig_AllNullFields(t *testing.T) {
 		MaxClassRepeat:      types.Int64Null(),
 	}
 
-	config := passwordPolicyModelToConfig(model, "1.7.0")
+	config := passwordPolicyModelToConfig(model)
 	assert.Nil(t, config.MinLength)
 	assert.Nil(t, config.RequiredNumeric)
 	assert.Nil(t, config.RequiredUppercase)
@@ -4244,8 +4242,8 @@ func TestAccAuthResourceLoginPolicy(t *testing.T) {
 	if err != nil {
 		t.Skipf("Cannot create f5os client: %v", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("skipping: login_policy requires F5OS 2.0.0+ but device reports %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("skipping: login_policy requires F5OS 2.0.0+ but device reports %q", client.Version())
 	}
 
 	// Capture the true device baseline so it can be restored after the test,
@@ -4670,8 +4668,8 @@ func TestAccAuthResourceLdap(t *testing.T) {
 	if err != nil {
 		t.Skipf("Cannot create f5os client: %v", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("skipping: ldap object classes require F5OS 2.0.0+ but device reports %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("skipping: ldap object classes require F5OS 2.0.0+ but device reports %q", client.Version())
 	}
 
 	// Capture the true device baseline so it can be restored after the test,
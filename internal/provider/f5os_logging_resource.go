@@ -296,7 +296,7 @@ func (r *f5osLoggingResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	// Use the embedded client (set in Configure)
-	client := r.client
+	client := r.client.WithContext(ctx)
 	baseURI := "/openconfig-system:system/logging"
 
 	// TLS and CA Bundles must be created together first (before servers)
@@ -338,7 +338,7 @@ func (r *f5osLoggingResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	client := r.client
+	client := r.client.WithContext(ctx)
 	baseURI := "/openconfig-system:system/logging"
 
 	// Check if servers are configured in the current state
@@ -1087,7 +1087,7 @@ func (r *f5osLoggingResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	client := r.client
+	client := r.client.WithContext(ctx)
 	baseURI := "/openconfig-system:system/logging"
 
 	if err := putTLSWithCABundles(ctx, client, baseURI, plan.TLS, plan.CABundles, (*resource.CreateResponse)(resp)); err != nil {
@@ -1133,7 +1133,7 @@ func (r *f5osLoggingResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	client := r.client
+	client := r.client.WithContext(ctx)
 	baseURI := "/openconfig-system:system/logging"
 
 	log.Printf("[DEBUG] Starting delete process for logging configuration")
@@ -2,8 +2,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -18,6 +16,8 @@ import (
 )
 
 var _ resource.ResourceWithImportState = &NTPServerResource{}
+var _ resource.ResourceWithValidateConfig = &NTPServerResource{}
+var _ resource.ResourceWithModifyPlan = &NTPServerResource{}
 
 type NTPServerResource struct {
 	client *f5os.F5os
@@ -37,6 +37,21 @@ func (r *NTPServerResource) Configure(_ context.Context, req resource.ConfigureR
 	}
 }
 
+// ValidateConfig rejects attributes the device does not support, once
+// the provider is configured; see attributeRequirements.
+func (r *NTPServerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateAttributeSupport(ctx, r.client, "f5os_ntp_server", req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan rejects attributes the device does not support at plan
+// time; see attributeRequirements.
+func (r *NTPServerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_ntp_server", req.Config, &resp.Diagnostics)
+}
+
 func (r *NTPServerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Manage NTP servers on F5OS based systems (Velos controller or rSeries appliance).",
@@ -118,18 +133,18 @@ func (r *NTPServerResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	if diags := r.validate200Fields(plan); diags.HasError() {
+	if diags := r.validate200Fields(ctx, plan); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	payload, err := r.client.CreateNTPServerPayload(plan.Server.ValueString(), plan)
+	payload, err := r.client.WithContext(ctx).CreateNTPServerPayload(plan.Server.ValueString(), plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Payload Creation Error", err.Error())
 		return
 	}
 
-	if err = r.client.CreateNTPServer(plan.Server.ValueString(), payload); err != nil {
+	if err = r.client.WithContext(ctx).CreateNTPServer(plan.Server.ValueString(), payload); err != nil {
 		resp.Diagnostics.AddError("NTP Create Error", err.Error())
 		return
 	}
@@ -138,7 +153,7 @@ func (r *NTPServerResource) Create(ctx context.Context, req resource.CreateReque
 	// and resolve any Unknown Computed values from the device. See
 	// resolveAndPatchGlobalNTP for the shared implementation used by
 	// both Create and Update.
-	if diags := r.resolveAndPatchGlobalNTP(&plan, "NTP Global Config Error"); diags.HasError() {
+	if diags := r.resolveAndPatchGlobalNTP(ctx, &plan, "NTP Global Config Error"); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
@@ -152,7 +167,7 @@ func (r *NTPServerResource) Create(ctx context.Context, req resource.CreateReque
 	// these end up as null, which is a legal value for a Computed
 	// attribute. Failures here are warnings (see helper) so a transient
 	// post-write GET error does not leak the resource on the device.
-	resp.Diagnostics.Append(r.refreshComputedStateLeaves(&plan)...)
+	resp.Diagnostics.Append(r.refreshComputedStateLeaves(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -185,13 +200,13 @@ func (r *NTPServerResource) Read(ctx context.Context, req resource.ReadRequest,
 		"ntp_authentication": state.NTPAuthentication.String(),
 	})
 
-	ntp, err := r.client.GetNTPServer(state.Server.ValueString())
+	ntp, err := r.client.WithContext(ctx).GetNTPServer(state.Server.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("NTP Read Error", err.Error())
 		return
 	}
 
-	ntpService, ntpAuth, err := r.client.GetNTPGlobalConfig()
+	ntpService, ntpAuth, err := r.client.WithContext(ctx).GetNTPGlobalConfig()
 	if err != nil {
 		resp.Diagnostics.AddError("NTP Global Config Read Error", err.Error())
 		return
@@ -248,18 +263,18 @@ func (r *NTPServerResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	if diags := r.validate200Fields(plan); diags.HasError() {
+	if diags := r.validate200Fields(ctx, plan); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	payload, err := r.client.UpdateNTPServerPayload(plan.Server.ValueString(), plan)
+	payload, err := r.client.WithContext(ctx).UpdateNTPServerPayload(plan.Server.ValueString(), plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Payload Creation Error", err.Error())
 		return
 	}
 
-	if err := r.client.UpdateNTPServer(plan.Server.ValueString(), payload); err != nil {
+	if err := r.client.WithContext(ctx).UpdateNTPServer(plan.Server.ValueString(), payload); err != nil {
 		resp.Diagnostics.AddError("NTP Update Error", err.Error())
 		return
 	}
@@ -268,14 +283,14 @@ func (r *NTPServerResource) Update(ctx context.Context, req resource.UpdateReque
 	// and resolve any Unknown Computed values from the device. See
 	// resolveAndPatchGlobalNTP for the shared implementation used by
 	// both Create and Update.
-	if diags := r.resolveAndPatchGlobalNTP(&plan, "NTP Global Config Update Error"); diags.HasError() {
+	if diags := r.resolveAndPatchGlobalNTP(ctx, &plan, "NTP Global Config Update Error"); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
 	// Populate F5OS 2.0.0+ read-only state leaves so Computed attributes
 	// carry concrete post-apply values. Failures here are warnings.
-	resp.Diagnostics.Append(r.refreshComputedStateLeaves(&plan)...)
+	resp.Diagnostics.Append(r.refreshComputedStateLeaves(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -291,7 +306,7 @@ func (r *NTPServerResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	if err := r.client.DeleteNTPServer(state.Server.ValueString()); err != nil {
+	if err := r.client.WithContext(ctx).DeleteNTPServer(state.Server.ValueString()); err != nil {
 		resp.Diagnostics.AddError("NTP Delete Error", err.Error())
 		return
 	}
@@ -305,9 +320,10 @@ func (r *NTPServerResource) ImportState(ctx context.Context, req resource.Import
 
 // validate200Fields returns an error diagnostic when any of the F5OS
 // 2.0.0+ additive attributes (association_type, version, port) are set
-// on a device running an older version. It is called by Create and
+// on a device that does not support them; see attributeRequirements.
+// It is called by Create and
 // Update before any payload is built.
-func (r *NTPServerResource) validate200Fields(plan f5os.NTPServerModel) diag.Diagnostics {
+func (r *NTPServerResource) validate200Fields(ctx context.Context, plan f5os.NTPServerModel) diag.Diagnostics {
 	var diags diag.Diagnostics
 	var set []string
 	if !plan.AssociationType.IsNull() && !plan.AssociationType.IsUnknown() {
@@ -319,16 +335,7 @@ func (r *NTPServerResource) validate200Fields(plan f5os.NTPServerModel) diag.Dia
 	if !plan.Port.IsNull() && !plan.Port.IsUnknown() {
 		set = append(set, "port")
 	}
-	if len(set) == 0 {
-		return diags
-	}
-	if !platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		diags.AddError("Unsupported attribute",
-			fmt.Sprintf("The following NTP server attribute(s) are only "+
-				"supported on F5OS 2.0.0 or later: %s. Detected device "+
-				"version: %q. Remove these attributes or target a 2.0.0+ device.",
-				strings.Join(set, ", "), r.client.PlatformVersion))
-	}
+	checkAttributeSupport(ctx, r.client, "f5os_ntp_server", &diags, set...)
 	return diags
 }
 
@@ -345,7 +352,7 @@ func (r *NTPServerResource) validate200Fields(plan f5os.NTPServerModel) diag.Dia
 //
 // patchErrTitle is used as the diagnostic title for a PatchNTPGlobalConfig
 // failure so Create and Update can surface their own error label.
-func (r *NTPServerResource) resolveAndPatchGlobalNTP(plan *f5os.NTPServerModel, patchErrTitle string) diag.Diagnostics {
+func (r *NTPServerResource) resolveAndPatchGlobalNTP(ctx context.Context, plan *f5os.NTPServerModel, patchErrTitle string) diag.Diagnostics {
 	var diags diag.Diagnostics
 	if !plan.NTPService.IsNull() && !plan.NTPService.IsUnknown() || !plan.NTPAuthentication.IsNull() && !plan.NTPAuthentication.IsUnknown() {
 		var svc, auth *bool
@@ -357,13 +364,13 @@ func (r *NTPServerResource) resolveAndPatchGlobalNTP(plan *f5os.NTPServerModel,
 			v := plan.NTPAuthentication.ValueBool()
 			auth = &v
 		}
-		if err := r.client.PatchNTPGlobalConfig(svc, auth); err != nil {
+		if err := r.client.WithContext(ctx).PatchNTPGlobalConfig(svc, auth); err != nil {
 			diags.AddError(patchErrTitle, err.Error())
 			return diags
 		}
 	}
 	if plan.NTPService.IsUnknown() || plan.NTPAuthentication.IsUnknown() {
-		svc, auth, err := r.client.GetNTPGlobalConfig()
+		svc, auth, err := r.client.WithContext(ctx).GetNTPGlobalConfig()
 		if err != nil {
 			diags.AddError("NTP Global Config Read Error", err.Error())
 			return diags
@@ -391,9 +398,9 @@ func (r *NTPServerResource) resolveAndPatchGlobalNTP(plan *f5os.NTPServerModel,
 // resource on the device while Terraform believes nothing was
 // created, forcing a subsequent apply to fail with "already exists".
 // The next Read cycle fills the Computed leaves.
-func (r *NTPServerResource) refreshComputedStateLeaves(plan *f5os.NTPServerModel) diag.Diagnostics {
+func (r *NTPServerResource) refreshComputedStateLeaves(ctx context.Context, plan *f5os.NTPServerModel) diag.Diagnostics {
 	var diags diag.Diagnostics
-	ntp, err := r.client.GetNTPServer(plan.Server.ValueString())
+	ntp, err := r.client.WithContext(ctx).GetNTPServer(plan.Server.ValueString())
 	if err != nil {
 		diags.AddWarning("NTP Post-Write Read Warning",
 			"The NTP server was written to the device successfully, but the "+
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -1657,8 +1658,8 @@ func testAccPreCheckNTPServer2_0(t *testing.T) {
 	if err != nil {
 		t.Fatalf("testAccPreCheckNTPServer2_0: failed to create session: %s", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.Version())
 	}
 }
 
@@ -2083,7 +2084,7 @@ func TestUnitNTPRefreshComputedStateLeavesWarning(t *testing.T) {
 		Port:            types.Int64Unknown(),
 	}
 
-	diags := r.refreshComputedStateLeaves(plan)
+	diags := r.refreshComputedStateLeaves(context.Background(), plan)
 
 	if diags.HasError() {
 		t.Fatalf("refreshComputedStateLeaves returned an error diag on GET failure; expected warning only. diags=%v", diags)
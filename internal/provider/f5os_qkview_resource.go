@@ -332,7 +332,7 @@ func normalizeQkviewFilename(name string) string {
 func (r *QkviewResource) qkviewExists(ctx context.Context, filename string) (bool, string, error) {
 	uri := "/openconfig-system:system/f5-system-diagnostics-qkview:diagnostics/qkview/list"
 
-	response, err := r.client.PostRequest(uri, nil)
+	response, err := r.client.WithContext(ctx).PostRequest(uri, nil)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to list qkviews: %w", err)
 	}
@@ -387,7 +387,7 @@ func (r *QkviewResource) createQkview(ctx context.Context, data *QkviewResourceM
 		return fmt.Errorf("failed to marshal capture request: %w", err)
 	}
 
-	_, err = r.client.PostRequest(uri, requestBody)
+	_, err = r.client.WithContext(ctx).PostRequest(uri, requestBody)
 	if err != nil {
 		return fmt.Errorf("failed to initiate qkview capture: %w", err)
 	}
@@ -403,24 +403,30 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 	statuses := []string{"collating", "collecting"}
 	messages := []string{"Collecting Data", "Collating data"}
 
-	// Poll for up to 30 minutes (180 attempts * 10 seconds default).
-	// Use the client's PollInterval when set (e.g. 1ms in unit tests)
-	// to avoid slow test suites.
+	// Poll for up to 180 attempts, 30 minutes at the 10 second default.
+	// f5os_qkview has no interval attribute of its own, so the provider's
+	// poll_interval applies when set.
 	maxAttempts := 180
-	pollInterval := r.client.PollInterval
-	if pollInterval <= 0 {
-		pollInterval = 10 * time.Second
+	pollInterval := resourcePollInterval(types.StringNull(), r.client, 10*time.Second)
+
+	client := r.client.WithContext(ctx)
+	start := time.Now()
+	budget := time.Duration(maxAttempts) * pollInterval
+	pause := func() error {
+		return pauseContext(ctx, pollInterval, fmt.Sprintf("qkview %q generation", filename), start, budget)
 	}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		response, err := r.client.PostRequest(uri, nil)
+		response, err := client.PostRequest(uri, nil)
 		if err != nil {
 			tflog.Error(ctx, fmt.Sprintf("Failed to check qkview status on attempt %d: %v", attempt+1, err))
 			// Don't fail immediately, retry a few times in case of transient network issues
 			if attempt >= 3 {
 				return "", fmt.Errorf("failed to check qkview status after %d attempts: %w", attempt+1, err)
 			}
-			time.Sleep(pollInterval)
+			if err := pause(); err != nil {
+				return "", err
+			}
 			continue
 		}
 
@@ -434,7 +440,9 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 			if attempt >= 3 {
 				return "", fmt.Errorf("failed to parse status response after %d attempts: %w", attempt+1, err)
 			}
-			time.Sleep(pollInterval)
+			if err := pause(); err != nil {
+				return "", err
+			}
 			continue
 		}
 
@@ -446,7 +454,9 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 			if attempt >= 3 {
 				return "", fmt.Errorf("failed to parse status result after %d attempts: %w", attempt+1, err)
 			}
-			time.Sleep(pollInterval)
+			if err := pause(); err != nil {
+				return "", err
+			}
 			continue
 		}
 
@@ -468,9 +478,11 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 				tflog.Warn(ctx, fmt.Sprintf("Failed to check if qkview exists: %v", err))
 				// For timeout scenarios, wait a bit longer for file system to catch up
 				if status.Status == "time-out" || strings.Contains(strings.ToLower(status.Message), "timed out") {
-					// In production this defaults to 10s; in unit tests the
-					// client's PollInterval is set to 1ms so tests stay fast.
-					time.Sleep(pollInterval)
+					// Wait one poll interval, 10s unless the provider's
+					// poll_interval says otherwise.
+					if err := pause(); err != nil {
+						return "", err
+					}
 					exists, generatedFile, err = r.qkviewExists(ctx, filename)
 					if err == nil && exists {
 						tflog.Info(ctx, fmt.Sprintf("Timeout qkview file found after delay: %s", generatedFile))
@@ -478,7 +490,9 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 					}
 				}
 				// Continue polling in case the file isn't ready yet
-				time.Sleep(pollInterval)
+				if err := pause(); err != nil {
+					return "", err
+				}
 				continue
 			}
 			if exists {
@@ -492,7 +506,9 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 					return fmt.Sprintf("%s.timedout", filename), nil
 				}
 				tflog.Debug(ctx, "Qkview file not found yet, continuing to poll")
-				time.Sleep(pollInterval)
+				if err := pause(); err != nil {
+					return "", err
+				}
 				continue
 			}
 		}
@@ -501,14 +517,18 @@ func (r *QkviewResource) waitForCompletion(ctx context.Context, filename string)
 		if status.Percent < 100 && (contains(statuses, status.Status) || contains(messages, status.Message) ||
 			strings.Contains(strings.ToLower(status.Status), "collect") ||
 			strings.Contains(strings.ToLower(status.Message), "collect")) {
-			time.Sleep(pollInterval)
+			if err := pause(); err != nil {
+				return "", err
+			}
 			continue
 		}
 
 		// If we get here and percent is less than 100, something might be wrong but continue polling
 		if status.Percent < 100 {
 			tflog.Warn(ctx, fmt.Sprintf("Unknown qkview status: %d%% - %s - %s, continuing to poll", status.Percent, status.Status, status.Message))
-			time.Sleep(pollInterval)
+			if err := pause(); err != nil {
+				return "", err
+			}
 			continue
 		}
 
@@ -532,7 +552,7 @@ func (r *QkviewResource) deleteQkview(ctx context.Context, filename string) erro
 		return fmt.Errorf("failed to marshal delete request: %w", err)
 	}
 
-	response, err := r.client.PostRequest(uri, requestBody)
+	response, err := r.client.WithContext(ctx).PostRequest(uri, requestBody)
 	if err != nil {
 		return fmt.Errorf("failed to delete qkview: %w", err)
 	}
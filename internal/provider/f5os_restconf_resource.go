@@ -0,0 +1,436 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// jsonObjectValidator checks that a string attribute is a JSON object.
+type jsonObjectValidator struct{}
+
+var _ validator.String = jsonObjectValidator{}
+
+func (v jsonObjectValidator) Description(ctx context.Context) string {
+	return "Ensures the value is a JSON object."
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	doc, err := decodeJSON([]byte(req.ConfigValue.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON", fmt.Sprintf("The value is not valid JSON: %s", err))
+		return
+	}
+	if _, ok := doc.(map[string]interface{}); !ok {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON", "The value must be a JSON object.")
+	}
+}
+
+// semanticJSONUnchanged is a plan modifier for payload. It keeps the prior
+// state value when the configuration is the same JSON written differently
+// (whitespace, member order, 5 for "5", module prefixes), so reformatting
+// the payload does not plan an update.
+type semanticJSONUnchanged struct{}
+
+func (m semanticJSONUnchanged) Description(_ context.Context) string {
+	return "Keep the prior state value when the configured JSON is semantically equal to it."
+}
+
+func (m semanticJSONUnchanged) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m semanticJSONUnchanged) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	cfg, err := decodeJSON([]byte(req.ConfigValue.ValueString()))
+	if err != nil {
+		return
+	}
+	state, err := decodeJSON([]byte(req.StateValue.ValueString()))
+	if err != nil {
+		return
+	}
+	if jsonEqual(cfg, state) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &RestconfResource{}
+	_ resource.ResourceWithConfigure   = &RestconfResource{}
+	_ resource.ResourceWithImportState = &RestconfResource{}
+)
+
+// RestconfResourceModel represents the schema model
+type RestconfResourceModel struct {
+	Id           types.String `tfsdk:"id"`
+	Path         types.String `tfsdk:"path"`
+	Payload      types.String `tfsdk:"payload"`
+	CreateMethod types.String `tfsdk:"create_method"`
+	UpdateMethod types.String `tfsdk:"update_method"`
+	DeleteMethod types.String `tfsdk:"delete_method"`
+	IgnoreFields types.List   `tfsdk:"ignore_fields"`
+}
+
+// RestconfResource manages an arbitrary RESTCONF object, for YANG paths
+// that have no dedicated resource.
+type RestconfResource struct {
+	client *f5os.F5os
+}
+
+// NewRestconfResource creates a new instance of the resource
+func NewRestconfResource() resource.Resource {
+	return &RestconfResource{}
+}
+
+// Metadata returns the resource type name
+func (r *RestconfResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_restconf"
+}
+
+// Schema defines the schema for the resource
+func (r *RestconfResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource used to manage any RESTCONF object on F5OS systems (VELOS or rSeries), for YANG paths that have no dedicated resource.\n\n" +
+			"The `payload` is the object as a GET of `path` returns it, for example `{\"openconfig-vlan:vlan\": [...]}` for `/openconfig-vlan:vlans/vlan=10`. " +
+			"On refresh only the leaves set in `payload` are compared with the device, so leaves the device fills in itself do not cause a diff.\n\n" +
+			"~> **NOTE:** The resource writes exactly what `payload` holds. It cannot check the payload against the device's YANG model before apply, so prefer a dedicated resource where one exists.\n\n" +
+			"Not supported with the provider's `transport` set to `netconf`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The RESTCONF path of the object.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "RESTCONF path of the object below the data root, e.g. `/openconfig-vlan:vlans/vlan=10`. List keys must be URL encoded.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^/\S+$`), "must start with / and contain no spaces"),
+				},
+			},
+			"payload": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The object as a JSON document. Use `jsonencode()` to build it.",
+				PlanModifiers: []planmodifier.String{
+					semanticJSONUnchanged{},
+				},
+				Validators: []validator.String{
+					jsonObjectValidator{},
+				},
+			},
+			"create_method": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("PATCH"),
+				MarkdownDescription: "Method used to create the object: `PATCH` (merge, the default), `PUT` (replace) or `POST` (create under the parent of `path`, failing if the object exists).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("POST", "PUT", "PATCH"),
+				},
+			},
+			"update_method": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("PATCH"),
+				MarkdownDescription: "Method used to update the object: `PATCH` (merge, the default) or `PUT` (replace).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("PUT", "PATCH"),
+				},
+			},
+			"delete_method": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("DELETE"),
+				MarkdownDescription: "`DELETE` (the default) deletes the object on destroy; `NONE` only removes it from Terraform state, for objects such as system settings that cannot be deleted.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("DELETE", "NONE"),
+				},
+			},
+			"ignore_fields": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: "Leaves of `payload` whose device value is never compared, as `/`-separated paths of node names from the top of the payload, e.g. `vlan/config/name`. " +
+					"Use it for leaves the device rewrites, such as hashed secrets.",
+			},
+		},
+	}
+}
+
+// Configure configures the resource with the provider client
+func (r *RestconfResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*f5os.F5os)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *f5os.F5os, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	rejectNetconfTransport(client, "f5os_restconf", &resp.Diagnostics)
+
+	r.client = client
+}
+
+// restconfParent returns the path of the parent of uri, which a POST
+// creating uri is sent to. The parent of a top-level node is the data
+// root.
+func restconfParent(uri string) string {
+	return uri[:strings.LastIndex(uri, "/")]
+}
+
+// write sends payload to uri with method, treating an error document in
+// the response as a failure.
+func (r *RestconfResource) write(ctx context.Context, method, uri string, payload []byte) error {
+	client := r.client.WithContext(ctx)
+	var body []byte
+	var err error
+	switch method {
+	case "POST":
+		body, err = client.PostRequest(restconfParent(uri), payload)
+	case "PUT":
+		body, err = client.PutRequest(uri, payload)
+	default:
+		body, err = client.PatchRequest(uri, payload)
+	}
+	if err != nil {
+		return err
+	}
+	return restconfBodyError(body)
+}
+
+// readObject reads the object at uri. found is false when the device
+// reports that the path does not exist.
+func readObject(ctx context.Context, client *f5os.F5os, uri string) (doc interface{}, found bool, err error) {
+	body, err := client.WithContext(ctx).GetRequest(uri)
+	if err != nil {
+		return nil, false, err
+	}
+	if errs := restconfBodyErrors(body); len(errs) > 0 {
+		if restconfMissing(errs) {
+			return nil, false, nil
+		}
+		return nil, false, restconfBodyError(body)
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		// A container with nothing set reads back empty.
+		return map[string]interface{}{}, true, nil
+	}
+	doc, err = decodeJSON(body)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid JSON in response: %w", err)
+	}
+	return doc, true, nil
+}
+
+// ignoredFields returns the ignore_fields of m as a set of local paths.
+func ignoredFields(ctx context.Context, m *RestconfResourceModel) (map[string]bool, diag.Diagnostics) {
+	fields, diags := extractStringList(ctx, m.IgnoreFields)
+	ignore := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		ignore[jsonLocalPath(f)] = true
+	}
+	return ignore, diags
+}
+
+// Create creates the resource and sets the initial Terraform state
+func (r *RestconfResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RestconfResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := plan.Path.ValueString()
+	method := plan.CreateMethod.ValueString()
+	tflog.Info(ctx, "Creating RESTCONF object", map[string]interface{}{"path": uri, "method": method})
+
+	if err := r.write(ctx, method, uri, []byte(plan.Payload.ValueString())); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err,
+			"RESTCONF Create Error",
+			fmt.Sprintf("Failed to %s %s: %s", method, uri, err),
+		)
+		return
+	}
+	plan.Id = types.StringValue(uri)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.checkApplied(ctx, &plan, &resp.Diagnostics)
+}
+
+// checkApplied reads the object back after a write and warns about
+// configured leaves the device did not take, such as values it
+// normalizes. The next refresh reports them as drift.
+func (r *RestconfResource) checkApplied(ctx context.Context, m *RestconfResourceModel, diags *diag.Diagnostics) {
+	uri := m.Path.ValueString()
+	doc, found, err := readObject(ctx, r.client, uri)
+	if err != nil {
+		addClientErrorDiagnostic(diags, nil, err,
+			"RESTCONF Read Error",
+			fmt.Sprintf("Failed to read %s back after writing it: %s", uri, err),
+		)
+		return
+	}
+	if !found {
+		diags.AddWarning("RESTCONF Object Not Found",
+			fmt.Sprintf("The device accepted the payload, but %s does not exist when read back. Check that path names the object the payload creates.", uri))
+		return
+	}
+	ignore, d := ignoredFields(ctx, m)
+	diags.Append(d...)
+	cfg, err := decodeJSON([]byte(m.Payload.ValueString()))
+	if err != nil {
+		return
+	}
+	if diffs := jsonDifferences(cfg, projectJSON(cfg, doc, ignore)); len(diffs) > 0 {
+		diags.AddWarning("RESTCONF Object Differs From Payload",
+			fmt.Sprintf("The device accepted the payload, but %s reads back with different values at: %s. "+
+				"The next plan will show these as changes; list leaves the device rewrites in ignore_fields.", uri, strings.Join(diffs, ", ")))
+	}
+}
+
+// Read refreshes the Terraform state with the latest data
+func (r *RestconfResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RestconfResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := state.Path.ValueString()
+	doc, found, err := readObject(ctx, r.client, uri)
+	if err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err,
+			"RESTCONF Read Error",
+			fmt.Sprintf("Failed to read %s: %s", uri, err),
+		)
+		return
+	}
+	if !found {
+		tflog.Warn(ctx, "RESTCONF object not found, removing from state", map[string]interface{}{"path": uri})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// An imported object has no payload yet; take the whole object.
+	if state.Payload.IsNull() || state.Payload.ValueString() == "" {
+		state.Payload = types.StringValue(compactJSON(doc))
+	} else {
+		cfg, err := decodeJSON([]byte(state.Payload.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError("RESTCONF Read Error", fmt.Sprintf("Invalid payload in state: %s", err))
+			return
+		}
+		ignore, diags := ignoredFields(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if projected := projectJSON(cfg, doc, ignore); !jsonEqual(cfg, projected) {
+			tflog.Debug(ctx, "RESTCONF object drifted", map[string]interface{}{"path": uri, "fields": jsonDifferences(cfg, projected)})
+			state.Payload = types.StringValue(compactJSON(projected))
+		}
+	}
+
+	state.Id = types.StringValue(uri)
+	if state.CreateMethod.IsNull() {
+		state.CreateMethod = types.StringValue("PATCH")
+	}
+	if state.UpdateMethod.IsNull() {
+		state.UpdateMethod = types.StringValue("PATCH")
+	}
+	if state.DeleteMethod.IsNull() {
+		state.DeleteMethod = types.StringValue("DELETE")
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success
+func (r *RestconfResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RestconfResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := plan.Path.ValueString()
+	method := plan.UpdateMethod.ValueString()
+	tflog.Info(ctx, "Updating RESTCONF object", map[string]interface{}{"path": uri, "method": method})
+
+	if err := r.write(ctx, method, uri, []byte(plan.Payload.ValueString())); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err,
+			"RESTCONF Update Error",
+			fmt.Sprintf("Failed to %s %s: %s", method, uri, err),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.checkApplied(ctx, &plan, &resp.Diagnostics)
+}
+
+// Delete deletes the resource and removes the Terraform state on success
+func (r *RestconfResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RestconfResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := state.Path.ValueString()
+	if state.DeleteMethod.ValueString() == "NONE" {
+		tflog.Info(ctx, "Removing RESTCONF object from state only", map[string]interface{}{"path": uri})
+		return
+	}
+	tflog.Info(ctx, "Deleting RESTCONF object", map[string]interface{}{"path": uri})
+	if err := r.client.WithContext(ctx).DeleteRequest(uri); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err,
+			"RESTCONF Delete Error",
+			fmt.Sprintf("Failed to delete %s: %s", uri, err),
+		)
+	}
+}
+
+// ImportState imports an object by its RESTCONF path. Its payload is the
+// whole object as the device returns it.
+func (r *RestconfResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !strings.HasPrefix(req.ID, "/") {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected a RESTCONF path starting with /, got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), req.ID)...)
+}
@@ -0,0 +1,303 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+func mustDecodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	v, err := decodeJSON([]byte(s))
+	if err != nil {
+		t.Fatalf("decodeJSON(%s): %v", s, err)
+	}
+	return v
+}
+
+func TestUnitRestconfProjectJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		configured string
+		device     string
+		ignore     []string
+		equal      bool
+		diffs      []string
+	}{
+		{
+			name:       "server populated leaves",
+			configured: `{"openconfig-vlan:vlan":[{"vlan-id":"10","config":{"vlan-id":10,"name":"web"}}]}`,
+			device:     `{"openconfig-vlan:vlan":[{"vlan-id":10,"config":{"vlan-id":10,"name":"web"},"members":{"member":[]}}]}`,
+			equal:      true,
+		},
+		{
+			name:       "module prefixes",
+			configured: `{"f5-system-snmp:snmp":{"f5-system-snmp:config":{"contact":"ops"}}}`,
+			device:     `{"f5-system-snmp:snmp":{"config":{"contact":"ops","location":"dc1"}}}`,
+			equal:      true,
+		},
+		{
+			name:       "extra list entries",
+			configured: `{"servers":{"server":[{"address":"10.0.0.2"}]}}`,
+			device:     `{"servers":{"server":[{"address":"10.0.0.1"},{"address":"10.0.0.2","port":53}]}}`,
+			equal:      true,
+		},
+		{
+			name:       "changed leaf",
+			configured: `{"config":{"name":"web","mtu":9000}}`,
+			device:     `{"config":{"name":"web","mtu":1500}}`,
+			diffs:      []string{"/config/mtu"},
+		},
+		{
+			name:       "missing leaf and list entry",
+			configured: `{"config":{"name":"web","members":["a","b"]}}`,
+			device:     `{"config":{"members":["a"]}}`,
+			diffs:      []string{"/config/members", "/config/name"},
+		},
+		{
+			name:       "changed leaf in list entry",
+			configured: `{"server":[{"address":"a","config":{"port":53}}]}`,
+			device:     `{"server":[{"address":"b","config":{"port":53}},{"address":"a","config":{"port":5353}}]}`,
+			diffs:      []string{"/server/config/port"},
+		},
+		{
+			name:       "ignored leaf",
+			configured: `{"user":[{"name":"u1","config":{"password":"secret"}}]}`,
+			device:     `{"user":[{"name":"u1","config":{"password":"$6$hashed"}}]}`,
+			ignore:     []string{"f5-openconfig-aaa:user/config/password"},
+			equal:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := mustDecodeJSON(t, tc.configured)
+			ignore := map[string]bool{}
+			for _, f := range tc.ignore {
+				ignore[jsonLocalPath(f)] = true
+			}
+			projected := projectJSON(cfg, mustDecodeJSON(t, tc.device), ignore)
+			if got := jsonEqual(cfg, projected); got != tc.equal {
+				t.Fatalf("jsonEqual = %v, want %v (projection %s)", got, tc.equal, compactJSON(projected))
+			}
+			if diffs := jsonDifferences(cfg, projected); strings.Join(diffs, ",") != strings.Join(tc.diffs, ",") {
+				t.Fatalf("differences = %v, want %v", diffs, tc.diffs)
+			}
+		})
+	}
+}
+
+func TestUnitRestconfSemanticJSONPlan(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		config, state string
+		keep          bool
+	}{
+		{`{"a": {"b": 1, "c": "x"}}`, `{"a":{"c":"x","b":"1"}}`, true},
+		{`{"m:a":{"b":true}}`, `{"a":{"b":true}}`, true},
+		{`{"a":{"b":1}}`, `{"a":{"b":2}}`, false},
+		{`{"a":[1,2]}`, `{"a":[2,1]}`, false},
+	} {
+		req := planmodifier.StringRequest{
+			ConfigValue: types.StringValue(tc.config),
+			PlanValue:   types.StringValue(tc.config),
+			StateValue:  types.StringValue(tc.state),
+		}
+		resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+		semanticJSONUnchanged{}.PlanModifyString(ctx, req, resp)
+		if kept := resp.PlanValue.Equal(req.StateValue); kept != tc.keep {
+			t.Errorf("config %s, state %s: kept state = %v, want %v", tc.config, tc.state, kept, tc.keep)
+		}
+	}
+}
+
+func restconfTestModel(uri, payload, createMethod string) *RestconfResourceModel {
+	return &RestconfResourceModel{
+		Id:           types.StringUnknown(),
+		Path:         types.StringValue(uri),
+		Payload:      types.StringValue(payload),
+		CreateMethod: types.StringValue(createMethod),
+		UpdateMethod: types.StringValue("PATCH"),
+		DeleteMethod: types.StringValue("DELETE"),
+		IgnoreFields: types.ListNull(types.StringType),
+	}
+}
+
+// TestUnitRestconfResourceLifecycle creates a VLAN with POST, detects a
+// change made on the device, updates it back and deletes it.
+func TestUnitRestconfResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	r := &RestconfResource{client: client}
+
+	const uri = "/openconfig-vlan:vlans/vlan=310"
+	payload := `{"openconfig-vlan:vlan": [{"vlan-id": "310", "config": {"vlan-id": 310, "name": "web"}}]}`
+	m := restconfTestModel(uri, payload, "POST")
+	createResp := testResourceCreate(t, r, m, nil)
+	if createResp.Diagnostics.HasError() || createResp.Diagnostics.WarningsCount() > 0 {
+		t.Fatalf("Create returned diagnostics: %v", createResp.Diagnostics)
+	}
+	requests := server.Requests()
+	if !contains(requests, "POST /restconf/data/openconfig-vlan:vlans") {
+		t.Fatalf("expected a POST to the parent, got %v", requests)
+	}
+
+	var got RestconfResourceModel
+	if resp := testResourceRead(t, r, createResp.State, &got); resp.Diagnostics.HasError() || got.Payload.ValueString() != payload || got.Id.ValueString() != uri {
+		t.Fatalf("expected the configured payload to be kept, got %+v, %v", got, resp.Diagnostics)
+	}
+
+	if err := server.Patch("openconfig-vlan:vlans/vlan=310/config", []byte(`{"openconfig-vlan:config":{"name":"changed"}}`)); err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if resp := testResourceRead(t, r, createResp.State, &got); resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	if want := `{"openconfig-vlan:vlan":[{"config":{"name":"changed","vlan-id":310},"vlan-id":"310"}]}`; got.Payload.ValueString() != want {
+		t.Fatalf("expected drift in payload\n got %s\nwant %s", got.Payload.ValueString(), want)
+	}
+
+	plan := testResourceState(t, r, m)
+	updateResp := &fwresource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(plan), State: createResp.State}, updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update returned diagnostics: %v", updateResp.Diagnostics)
+	}
+	if body, err := server.Get("openconfig-vlan:vlans/vlan=310/config/name"); err != nil || !strings.Contains(string(body), `"web"`) {
+		t.Fatalf("expected the name to be restored, got %s, %v", body, err)
+	}
+
+	deleteResp := &fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete returned diagnostics: %v", deleteResp.Diagnostics)
+	}
+	if resp := testResourceRead(t, r, updateResp.State, nil); resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Fatalf("expected the resource to be removed from state, got %v, %v", resp.State.Raw, resp.Diagnostics)
+	}
+}
+
+// TestUnitRestconfResourceImport verifies that an imported object takes
+// the whole device object as its payload.
+func TestUnitRestconfResourceImport(t *testing.T) {
+	client, _ := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	r := &RestconfResource{client: client}
+	m := restconfTestModel("/openconfig-system:system/config", "", "PATCH")
+	m.Payload = types.StringNull()
+	m.CreateMethod, m.UpdateMethod, m.DeleteMethod = types.StringNull(), types.StringNull(), types.StringNull()
+
+	var got RestconfResourceModel
+	if resp := testResourceRead(t, r, testResourceState(t, r, m), &got); resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	doc := mustDecodeJSON(t, got.Payload.ValueString())
+	if _, ok := jsonMember(doc.(map[string]interface{}), "config"); !ok {
+		t.Fatalf("expected the system config in the payload, got %s", got.Payload.ValueString())
+	}
+	if got.CreateMethod.ValueString() != "PATCH" || got.DeleteMethod.ValueString() != "DELETE" {
+		t.Fatalf("expected default methods, got %q %q", got.CreateMethod.ValueString(), got.DeleteMethod.ValueString())
+	}
+}
+
+// TestUnitRestconfResourceCreateWarnsOnDifference verifies that Create
+// warns about leaves the device did not take as sent.
+func TestUnitRestconfResourceCreateWarnsOnDifference(t *testing.T) {
+	testAccPreUnitCheck(t)
+	defer teardown()
+	setupVlanCommonMock(t)
+	mux.HandleFunc("/restconf/data/f5-example:widgets/widget=w1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"f5-example:widget":[{"name":"w1","config":{"name":"w1","size":"small","serial":"X1"}}]}`)
+	})
+	client, err := newTestClientFromEnv()
+	if err != nil {
+		t.Fatalf("failed to create test client against mock: %s", err)
+	}
+	resp := testResourceCreate(t, &RestconfResource{client: client}, restconfTestModel("/f5-example:widgets/widget=w1",
+		`{"f5-example:widget":[{"name":"w1","config":{"name":"w1","size":"large"}}]}`, "PATCH"), nil)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+	}
+	if detail := resp.Diagnostics.Warnings()[0].Detail(); !strings.Contains(detail, "/widget/config/size") {
+		t.Fatalf("expected the differing leaf in the warning, got %q", detail)
+	}
+}
+
+// TestUnitRestconfResourceCreateError verifies that a rejected write is
+// reported with the device's message.
+func TestUnitRestconfResourceCreateError(t *testing.T) {
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	client.Retry = &f5os.RetryPolicy{MaxAttempts: 1}
+	server.FailNext("PUT", "openconfig-vlan:vlans/vlan=320", http.StatusBadRequest, "bad vlan")
+
+	resp := testResourceCreate(t, &RestconfResource{client: client}, restconfTestModel("/openconfig-vlan:vlans/vlan=320",
+		`{"openconfig-vlan:vlan":[{"vlan-id":320,"config":{"vlan-id":320}}]}`, "PUT"), nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "bad vlan") {
+		t.Fatalf("expected the device error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestUnitRestconfResource(t *testing.T) {
+	testAccPreUnitCheck(t)
+	setupVlanCommonMock(t)
+	created := false
+	mux.HandleFunc("/restconf/data/openconfig-vlan:vlans/vlan=330", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PATCH":
+			created = true
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE":
+			created = false
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			if !created {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(w, `{"ietf-restconf:errors":{"error":[{"error-type":"application","error-tag":"invalid-value","error-message":"uri keypath not found"}]}}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"openconfig-vlan:vlan":[{"vlan-id":330,"config":{"vlan-id":330,"name":"app"},"members":{}}]}`)
+		}
+	})
+	defer teardown()
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRestconfResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("f5os_restconf.vlan", "id", "/openconfig-vlan:vlans/vlan=330"),
+					resource.TestCheckResourceAttr("f5os_restconf.vlan", "create_method", "PATCH"),
+					resource.TestCheckResourceAttr("f5os_restconf.vlan", "delete_method", "DELETE"),
+				),
+			},
+			{
+				Config:   testAccRestconfResourceConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccRestconfResourceConfig = `
+resource "f5os_restconf" "vlan" {
+  path = "/openconfig-vlan:vlans/vlan=330"
+  payload = jsonencode({
+    "openconfig-vlan:vlan" = [{
+      "vlan-id" = "330"
+      config    = { "vlan-id" = 330, name = "app" }
+    }]
+  })
+}
+`
@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// jsonPathValidator checks that a string attribute is a JSONPath
+// expression parseJSONPath accepts.
+type jsonPathValidator struct{}
+
+var _ validator.String = jsonPathValidator{}
+
+func (v jsonPathValidator) Description(ctx context.Context) string {
+	return "Ensures the value is a JSONPath expression starting with $."
+}
+
+func (v jsonPathValidator) MarkdownDescription(ctx context.Context) string {
+	return "Ensures the value is a JSONPath expression starting with `$`."
+}
+
+func (v jsonPathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parseJSONPath(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSONPath", err.Error())
+	}
+}
+
+// replaceOnMapChange is a plan modifier that replaces the resource when a
+// map attribute changes, as stringplanmodifier.RequiresReplace does for
+// strings; the framework version in use has no map equivalent.
+type replaceOnMapChange struct{}
+
+func (m replaceOnMapChange) Description(_ context.Context) string {
+	return "Replace the resource when the value changes."
+}
+
+func (m replaceOnMapChange) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m replaceOnMapChange) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	// Nothing to replace on create or destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	if !req.PlanValue.Equal(req.StateValue) {
+		resp.RequiresReplace = true
+	}
+}
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource              = &RpcResource{}
+	_ resource.ResourceWithConfigure = &RpcResource{}
+)
+
+// RpcResourceModel represents the schema model
+type RpcResourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	Path             types.String `tfsdk:"path"`
+	Input            types.String `tfsdk:"input"`
+	Triggers         types.Map    `tfsdk:"triggers"`
+	StatusPath       types.String `tfsdk:"status_path"`
+	StatusCondition  types.String `tfsdk:"status_condition"`
+	FailureCondition types.String `tfsdk:"failure_condition"`
+	PollInterval     types.String `tfsdk:"poll_interval"`
+	Timeout          types.Int64  `tfsdk:"timeout"`
+	Output           types.String `tfsdk:"output"`
+	Status           types.String `tfsdk:"status"`
+}
+
+// RpcResource invokes a RESTCONF action or RPC. The call is made on create
+// and again whenever the resource is replaced, which a change of path,
+// input or triggers forces.
+type RpcResource struct {
+	client *f5os.F5os
+}
+
+// NewRpcResource creates a new instance of the resource
+func NewRpcResource() resource.Resource {
+	return &RpcResource{}
+}
+
+// Metadata returns the resource type name
+func (r *RpcResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rpc"
+}
+
+// Schema defines the schema for the resource
+func (r *RpcResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource used to invoke a RESTCONF action or RPC on F5OS systems (VELOS or rSeries), such as a licensing, diagnostics or database operation that has no dedicated resource.\n\n" +
+			"The operation is POSTed on create, and again whenever `path`, `input` or `triggers` change. " +
+			"It can then poll `status_path` until `status_condition` selects a value.\n\n" +
+			"~> **NOTE:** Destroying the resource only removes it from Terraform state; the effect of the operation is not undone.\n\n" +
+			"Not supported with the provider's `transport` set to `netconf`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Unique identifier of this invocation.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "RESTCONF path of the action below the data root, e.g. `/openconfig-system:system/f5-database:database/f5-database:reset-to-default`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^/\S+$`), "must start with / and contain no spaces"),
+				},
+			},
+			"input": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Input of the operation as a JSON object, e.g. `jsonencode({ \"f5-database:proceed\" = \"yes\" })`. Omit it for operations without input.",
+				PlanModifiers: []planmodifier.String{
+					semanticJSONUnchanged{},
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					jsonObjectValidator{},
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Arbitrary values that re-run the operation when any of them changes.",
+				PlanModifiers: []planmodifier.Map{
+					replaceOnMapChange{},
+				},
+			},
+			"status_path": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "RESTCONF path read after the operation until `status_condition` holds.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^/\S+$`), "must start with / and contain no spaces"),
+					stringvalidator.AlsoRequires(path.MatchRoot("status_condition")),
+				},
+			},
+			"status_condition": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "JSONPath evaluated on each read of `status_path`; the operation is complete once it selects a value, " +
+					"e.g. `$..transfer-operation[?(@.status == 'Completed')]`. A path that does not exist yet is read again.",
+				Validators: []validator.String{
+					jsonPathValidator{},
+					stringvalidator.AlsoRequires(path.MatchRoot("status_path")),
+				},
+			},
+			"failure_condition": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "JSONPath evaluated on each read of `status_path`; the operation has failed once it selects a value, e.g. `$..transfer-operation[?(@.status == 'Failed')]`.",
+				Validators: []validator.String{
+					jsonPathValidator{},
+					stringvalidator.AlsoRequires(path.MatchRoot("status_path")),
+				},
+			},
+			"poll_interval": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Time between reads of `status_path`. When unset, the provider's `poll_interval` is used, and `10s` if that is unset too.",
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(600),
+				MarkdownDescription: "Time in seconds to wait for `status_condition`. Default is 600.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"output": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Output of the operation as JSON. Use `jsondecode()` to access it.",
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The last read of `status_path` as JSON, when one is set.",
+			},
+		},
+	}
+}
+
+// Configure configures the resource with the provider client
+func (r *RpcResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*f5os.F5os)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *f5os.F5os, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	rejectNetconfTransport(client, "f5os_rpc", &resp.Diagnostics)
+
+	r.client = client
+}
+
+// Create invokes the operation and waits for it to complete
+func (r *RpcResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RpcResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := plan.Path.ValueString()
+	var input []byte
+	if !plan.Input.IsNull() {
+		input = []byte(plan.Input.ValueString())
+	}
+	tflog.Info(ctx, "Invoking RESTCONF operation", map[string]interface{}{"path": uri})
+	body, err := r.client.WithContext(ctx).PostRequest(uri, input)
+	if err == nil {
+		err = restconfBodyError(body)
+	}
+	if err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err,
+			"RESTCONF Operation Error",
+			fmt.Sprintf("Failed to invoke %s: %s", uri, err),
+		)
+		return
+	}
+	output := strings.TrimSpace(string(body))
+	if output == "" {
+		output = "{}"
+	}
+	plan.Output = types.StringValue(output)
+	plan.Status = types.StringNull()
+	plan.Id = types.StringValue(uuid.NewString())
+
+	if !plan.StatusPath.IsNull() {
+		status, err := r.waitForStatus(ctx, &plan)
+		if status != "" {
+			plan.Status = types.StringValue(status)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("RESTCONF Operation Error", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// waitForStatus reads status_path until status_condition or
+// failure_condition selects a value, and returns the last status read.
+func (r *RpcResource) waitForStatus(ctx context.Context, plan *RpcResourceModel) (string, error) {
+	uri := plan.StatusPath.ValueString()
+	done, err := parseJSONPath(plan.StatusCondition.ValueString())
+	if err != nil {
+		return "", err
+	}
+	var failed jsonPath
+	if !plan.FailureCondition.IsNull() {
+		if failed, err = parseJSONPath(plan.FailureCondition.ValueString()); err != nil {
+			return "", err
+		}
+	}
+	interval := resourcePollInterval(plan.PollInterval, r.client, 10*time.Second)
+	timeout := time.Duration(plan.Timeout.ValueInt64()) * time.Second
+
+	start := time.Now()
+	last := ""
+	for {
+		doc, found, err := readObject(ctx, r.client, uri)
+		if err != nil {
+			return last, fmt.Errorf("failed to read %s: %w", uri, err)
+		}
+		if found {
+			last = compactJSON(doc)
+			tflog.Debug(ctx, "RESTCONF operation status", map[string]interface{}{"path": uri, "status": last})
+			if failed != nil && len(failed.eval(doc)) > 0 {
+				return last, fmt.Errorf("the operation failed: %s matched failure_condition. Status: %s", uri, last)
+			}
+			if len(done.eval(doc)) > 0 {
+				return last, nil
+			}
+		}
+		if time.Since(start)+interval > timeout {
+			if last == "" {
+				return last, fmt.Errorf("timed out after %s waiting for %s to exist", timeout, uri)
+			}
+			return last, fmt.Errorf("timed out after %s waiting for status_condition on %s. Last status: %s", timeout, uri, last)
+		}
+		if err := pauseContext(ctx, interval, fmt.Sprintf("status of %s", plan.Path.ValueString()), start, timeout); err != nil {
+			return last, err
+		}
+	}
+}
+
+// Read keeps the recorded result; an invocation has nothing to refresh.
+func (r *RpcResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RpcResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update stores changes to the polling settings, which apply to the next
+// invocation. Every other change replaces the resource.
+func (r *RpcResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state RpcResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Id = state.Id
+	plan.Output = state.Output
+	plan.Status = state.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the resource from state
+func (r *RpcResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Info(ctx, "Removing RESTCONF operation from Terraform state")
+}
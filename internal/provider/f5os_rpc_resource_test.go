@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+func rpcTestModel(uri, input string) *RpcResourceModel {
+	m := &RpcResourceModel{
+		Id:               types.StringUnknown(),
+		Path:             types.StringValue(uri),
+		Input:            types.StringNull(),
+		Triggers:         types.MapNull(types.StringType),
+		StatusPath:       types.StringNull(),
+		StatusCondition:  types.StringNull(),
+		FailureCondition: types.StringNull(),
+		PollInterval:     types.StringValue("1ms"),
+		Timeout:          types.Int64Value(5),
+		Output:           types.StringUnknown(),
+		Status:           types.StringUnknown(),
+	}
+	if input != "" {
+		m.Input = types.StringValue(input)
+	}
+	return m
+}
+
+// TestUnitRpcResourcePollsStatus starts an image import on the emulator
+// and waits for the transfer to complete.
+func TestUnitRpcResourcePollsStatus(t *testing.T) {
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	r := &RpcResource{client: client}
+
+	m := rpcTestModel("/f5-utils-file-transfer:file/import",
+		`{"f5-utils-file-transfer:remote-host":"files.example.net","f5-utils-file-transfer:remote-file":"/images/BIGIP-17.1.0.ALL-F5OS.qcow2.zip.bundle","f5-utils-file-transfer:local-file":"images/tenant","f5-utils-file-transfer:protocol":"https"}`)
+	m.StatusPath = types.StringValue("/f5-utils-file-transfer:file/transfer-operations")
+	m.StatusCondition = types.StringValue("$..transfer-operation[?(@.status == 'Completed')]")
+	var got RpcResourceModel
+	resp := testResourceCreate(t, r, m, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create returned diagnostics: %v", resp.Diagnostics)
+	}
+	if !strings.Contains(got.Output.ValueString(), `"operation-id"`) {
+		t.Fatalf("expected the operation output, got %s", got.Output.ValueString())
+	}
+	if !strings.Contains(got.Status.ValueString(), `"Completed"`) || got.Id.ValueString() == "" {
+		t.Fatalf("expected a completed status, got %s (id %q)", got.Status.ValueString(), got.Id.ValueString())
+	}
+	polls := 0
+	for _, req := range server.Requests() {
+		if req == "GET /restconf/data/f5-utils-file-transfer:file/transfer-operations" {
+			polls++
+		}
+	}
+	if polls < 2 {
+		t.Fatalf("expected the status to be polled until complete, got %v", server.Requests())
+	}
+}
+
+// TestUnitRpcResourcePollIntervalPrecedence verifies that the resource's
+// poll_interval wins over the provider's, which applies only when the
+// resource's configuration leaves it out.
+func TestUnitRpcResourcePollIntervalPrecedence(t *testing.T) {
+	for name, tc := range map[string]struct {
+		resource string
+		provider string
+	}{
+		"both set":      {"1ms", "1h"},
+		"provider only": {"", "1ms"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, err := f5osemu.NewServer(f5osemu.Options{Platform: f5osemu.RSeries})
+			if err != nil {
+				t.Fatalf("NewServer failed: %v", err)
+			}
+			defer server.Close()
+			config := map[string]tftypes.Value{
+				"path":             tftypes.NewValue(tftypes.String, "/f5-utils-file-transfer:file/import"),
+				"input":            tftypes.NewValue(tftypes.String, `{"f5-utils-file-transfer:remote-host":"files.example.net","f5-utils-file-transfer:remote-file":"/images/BIGIP-17.1.0.ALL-F5OS.qcow2.zip.bundle","f5-utils-file-transfer:local-file":"images/tenant","f5-utils-file-transfer:protocol":"https"}`),
+				"status_path":      tftypes.NewValue(tftypes.String, "/f5-utils-file-transfer:file/transfer-operations"),
+				"status_condition": tftypes.NewValue(tftypes.String, "$..transfer-operation[?(@.status == 'Completed')]"),
+				"timeout":          tftypes.NewValue(tftypes.Number, 5),
+			}
+			if tc.resource != "" {
+				config["poll_interval"] = tftypes.NewValue(tftypes.String, tc.resource)
+			}
+			// A one hour interval would not fit in the timeout, so the
+			// wait completes only at the millisecond interval.
+			state, diags := testProtoCreate(t, map[string]tftypes.Value{
+				"host":          tftypes.NewValue(tftypes.String, server.URL),
+				"username":      tftypes.NewValue(tftypes.String, "admin"),
+				"password":      tftypes.NewValue(tftypes.String, "admin"),
+				"poll_interval": tftypes.NewValue(tftypes.String, tc.provider),
+			}, "f5os_rpc", config)
+			if len(diags) > 0 {
+				t.Fatalf("create returned diagnostics: %s: %s", diags[0].Summary, diags[0].Detail)
+			}
+			var values map[string]tftypes.Value
+			if err := state.As(&values); err != nil {
+				t.Fatalf("reading the new state failed: %v", err)
+			}
+			if tc.resource == "" && !values["poll_interval"].IsNull() {
+				t.Fatalf("expected poll_interval to stay null, got %s", values["poll_interval"])
+			}
+		})
+	}
+}
+
+func TestUnitRpcResourceFailureCondition(t *testing.T) {
+	testAccPreUnitCheck(t)
+	defer teardown()
+	setupVlanCommonMock(t)
+	mux.HandleFunc("/restconf/data/openconfig-system:system/f5-database:database/reset-to-default", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"f5-database:output":{"result":"Started"}}`)
+	})
+	mux.HandleFunc("/restconf/data/openconfig-system:system/f5-database:database/state", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"f5-database:state":{"reset-status":"Failed: database busy"}}`)
+	})
+	client, err := newTestClientFromEnv()
+	if err != nil {
+		t.Fatalf("failed to create test client against mock: %s", err)
+	}
+	m := rpcTestModel("/openconfig-system:system/f5-database:database/reset-to-default", `{"f5-database:proceed":"yes"}`)
+	m.StatusPath = types.StringValue("/openconfig-system:system/f5-database:database/state")
+	m.StatusCondition = types.StringValue("$[?(@.reset-status == 'Complete')]")
+	m.FailureCondition = types.StringValue("$[?(@.reset-status != 'Complete')]")
+	resp := testResourceCreate(t, &RpcResource{client: client}, m, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "failure_condition") ||
+		!strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "database busy") {
+		t.Fatalf("expected the failed status in the error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestUnitRpcResourceTimeout(t *testing.T) {
+	client, _ := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	m := rpcTestModel("/f5-utils-file-transfer:file/import",
+		`{"remote-host":"files.example.net","remote-file":"/images/other.qcow2.zip.bundle","local-file":"images/tenant","protocol":"https"}`)
+	m.StatusPath = types.StringValue("/f5-utils-file-transfer:file/transfer-operations")
+	m.StatusCondition = types.StringValue("$..transfer-operation[?(@.status == 'Never')]")
+	m.Timeout = types.Int64Value(1)
+	resp := testResourceCreate(t, &RpcResource{client: client}, m, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "Last status") {
+		t.Fatalf("expected a timeout with the last status, got %v", resp.Diagnostics)
+	}
+}
+
+func TestUnitRpcResourceInvokeError(t *testing.T) {
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	client.Retry = &f5os.RetryPolicy{MaxAttempts: 1}
+	server.FailNext("POST", "f5-utils-file-transfer:file/import", http.StatusBadRequest, "remote-host is required")
+	resp := testResourceCreate(t, &RpcResource{client: client}, rpcTestModel("/f5-utils-file-transfer:file/import", `{}`), nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "remote-host is required") {
+		t.Fatalf("expected the device error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestUnitRpcResourceTriggersReplace(t *testing.T) {
+	ctx := context.Background()
+	state := types.MapValueMust(types.StringType, map[string]attr.Value{"v": types.StringValue("1")})
+	for _, tc := range []struct {
+		plan    types.Map
+		replace bool
+	}{
+		{types.MapValueMust(types.StringType, map[string]attr.Value{"v": types.StringValue("1")}), false},
+		{types.MapValueMust(types.StringType, map[string]attr.Value{"v": types.StringValue("2")}), true},
+		{types.MapNull(types.StringType), true},
+	} {
+		req := planmodifier.MapRequest{
+			State:      tfsdk.State{Raw: tftypes.NewValue(tftypes.String, "x")},
+			Plan:       tfsdk.Plan{Raw: tftypes.NewValue(tftypes.String, "x")},
+			StateValue: state,
+			PlanValue:  tc.plan,
+		}
+		resp := &planmodifier.MapResponse{PlanValue: tc.plan}
+		replaceOnMapChange{}.PlanModifyMap(ctx, req, resp)
+		if resp.RequiresReplace != tc.replace {
+			t.Errorf("plan %v: RequiresReplace = %v, want %v", tc.plan, resp.RequiresReplace, tc.replace)
+		}
+	}
+}
+
+func TestUnitRpcResource(t *testing.T) {
+	testAccPreUnitCheck(t)
+	setupVlanCommonMock(t)
+	mux.HandleFunc("/restconf/data/openconfig-system:system/f5-system-licensing:licensing/f5-system-licensing-install:get-eula", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"f5-system-licensing-install:output":{"eula-text":"END USER LICENSE AGREEMENT"}}`)
+	})
+	defer teardown()
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRpcResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("f5os_rpc.eula", "poll_interval"),
+					resource.TestMatchResourceAttr("f5os_rpc.eula", "output", regexp.MustCompile(`LICENSE AGREEMENT`)),
+				),
+			},
+		},
+	})
+}
+
+const testAccRpcResourceConfig = `
+resource "f5os_rpc" "eula" {
+  path  = "/openconfig-system:system/f5-system-licensing:licensing/f5-system-licensing-install:get-eula"
+  input = jsonencode({ "f5-system-licensing-install:registration-key" = "AAAAA-BBBBB-CCCCC-DDDDD-EEEEEEE" })
+  triggers = {
+    key = "AAAAA-BBBBB-CCCCC-DDDDD-EEEEEEE"
+  }
+}
+`
@@ -90,6 +90,8 @@ type snmpClient interface {
 	DeleteSnmpUser(name string) error
 	GetSnmpConfig() ([]byte, error)
 	GetSnmpMib() ([]byte, error)
+	// WithContext returns a client whose calls are bound to ctx.
+	WithContext(ctx context.Context) snmpClient
 }
 
 // f5osSnmpClient adapts the concrete SDK client to the snmpClient interface.
@@ -115,6 +117,9 @@ func (a *f5osSnmpClient) DeleteSnmpCommunity(name string) error { return a.c.Del
 func (a *f5osSnmpClient) DeleteSnmpUser(name string) error      { return a.c.DeleteSnmpUser(name) }
 func (a *f5osSnmpClient) GetSnmpConfig() ([]byte, error)        { return a.c.GetSnmpConfig() }
 func (a *f5osSnmpClient) GetSnmpMib() ([]byte, error)           { return a.c.GetSnmpMib() }
+func (a *f5osSnmpClient) WithContext(ctx context.Context) snmpClient {
+	return &f5osSnmpClient{c: a.c.WithContext(ctx)}
+}
 
 // NewSnmpResource creates a new instance of the resource
 func NewSnmpResource() resource.Resource {
@@ -415,7 +420,7 @@ func (r *SnmpResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// ------------------------------------------------------------------
 	// 1. Read SNMP communities, targets, and users from the device.
 	// ------------------------------------------------------------------
-	snmpData, err := r.client.GetSnmpConfig()
+	snmpData, err := r.client.WithContext(ctx).GetSnmpConfig()
 	if err != nil {
 		resp.Diagnostics.AddError("SNMP Read Error", fmt.Sprintf("Failed to read SNMP config from device: %s", err))
 		return
@@ -634,7 +639,7 @@ func (r *SnmpResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// ------------------------------------------------------------------
 	mibWasManaged := !state.SnmpMib.IsNull()
 
-	mibData, err := r.client.GetSnmpMib()
+	mibData, err := r.client.WithContext(ctx).GetSnmpMib()
 	if err != nil {
 		// MIB read failure is non-fatal; leave snmp_mib unchanged.
 		tflog.Warn(ctx, "Failed to read SNMP MIB from device", map[string]interface{}{"error": err.Error()})
@@ -803,7 +808,7 @@ func (r *SnmpResource) createSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal community payload: %w", err)
 		}
 
-		err = r.client.CreateSnmpCommunities(communityBytes)
+		err = r.client.WithContext(ctx).CreateSnmpCommunities(communityBytes)
 		if err != nil {
 			return fmt.Errorf("failed to create SNMP communities: %w", err)
 		}
@@ -817,7 +822,7 @@ func (r *SnmpResource) createSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal user payload: %w", err)
 		}
 
-		err = r.client.CreateSnmpUsers(userBytes)
+		err = r.client.WithContext(ctx).CreateSnmpUsers(userBytes)
 		if err != nil {
 			return fmt.Errorf("failed to create SNMP users: %w", err)
 		}
@@ -831,7 +836,7 @@ func (r *SnmpResource) createSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal target payload: %w", err)
 		}
 
-		err = r.client.CreateSnmpTargets(targetBytes)
+		err = r.client.WithContext(ctx).CreateSnmpTargets(targetBytes)
 		if err != nil {
 			return fmt.Errorf("failed to create SNMP targets: %w", err)
 		}
@@ -845,7 +850,7 @@ func (r *SnmpResource) createSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal MIB payload: %w", err)
 		}
 
-		err = r.client.UpdateSnmpMib(mibBytes)
+		err = r.client.WithContext(ctx).UpdateSnmpMib(mibBytes)
 		if err != nil {
 			return fmt.Errorf("failed to configure SNMP MIB: %w", err)
 		}
@@ -868,7 +873,7 @@ func (r *SnmpResource) updateSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal community payload: %w", err)
 		}
 
-		err = r.client.UpdateSnmpCommunities(communityBytes)
+		err = r.client.WithContext(ctx).UpdateSnmpCommunities(communityBytes)
 		if err != nil {
 			return fmt.Errorf("failed to update SNMP communities: %w", err)
 		}
@@ -886,7 +891,7 @@ func (r *SnmpResource) updateSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal user payload: %w", err)
 		}
 
-		err = r.client.UpdateSnmpUsers(userBytes)
+		err = r.client.WithContext(ctx).UpdateSnmpUsers(userBytes)
 		if err != nil {
 			return fmt.Errorf("failed to update SNMP users: %w", err)
 		}
@@ -904,7 +909,7 @@ func (r *SnmpResource) updateSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal target payload: %w", err)
 		}
 
-		err = r.client.UpdateSnmpTargets(targetBytes)
+		err = r.client.WithContext(ctx).UpdateSnmpTargets(targetBytes)
 		if err != nil {
 			return fmt.Errorf("failed to update SNMP targets: %w", err)
 		}
@@ -918,7 +923,7 @@ func (r *SnmpResource) updateSnmpConfig(ctx context.Context, communities []SnmpC
 			return fmt.Errorf("failed to marshal MIB payload: %w", err)
 		}
 
-		err = r.client.UpdateSnmpMib(mibBytes)
+		err = r.client.WithContext(ctx).UpdateSnmpMib(mibBytes)
 		if err != nil {
 			return fmt.Errorf("failed to update SNMP MIB: %w", err)
 		}
@@ -934,7 +939,7 @@ func (r *SnmpResource) updateSnmpConfig(ctx context.Context, communities []SnmpC
 func (r *SnmpResource) deleteSnmpConfig(ctx context.Context, communities []SnmpCommunityModel, targets []SnmpTargetModel, users []SnmpUserModel, resetMib bool) error {
 	// Delete targets first (they may depend on communities/users)
 	for _, target := range targets {
-		err := r.client.DeleteSnmpTarget(target.Name.ValueString())
+		err := r.client.WithContext(ctx).DeleteSnmpTarget(target.Name.ValueString())
 		if err != nil {
 			tflog.Warn(ctx, "Failed to delete SNMP target", map[string]interface{}{
 				"target": target.Name.ValueString(),
@@ -945,7 +950,7 @@ func (r *SnmpResource) deleteSnmpConfig(ctx context.Context, communities []SnmpC
 
 	// Delete communities
 	for _, community := range communities {
-		err := r.client.DeleteSnmpCommunity(community.Name.ValueString())
+		err := r.client.WithContext(ctx).DeleteSnmpCommunity(community.Name.ValueString())
 		if err != nil {
 			tflog.Warn(ctx, "Failed to delete SNMP community", map[string]interface{}{
 				"community": community.Name.ValueString(),
@@ -956,7 +961,7 @@ func (r *SnmpResource) deleteSnmpConfig(ctx context.Context, communities []SnmpC
 
 	// Delete users
 	for _, user := range users {
-		err := r.client.DeleteSnmpUser(user.Name.ValueString())
+		err := r.client.WithContext(ctx).DeleteSnmpUser(user.Name.ValueString())
 		if err != nil {
 			tflog.Warn(ctx, "Failed to delete SNMP user", map[string]interface{}{
 				"user":  user.Name.ValueString(),
@@ -978,7 +983,7 @@ func (r *SnmpResource) deleteSnmpConfig(ctx context.Context, communities []SnmpC
 		mibBytes, err := json.Marshal(emptyMib)
 		if err != nil {
 			tflog.Warn(ctx, "Failed to marshal empty MIB payload", map[string]interface{}{"error": err.Error()})
-		} else if err := r.client.UpdateSnmpMib(mibBytes); err != nil {
+		} else if err := r.client.WithContext(ctx).UpdateSnmpMib(mibBytes); err != nil {
 			tflog.Warn(ctx, "Failed to reset SNMP MIB on delete", map[string]interface{}{"error": err.Error()})
 		}
 	}
@@ -1458,6 +1458,7 @@ func (m *mockSnmp) GetSnmpMib() ([]byte, error) {
 	}
 	return []byte(`{"SNMPv2-MIB:system":{"sysName":"","sysContact":"","sysLocation":""}}`), nil
 }
+func (m *mockSnmp) WithContext(context.Context) snmpClient { return m }
 
 // --- Tests ---
 
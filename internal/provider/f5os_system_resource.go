@@ -159,7 +159,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("failure while creating System payload, got error: %s", err))
 		return
 	}
-	res, err := r.client.PatchRequest("/openconfig-system:system", byteBody)
+	res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system", byteBody)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating System, got error: %s", err))
 		return
@@ -176,7 +176,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 			return
 		}
 
-		res, err := r.client.PatchRequest("/openconfig-system:system/aaa", byteBody)
+		res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system/aaa", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Patching Token Lifetime, got error: %s", err))
 			return
@@ -195,7 +195,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Request Body  %+v", string(byteBody)))
 
-		res, err := r.client.PatchRequest("/openconfig-system:system/f5-system-settings:settings", byteBody)
+		res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system/f5-system-settings:settings", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Patching System Settings, got error: %s", err))
 			return
@@ -218,7 +218,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI, byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI, byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Http Cipher, got error: %s", err))
 			return
@@ -240,7 +240,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/ciphers", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/ciphers", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Sshd Cipher, got error: %s", err))
 			return
@@ -260,7 +260,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/kexalgorithms", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/kexalgorithms", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Sshd Key Algo, got error: %s", err))
 			return
@@ -280,7 +280,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/macs", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/macs", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Mac Algo, got error: %s", err))
 			return
@@ -300,7 +300,7 @@ func (r *SystemResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[CREATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/host-key-algorithms", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/host-key-algorithms", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Ssh Host Key Algo, got error: %s", err))
 			return
@@ -337,7 +337,7 @@ func (r *SystemResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	tflog.Debug(ctx, fmt.Sprint("[READ]", "Read System Config"))
-	system, err := r.getRequestWithRetry("/openconfig-system:system/config")
+	system, err := r.getRequestWithRetry(ctx, "/openconfig-system:system/config")
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while fetching System Config, got error: %s", err))
 		return
@@ -352,7 +352,7 @@ func (r *SystemResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	// Clock Settings
 	tflog.Debug(ctx, fmt.Sprint("[READ]", "Clock Settings"))
-	clock, err := r.getRequestWithRetry("/openconfig-system:system/clock")
+	clock, err := r.getRequestWithRetry(ctx, "/openconfig-system:system/clock")
 
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while fetching Clock Config, got error: %s", err))
@@ -367,7 +367,7 @@ func (r *SystemResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 	// security ciphers settings
-	ciphers, err := r.getRequestWithRetry("/openconfig-system:system/f5-security-ciphers:security/services/service")
+	ciphers, err := r.getRequestWithRetry(ctx, "/openconfig-system:system/f5-security-ciphers:security/services/service")
 
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while fetching Ciphers Config, got error: %s", err))
@@ -405,7 +405,7 @@ func (r *SystemResource) Read(ctx context.Context, req resource.ReadRequest, res
 		}
 	}
 	// system settings
-	settings, err := r.getRequestWithRetry("/openconfig-system:system/f5-system-settings:settings")
+	settings, err := r.getRequestWithRetry(ctx, "/openconfig-system:system/f5-system-settings:settings")
 
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while fetching Settings Config, got error: %s", err))
@@ -420,7 +420,7 @@ func (r *SystemResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	// token lifetime
-	lifetime, err := r.getRequestWithRetry("/openconfig-system:system/aaa/f5-aaa-confd-restconf-token:restconf-token/state/lifetime")
+	lifetime, err := r.getRequestWithRetry(ctx, "/openconfig-system:system/aaa/f5-aaa-confd-restconf-token:restconf-token/state/lifetime")
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while fetching Token Lifetime, got error: %s", err))
 		return
@@ -464,7 +464,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 			return
 		}
 
-		res, err := r.client.PatchRequest("/openconfig-system:system/aaa", byteBody)
+		res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system/aaa", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Patching Token Lifetime, got error: %s", err))
 			return
@@ -483,7 +483,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Request Body  %+v", string(byteBody)))
 
-		res, err := r.client.PatchRequest("/openconfig-system:system/f5-system-settings:settings", byteBody)
+		res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system/f5-system-settings:settings", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Patching System Settings, got error: %s", err))
 			return
@@ -506,7 +506,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI, byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI, byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Http Cipher, got error: %s", err))
 			return
@@ -528,7 +528,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/ciphers", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/ciphers", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while Sshd Cipher, got error: %s", err))
 			return
@@ -548,7 +548,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/kexalgorithms", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/kexalgorithms", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Sshd Key Algo, got error: %s", err))
 			return
@@ -568,7 +568,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/macs", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/macs", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Mac Algo, got error: %s", err))
 			return
@@ -588,7 +588,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 
 		tflog.Debug(ctx, fmt.Sprintf("[UPDATE], Full Uri:%+v", fullURI))
-		res, err := r.client.PutRequest(fullURI+"/host-key-algorithms", byteBody)
+		res, err := r.client.WithContext(ctx).PutRequest(fullURI+"/host-key-algorithms", byteBody)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating Ssh Host Key Algo, got error: %s", err))
 			return
@@ -606,7 +606,7 @@ func (r *SystemResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	res, err := r.client.PatchRequest("/openconfig-system:system", byteBody)
+	res, err := r.client.WithContext(ctx).PatchRequest("/openconfig-system:system", byteBody)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("failure while creating System, got error: %s", err))
 		return
@@ -662,7 +662,7 @@ func (r *SystemResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	baseURL := "/openconfig-system:system/config"
 	paths := []string{"login-banner", "hostname", "motd-banner"}
 	for _, path := range paths {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, path))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, path))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting System Config, got error: %s", err))
 			return
@@ -671,7 +671,7 @@ func (r *SystemResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	if !data.TokenLifetime.IsNull() {
 		baseURL = "/openconfig-system:system/aaa/f5-aaa-confd-restconf-token:restconf-token/config/lifetime/lifetime"
-		err := r.client.DeleteRequest(baseURL)
+		err := r.client.WithContext(ctx).DeleteRequest(baseURL)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting Token Lifetime, got error: %s", err))
 			return
@@ -680,14 +680,14 @@ func (r *SystemResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	baseURL = "/openconfig-system:system/f5-system-settings:settings"
 	if !data.CliTimeout.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, "idle-timeout"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, "idle-timeout"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting System Settings, got error: %s", err))
 			return
 		}
 	}
 	if !data.SshdIdleTimeout.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, "sshd-idle-timeout"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s/%s`, baseURL, "sshd-idle-timeout"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting System Settings, got error: %s", err))
 			return
@@ -696,35 +696,35 @@ func (r *SystemResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	baseURL = "/openconfig-system:system/f5-security-ciphers:security/services/service"
 	if !data.HttpdCipherSuite.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s="httpd"/config/%s`, baseURL, "ssl-cipher-suite"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s="httpd"/config/%s`, baseURL, "ssl-cipher-suite"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting System Settings, got error: %s", err))
 			return
 		}
 	}
 	if !data.SshdCiphers.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "ciphers"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "ciphers"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting Ciphers Config, got error: %s", err))
 			return
 		}
 	}
 	if !data.SshdKeyAlg.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "kexalgorithms"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "kexalgorithms"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting Ciphers Config, got error: %s", err))
 			return
 		}
 	}
 	if !data.SshdMacAlg.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "macs"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "macs"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting Ciphers Config, got error: %s", err))
 			return
 		}
 	}
 	if !data.SshdHkeyAlg.IsNull() {
-		err := r.client.DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "host-key-algorithms"))
+		err := r.client.WithContext(ctx).DeleteRequest(fmt.Sprintf(`%s="sshd"/config/%s`, baseURL, "host-key-algorithms"))
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Error:", fmt.Sprintf("Failure while Deleting Ciphers Config, got error: %s", err))
 			return
@@ -757,20 +757,21 @@ func (r *SystemResource) ImportState(ctx context.Context, req resource.ImportSta
 // method should be called after such changes to ensure the subsequent
 // Read succeeds.
 func (r *SystemResource) waitForDeviceReady(ctx context.Context, timeout time.Duration) error {
+	client := r.client.WithContext(ctx)
 	check := func() bool {
-		_, err := r.client.GetRequest("/openconfig-system:system/config")
+		_, err := client.GetRequest("/openconfig-system:system/config")
 		if err != nil {
 			return false
 		}
-		_, err = r.client.GetRequest("/openconfig-system:system/f5-security-ciphers:security/services/service")
+		_, err = client.GetRequest("/openconfig-system:system/f5-security-ciphers:security/services/service")
 		if err != nil {
 			return false
 		}
-		_, err = r.client.GetRequest("/openconfig-system:system/aaa")
+		_, err = client.GetRequest("/openconfig-system:system/aaa")
 		return err == nil
 	}
 
-	if err := pollUntilStable(check, timeout); err != nil {
+	if err := pollUntilStable(ctx, check, timeout); err != nil {
 		return err
 	}
 	tflog.Info(ctx, "[waitForDeviceReady] Device stabilized after SSHD changes")
@@ -797,7 +798,7 @@ func (r *SystemResource) waitForDeviceReady(ctx context.Context, timeout time.Du
 // container response that the caller should handle directly.
 //
 // Honors F5OS_POLL_INTERVAL (unit-test mode) to keep tests fast.
-func (r *SystemResource) getRequestWithRetry(path string) ([]byte, error) {
+func (r *SystemResource) getRequestWithRetry(ctx context.Context, path string) ([]byte, error) {
 	backoff := 5 * time.Second
 	if v := os.Getenv("F5OS_POLL_INTERVAL"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
@@ -806,7 +807,7 @@ func (r *SystemResource) getRequestWithRetry(path string) ([]byte, error) {
 	}
 	var lastErr error
 	for attempt := 0; attempt < 3; attempt++ {
-		data, err := r.client.GetRequest(path)
+		data, err := r.client.WithContext(ctx).GetRequest(path)
 		if err == nil {
 			// Success (including a legitimate empty-body 204).
 			return data, nil
@@ -819,7 +820,11 @@ func (r *SystemResource) getRequestWithRetry(path string) ([]byte, error) {
 		}
 		lastErr = err
 		if attempt < 2 {
-			time.Sleep(backoff)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
 		}
 	}
 	if lastErr == nil {
@@ -836,12 +841,14 @@ func (r *SystemResource) getRequestWithRetry(path string) ([]byte, error) {
 // It calls check repeatedly until two consecutive successes are separated
 // by a cooldown period, confirming the device has truly stabilized.
 // The cooldown is capped to the remaining deadline to prevent overshoot.
+// It returns early if ctx is cancelled.
 //
 // When F5OS_POLL_INTERVAL is set (unit test mode), the cooldown and interval
 // are reduced to 1ms so that unit tests are not blocked by the 30-second
 // stabilization wait that real devices need.
-func pollUntilStable(check func() bool, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
+func pollUntilStable(ctx context.Context, check func() bool, timeout time.Duration) error {
+	start := time.Now()
+	deadline := start.Add(timeout)
 	interval := devicePollInterval
 	cooldown := deviceCooldown
 
@@ -864,12 +871,16 @@ func pollUntilStable(check func() bool, timeout time.Duration) error {
 			if cooldown <= 0 {
 				break
 			}
-			time.Sleep(cooldown)
+			if err := pauseContext(ctx, cooldown, "device stabilization", start, timeout); err != nil {
+				return err
+			}
 			if check() {
 				return nil
 			}
 		}
-		time.Sleep(interval)
+		if err := pauseContext(ctx, interval, "device stabilization", start, timeout); err != nil {
+			return err
+		}
 	}
 	return fmt.Errorf("device did not stabilize within %v", timeout)
 }
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -226,7 +227,7 @@ func waitForDeviceAvailable(timeout time.Duration) error {
 		return true
 	}
 
-	return pollUntilStable(check, timeout)
+	return pollUntilStable(context.Background(), check, timeout)
 }
 
 // testAccPreCheckWithRetry wraps testAccPreCheck with retry logic to handle
@@ -30,8 +30,8 @@ func TestUnitTlsCertKeyReadClearsStateCertificatePre200(t *testing.T) {
 	testAccPreUnitCheck(t)
 	defer teardown()
 
-	// Mock a pre-2.0 rSeries device so client.PlatformVersion falls
-	// into the pre-2.0 branch of Read.
+	// Mock a pre-2.0 rSeries device so Read takes its pre-2.0
+	// branch.
 	setupMockPlatformVersion(mux, "1.8.3-23453")
 	tlsCertKeyMockAuth(t)
 
@@ -48,8 +48,8 @@ func TestUnitTlsCertKeyReadClearsStateCertificatePre200(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to create test client against mock: %s", err)
 	}
-	if platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Fatalf("mock reported %q; expected pre-2.0", client.PlatformVersion)
+	if platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Fatalf("mock reported %q; expected pre-2.0", client.Version())
 	}
 
 	r := &PartitionCertKeyResource{client: client}
@@ -19,8 +19,10 @@ import (
 )
 
 var (
-	_ resource.Resource                = &PartitionCertKeyResource{}
-	_ resource.ResourceWithImportState = &PartitionCertKeyResource{}
+	_ resource.Resource                   = &PartitionCertKeyResource{}
+	_ resource.ResourceWithImportState    = &PartitionCertKeyResource{}
+	_ resource.ResourceWithValidateConfig = &PartitionCertKeyResource{}
+	_ resource.ResourceWithModifyPlan     = &PartitionCertKeyResource{}
 )
 
 func NewPartitionCertKeyResource() resource.Resource {
@@ -172,6 +174,21 @@ func (r *PartitionCertKeyResource) Configure(ctx context.Context, req resource.C
 	r.teemData = teemData
 }
 
+// ValidateConfig rejects attributes the device does not support, once
+// the provider is configured; see attributeRequirements.
+func (r *PartitionCertKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateAttributeSupport(ctx, r.client, "f5os_tls_cert_key", req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan rejects attributes the device does not support at plan
+// time; see attributeRequirements.
+func (r *PartitionCertKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_tls_cert_key", req.Config, &resp.Diagnostics)
+}
+
 // isImportMode returns true when the caller populated the F5OS 2.0.0+
 // certificate/key leaves in the plan, signaling that the resource
 // should PATCH an existing cert/key rather than call
@@ -207,21 +224,13 @@ func (r *PartitionCertKeyResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	if platformVersionAtLeast(r.client.PlatformVersion, "v1.8") {
-		if data.SubjectAlternativeName.IsNull() || data.SubjectAlternativeName.IsUnknown() {
-			resp.Diagnostics.AddError("subject_alternative_name is required for platform version v1.8 and above", "")
-			return
-		}
-	} else {
-		if !data.SubjectAlternativeName.IsNull() || data.SubjectAlternativeName.IsUnknown() {
-			resp.Diagnostics.AddError("subject_alternative_name is not supported for platform version below v1.8", "")
-			return
-		}
+	if r.checkSubjectAlternativeName(ctx, data, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
 	}
 
 	tlsConfig := getTLSConfig(data)
 
-	err := r.client.CreateTlsCertKey(tlsConfig)
+	err := r.client.WithContext(ctx).CreateTlsCertKey(tlsConfig)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create partition cert key", err.Error())
@@ -245,6 +254,20 @@ func (r *PartitionCertKeyResource) Create(ctx context.Context, req resource.Crea
 	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
 }
 
+// checkSubjectAlternativeName adds an error when the self-signed workflow
+// sets subject_alternative_name on a device that does not support it, or
+// leaves it unset on one that requires it.
+func (r *PartitionCertKeyResource) checkSubjectAlternativeName(ctx context.Context, data *PartitionCertKeyResourceModel, diags *diag.Diagnostics) {
+	if !data.SubjectAlternativeName.IsNull() {
+		checkAttributeSupport(ctx, r.client, "f5os_tls_cert_key", diags, "subject_alternative_name")
+		return
+	}
+	if attributeSupported(ctx, r.client, "f5os_tls_cert_key", "subject_alternative_name") {
+		diags.AddAttributeError(path.Root("subject_alternative_name"), "Missing subject_alternative_name",
+			"subject_alternative_name is required for platform version v1.8 and above")
+	}
+}
+
 func (r *PartitionCertKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data *PartitionCertKeyResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -265,8 +288,8 @@ func (r *PartitionCertKeyResource) Read(ctx context.Context, req resource.ReadRe
 	// the device value (state), producing a perpetual diff. Users who
 	// need the device view of the certificate should read
 	// state_certificate instead.
-	if platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		_, state, err := r.client.GetTlsCertKey()
+	if attributeSupported(ctx, r.client, "f5os_tls_cert_key", "state_certificate") {
+		_, state, err := r.client.WithContext(ctx).GetTlsCertKey()
 		if err != nil {
 			resp.Diagnostics.AddWarning("Failed to refresh TLS cert/key from device",
 				"The resource will remain in state and Terraform will retry on the next apply. Original error: "+err.Error())
@@ -308,21 +331,13 @@ func (r *PartitionCertKeyResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	if platformVersionAtLeast(r.client.PlatformVersion, "v1.8") {
-		if data.SubjectAlternativeName.IsNull() || data.SubjectAlternativeName.IsUnknown() {
-			resp.Diagnostics.AddError("subject_alternative_name is required for platform version v1.8 and above", "")
-			return
-		}
-	} else {
-		if !data.SubjectAlternativeName.IsNull() || data.SubjectAlternativeName.IsUnknown() {
-			resp.Diagnostics.AddError("subject_alternative_name is not supported for platform version below v1.8", "")
-			return
-		}
+	if r.checkSubjectAlternativeName(ctx, data, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
 	}
 
 	tlsConfig := getTLSConfig(data)
 
-	err := r.client.CreateTlsCertKey(tlsConfig)
+	err := r.client.WithContext(ctx).CreateTlsCertKey(tlsConfig)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update partition cert key", err.Error())
@@ -351,7 +366,7 @@ func (r *PartitionCertKeyResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	err := r.client.DeleteTlsCertKey(data.Id.ValueString())
+	err := r.client.WithContext(ctx).DeleteTlsCertKey(data.Id.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete partition cert key", err.Error())
@@ -370,19 +385,14 @@ func (r *PartitionCertKeyResource) ImportState(ctx context.Context, req resource
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
 
-// applyImport handles the F5OS 2.0.0+ import workflow: version-gate,
+// applyImport handles the F5OS 2.0.0+ import workflow: support check,
 // PATCH config.certificate and/or config.key, wait for the RESTCONF
 // service to recover, and populate the resource's Computed leaves
 // (Id, StateCertificate).
 func (r *PartitionCertKeyResource) applyImport(ctx context.Context, data *PartitionCertKeyResourceModel, diags *diag.Diagnostics) {
-	if !platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		diags.AddError("Unsupported attribute",
-			"The certificate and key attributes (TLS import workflow) require "+
-				"F5OS 2.0.0 or later. Detected device version: "+r.client.PlatformVersion+". "+
-				"The self-signed workflow (subject_alternative_name / key_type / key_size / "+
-				"key_curve / days_valid / ...) remains available on older versions — remove the "+
-				"certificate and key attributes to fall back to it, or target a 2.0.0+ device to "+
-				"import an existing cert/key pair.")
+	// The self-signed workflow (subject_alternative_name / key_type /
+	// key_size / ...) remains available on devices without import support.
+	if checkAttributeSupport(ctx, r.client, "f5os_tls_cert_key", diags, "certificate", "key"); diags.HasError() {
 		return
 	}
 	cert := ""
@@ -393,7 +403,7 @@ func (r *PartitionCertKeyResource) applyImport(ctx context.Context, data *Partit
 	if !data.Key.IsNull() && !data.Key.IsUnknown() {
 		key = data.Key.ValueString()
 	}
-	if err := r.client.ImportTlsCertKey(cert, key); err != nil {
+	if err := r.client.WithContext(ctx).ImportTlsCertKey(cert, key); err != nil {
 		diags.AddError("Failed to import TLS cert/key", err.Error())
 		return
 	}
@@ -407,7 +417,7 @@ func (r *PartitionCertKeyResource) applyImport(ctx context.Context, data *Partit
 	data.Id = types.StringValue(data.Name.ValueString())
 	// Refresh state.certificate from the device so Terraform sees a
 	// concrete value; fall back to null if the read fails.
-	_, state, err := r.client.GetTlsCertKey()
+	_, state, err := r.client.WithContext(ctx).GetTlsCertKey()
 	if err != nil {
 		diags.AddWarning("Failed to refresh TLS cert/key state after import",
 			"Terraform will retry on the next apply. Original error: "+err.Error())
@@ -479,7 +489,7 @@ func waitForTLSService(ctx context.Context, client *f5ossdk.F5os) error {
 			return ctx.Err()
 		default:
 		}
-		resp, err := client.GetRequest("/openconfig-platform:components/component")
+		resp, err := client.WithContext(ctx).GetRequest("/openconfig-platform:components/component")
 		if err == nil && len(resp) > 0 {
 			return nil
 		}
@@ -30,11 +30,16 @@ func tlsCertKeyMockAuth(t *testing.T) {
 	})
 }
 
-// tlsCertKeyMockPlatform registers the platform component endpoint (pre-v1.8).
+// tlsCertKeyMockPlatform registers the platform component and image install
+// endpoints so the provider detects F5OS v1.7 (pre-v1.8).
 func tlsCertKeyMockPlatform() {
 	mux.HandleFunc("/restconf/data/openconfig-platform:components/component", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, _ = fmt.Fprintf(w, "%s", loadFixtureString("./fixtures/rseries_platform_state_ok.json"))
+		_, _ = fmt.Fprintf(w, "%s", loadFixtureString("./fixtures/platform_components_rseries.json"))
+	})
+	mux.HandleFunc("/restconf/data/openconfig-system:system/f5-system-image:image/state/install", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"f5-system-image:install": {"install-os-version": "1.7.0-1234","install-service-version": "1.7.0-1234","install-status": "success"}}`)
 	})
 }
 
@@ -241,7 +246,7 @@ func TestUnitCreateCertKeySANNotSupportedError(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config:      tlsCertKeySANCfg,
-				ExpectError: regexp.MustCompile("subject_alternative_name is not supported for platform version below v1.8"),
+				ExpectError: regexp.MustCompile("subject_alternative_name is not supported on F5OS versions below 1.8.0"),
 			},
 		},
 	})
@@ -391,7 +396,7 @@ func TestUnitUpdateCertKeySANNotSupportedError(t *testing.T) {
 			// Step 2: Update adds SAN which is not supported pre-v1.8
 			{
 				Config:      tlsCertKeySANCfg,
-				ExpectError: regexp.MustCompile("subject_alternative_name is not supported for platform version below v1.8"),
+				ExpectError: regexp.MustCompile("subject_alternative_name is not supported on F5OS versions below 1.8.0"),
 			},
 		},
 	})
@@ -1228,8 +1233,8 @@ func testAccPreCheckTlsImport(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to create F5OS client for pre-check: %s", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("target device is %s; TLS cert/key import requires F5OS 2.0.0+", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("target device is %s; TLS cert/key import requires F5OS 2.0.0+", client.Version())
 	}
 }
 
@@ -1331,8 +1336,8 @@ func TestAccTlsCertKeyImportRejectedPre200(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to create F5OS client for pre-check: %s", err)
 	}
-	if platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("target device is %s; this test only fires on pre-2.0.0 devices", client.PlatformVersion)
+	if platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("target device is %s; this test only fires on pre-2.0.0 devices", client.Version())
 	}
 
 	resource.Test(t, resource.TestCase{
@@ -204,7 +204,7 @@ func (r *UserPasswordChangeResource) changePasswordWithOldPassword(ctx context.C
 		ConfirmPassword: newPassword, // API requires confirmation password
 	}
 
-	_, err := r.client.PartitionPasswordChange(userName, passwordChangeConfig)
+	_, err := r.client.WithContext(ctx).PartitionPasswordChange(userName, passwordChangeConfig)
 	if err != nil {
 		errStr := err.Error()
 		tflog.Debug(ctx, "Password Change API Error", map[string]any{
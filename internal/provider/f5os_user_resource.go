@@ -3,9 +3,9 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -24,6 +24,15 @@ type UserResource struct {
 	client *f5os.F5os
 }
 
+// errUserNotFound is returned by getUser when the device has no such user.
+var errUserNotFound = errors.New("user not found")
+
+// userErrorAttributes maps the nodes of user and set-password requests to
+// the attributes they configure, for addClientErrorDiagnostic.
+var userErrorAttributes = restconfAttributes(map[string]string{
+	"set-password": "password",
+}, "password", "role", "secondary_role", "authorized_keys", "expiry_status")
+
 func NewUserResource() resource.Resource {
 	return &UserResource{}
 }
@@ -96,7 +105,7 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 	// Try to create the user with all fields including secondary role
 	createWarnings, err := r.createUserWithRetry(ctx, plan)
 	if err != nil {
-		resp.Diagnostics.AddError("User Create Error", err.Error())
+		addClientErrorDiagnostic(&resp.Diagnostics, userErrorAttributes, err, "User Create Error", err.Error())
 		return
 	}
 	for _, w := range createWarnings {
@@ -110,7 +119,7 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 		err = r.setUserPassword(ctx, username, password)
 		if err != nil {
 			// If password setting fails, clean up the created user
-			deleteErr := r.deleteUser(username)
+			deleteErr := r.deleteUser(context.WithoutCancel(ctx), username)
 			if deleteErr != nil {
 				tflog.Error(ctx, "Failed to clean up user after password error", map[string]interface{}{
 					"username":     username,
@@ -118,7 +127,7 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 				})
 			}
 
-			resp.Diagnostics.AddError(
+			addClientErrorDiagnostic(&resp.Diagnostics, userErrorAttributes, err,
 				"Error Setting User Password",
 				"Could not set password for user "+username+": "+err.Error(),
 			)
@@ -146,10 +155,10 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	user, err := r.getUser(state.Username.ValueString())
+	user, err := r.getUser(ctx, state.Username.ValueString())
 	if err != nil {
 		// If user not found, remove from state
-		if err.Error() == "user not found" {
+		if errors.Is(err, errUserNotFound) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -240,9 +249,9 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	username := plan.Username.ValueString()
 
 	// Check if user exists before updating
-	_, err := r.getUser(username)
+	_, err := r.getUser(ctx, username)
 	if err != nil {
-		if err.Error() == "user not found" {
+		if errors.Is(err, errUserNotFound) {
 			resp.Diagnostics.AddError("User Update Error",
 				fmt.Sprintf("User '%s' does not exist and cannot be updated", username))
 		} else {
@@ -255,7 +264,7 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	// Step 1: Update user attributes (without password)
 	updateWarnings, err := r.updateUserWithRetry(ctx, username, plan)
 	if err != nil {
-		resp.Diagnostics.AddError("User Update Error", err.Error())
+		addClientErrorDiagnostic(&resp.Diagnostics, userErrorAttributes, err, "User Update Error", err.Error())
 		return
 	}
 	for _, w := range updateWarnings {
@@ -278,7 +287,7 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 			// Use setUserPassword (admin set-password endpoint) since the provider runs as admin
 			err = r.setUserPassword(ctx, username, newPassword)
 			if err != nil {
-				resp.Diagnostics.AddError(
+				addClientErrorDiagnostic(&resp.Diagnostics, userErrorAttributes, err,
 					"Error Updating User Password",
 					"Could not update password for user "+username+": "+err.Error(),
 				)
@@ -311,9 +320,9 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	username := state.Username.ValueString()
 
 	// Check if user exists before attempting deletion
-	_, err := r.getUser(username)
+	_, err := r.getUser(ctx, username)
 	if err != nil {
-		if err.Error() == "user not found" {
+		if errors.Is(err, errUserNotFound) {
 			// User already doesn't exist, consider this successful
 			tflog.Info(ctx, "User already deleted or does not exist", map[string]any{
 				"username": username,
@@ -352,7 +361,7 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 
 	// Step 2: Delete the user
-	if err := r.deleteUser(username); err != nil {
+	if err := r.deleteUser(ctx, username); err != nil {
 		resp.Diagnostics.AddError("User Delete Error", err.Error())
 		return
 	}
@@ -448,20 +457,17 @@ func (r *UserResource) createUserPayload(plan UserModel, includePassword bool, i
 }
 
 // getUser retrieves user information via API and determines secondary roles from roles endpoint
-func (r *UserResource) getUser(username string) (*UserStruct, error) {
+func (r *UserResource) getUser(ctx context.Context, username string) (*UserStruct, error) {
 	uri := fmt.Sprintf("/openconfig-system:system/aaa/authentication/f5-system-aaa:users/user=%s", username)
 
-	respData, err := r.client.GetRequest(uri)
+	respData, err := r.client.WithContext(ctx).GetRequest(uri)
 	if err != nil {
-		// Check if this is a 404 error (user not found)
-		errStr := fmt.Sprintf("%s", err)
-		if errStr == "404" ||
-			strings.Contains(errStr, "404") ||
-			strings.Contains(errStr, "not found") {
-			return nil, fmt.Errorf("user not found")
-		}
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
+	// The client returns the error document of a 404 without an error.
+	if restconfMissing(restconfBodyErrors(respData)) {
+		return nil, errUserNotFound
+	}
 
 	var response userResponseWrapper
 	if err := json.Unmarshal(respData, &response); err != nil {
@@ -475,7 +481,7 @@ func (r *UserResource) getUser(username string) (*UserStruct, error) {
 	})
 
 	if len(response.Users) == 0 {
-		return nil, fmt.Errorf("user not found")
+		return nil, errUserNotFound
 	}
 
 	user := &response.Users[0]
@@ -530,20 +536,11 @@ func (r *UserResource) getUser(username string) (*UserStruct, error) {
 }
 
 // updateUser updates an existing user via API
-func (r *UserResource) updateUser(username string, payload []byte) error {
+func (r *UserResource) updateUser(ctx context.Context, username string, payload []byte) error {
 	uri := fmt.Sprintf("/openconfig-system:system/aaa/authentication/f5-system-aaa:users/user=%s", username)
 
-	respData, err := r.client.PatchRequest(uri, payload)
+	respData, err := r.client.WithContext(ctx).PatchRequest(uri, payload)
 	if err != nil {
-		// Check if this is a password policy violation
-		errStr := fmt.Sprintf("%s", err)
-		if strings.Contains(errStr, "BAD PASSWORD") ||
-			strings.Contains(errStr, "password policy") ||
-			strings.Contains(errStr, "dictionary check") ||
-			strings.Contains(errStr, "simplistic") ||
-			strings.Contains(errStr, "systematic") {
-			return fmt.Errorf("password does not meet F5OS device policy requirements: %w", err)
-		}
 		return fmt.Errorf("API request failed: %w", err)
 	}
 
@@ -555,10 +552,10 @@ func (r *UserResource) updateUser(username string, payload []byte) error {
 }
 
 // deleteUser deletes a user via API
-func (r *UserResource) deleteUser(username string) error {
+func (r *UserResource) deleteUser(ctx context.Context, username string) error {
 	uri := fmt.Sprintf("/openconfig-system:system/aaa/authentication/f5-system-aaa:users/user=%s", username)
 
-	err := r.client.DeleteRequest(uri)
+	err := r.client.WithContext(ctx).DeleteRequest(uri)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -586,22 +583,13 @@ func (r *UserResource) setUserPassword(ctx context.Context, username, password s
 		"payload":  string(payload),
 	})
 
-	respData, err := r.client.PostRequest(uri, payload)
+	respData, err := r.client.WithContext(ctx).PostRequest(uri, payload)
 	if err != nil {
-		errStr := err.Error()
 		tflog.Debug(ctx, "Set Password API Error", map[string]any{
-			"error": errStr,
+			"error": err.Error(),
 		})
-
-		// Check for F5OS password policy violations
-		if strings.Contains(errStr, "password") &&
-			(strings.Contains(errStr, "length") ||
-				strings.Contains(errStr, "character") ||
-				strings.Contains(errStr, "dictionary check") ||
-				strings.Contains(errStr, "simplistic") ||
-				strings.Contains(errStr, "systematic")) {
-			return fmt.Errorf("password does not meet F5OS device policy requirements: %w", err)
-		}
+		// A password the device's policy rejects is reported against the
+		// password attribute by addClientErrorDiagnostic.
 		return fmt.Errorf("API request failed: %w", err)
 	}
 
@@ -614,7 +602,7 @@ func (r *UserResource) setUserPassword(ctx context.Context, username, password s
 }
 
 // createUser creates a new user via API using POST to the users collection
-func (r *UserResource) createUser(payload []byte) error {
+func (r *UserResource) createUser(ctx context.Context, payload []byte) error {
 	uri := "/openconfig-system:system/aaa/authentication/f5-system-aaa:users"
 
 	tflog.Debug(context.Background(), "Creating User via POST", map[string]any{
@@ -622,17 +610,8 @@ func (r *UserResource) createUser(payload []byte) error {
 		"payload": string(payload),
 	})
 
-	respData, err := r.client.PostRequest(uri, payload)
+	respData, err := r.client.WithContext(ctx).PostRequest(uri, payload)
 	if err != nil {
-		// Check if this is a password policy violation
-		errStr := fmt.Sprintf("%s", err)
-		if strings.Contains(errStr, "BAD PASSWORD") ||
-			strings.Contains(errStr, "password policy") ||
-			strings.Contains(errStr, "dictionary check") ||
-			strings.Contains(errStr, "simplistic") ||
-			strings.Contains(errStr, "systematic") {
-			return fmt.Errorf("password does not meet F5OS device policy requirements: %w", err)
-		}
 		return fmt.Errorf("API request failed: %w", err)
 	}
 
@@ -662,7 +641,7 @@ func (r *UserResource) createUserWithRetry(ctx context.Context, plan UserModel)
 		"payload":  string(payload),
 	})
 
-	err = r.createUser(payload)
+	err = r.createUser(ctx, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -719,7 +698,7 @@ func (r *UserResource) updateUserWithRetry(ctx context.Context, username string,
 		"role":     primaryRole,
 	})
 
-	err = r.updateUser(username, payload)
+	err = r.updateUser(ctx, username, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
@@ -818,20 +797,20 @@ func (r *UserResource) assignUserToRole(ctx context.Context, username, roleName
 	})
 
 	// Use PUT request to assign the user to the role
-	respData, err := r.client.PutRequest(uri, payload)
+	respData, err := r.client.WithContext(ctx).PutRequest(uri, payload)
 	if err != nil {
-		errStr := err.Error()
 		tflog.Debug(ctx, "Role Assignment API Error", map[string]any{
-			"error": errStr,
+			"error": err.Error(),
 		})
-
-		// Check if the role doesn't exist
-		if strings.Contains(errStr, "404") || strings.Contains(errStr, "not found") {
-			return fmt.Errorf("role '%s' does not exist on the F5OS device", roleName)
-		}
-
 		return fmt.Errorf("API request failed: %w", err)
 	}
+	// The client returns the error document of a 404, as for a role that
+	// does not exist, without an error.
+	if errs := restconfBodyErrors(respData); restconfMissing(errs) {
+		return fmt.Errorf("role '%s' does not exist on the F5OS device", roleName)
+	} else if len(errs) > 0 {
+		return restconfBodyError(respData)
+	}
 
 	tflog.Debug(ctx, "User Role Assignment Response", map[string]any{
 		"username": username,
@@ -846,7 +825,7 @@ func (r *UserResource) assignUserToRole(ctx context.Context, username, roleName
 func (r *UserResource) getUserRoles(ctx context.Context, username string) ([]string, error) {
 	uri := "/openconfig-system:system/aaa/authentication/f5-system-aaa:roles"
 
-	respData, err := r.client.GetRequest(uri)
+	respData, err := r.client.WithContext(ctx).GetRequest(uri)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get roles: %w", err)
 	}
@@ -1009,22 +988,13 @@ func (r *UserResource) removeUserFromRole(ctx context.Context, username, roleNam
 	})
 
 	// Use DELETE request to remove the user from the role
-	err := r.client.DeleteRequest(uri)
+	// A user that is not assigned to the role answers 404, which the
+	// client does not report as an error, so removal needs no check.
+	err := r.client.WithContext(ctx).DeleteRequest(uri)
 	if err != nil {
-		errStr := err.Error()
 		tflog.Debug(ctx, "Role Removal API Error", map[string]any{
-			"error": errStr,
+			"error": err.Error(),
 		})
-
-		// Check if the user/role assignment doesn't exist (404 is OK for removal)
-		if strings.Contains(errStr, "404") || strings.Contains(errStr, "not found") {
-			tflog.Debug(ctx, "User was not assigned to role, removal not needed", map[string]any{
-				"username": username,
-				"role":     roleName,
-			})
-			return nil
-		}
-
 		return fmt.Errorf("API request failed: %w", err)
 	}
 
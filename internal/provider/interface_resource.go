@@ -19,6 +19,12 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &InterfaceResource{}
 var _ resource.ResourceWithImportState = &InterfaceResource{}
+var _ resource.ResourceWithValidateConfig = &InterfaceResource{}
+var _ resource.ResourceWithModifyPlan = &InterfaceResource{}
+
+// interfaceErrorAttributes maps interface RESTCONF nodes to the attributes
+// that set them, so device errors are reported against the offending field.
+var interfaceErrorAttributes = restconfAttributes(nil, "name", "native_vlan", "trunk_vlans", "enabled", "description")
 
 func NewInterfaceResource() resource.Resource {
 	return &InterfaceResource{}
@@ -120,6 +126,21 @@ func (r *InterfaceResource) Configure(ctx context.Context, req resource.Configur
 	r.teemData = teemData
 }
 
+// ValidateConfig rejects attributes the device does not support, once
+// the provider is configured; see attributeRequirements.
+func (r *InterfaceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	validateAttributeSupport(ctx, r.client, "f5os_interface", req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan rejects attributes the device does not support at plan
+// time; see attributeRequirements.
+func (r *InterfaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_interface", req.Config, &resp.Diagnostics)
+}
+
 func (r *InterfaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *InterfaceResourceModel
 
@@ -129,11 +150,11 @@ func (r *InterfaceResource) Create(ctx context.Context, req resource.CreateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_vlan` resource is supported with Velos Partition level/rSeries appliance.")
 		return
 	}
-	if diags := r.validate200Fields(data); diags.HasError() {
+	if diags := r.validate200Fields(ctx, data); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
@@ -142,9 +163,9 @@ func (r *InterfaceResource) Create(ctx context.Context, req resource.CreateReque
 
 	tflog.Debug(ctx, fmt.Sprintf("interfaceReqConfig Data:%+v", interfaceReqConfig))
 
-	respByte, err := r.client.UpdateInterface(data.Name.ValueString(), interfaceReqConfig)
+	respByte, err := r.client.WithContext(ctx).UpdateInterface(data.Name.ValueString(), interfaceReqConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Updating Interface failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, interfaceErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Updating Interface failed, got error: %s", err))
 		return
 	}
 
@@ -153,11 +174,11 @@ func (r *InterfaceResource) Create(ctx context.Context, req resource.CreateReque
 	teemInfo := make(map[string]any)
 	teemInfo["teemData"] = r.teemData
 	r.client.Metadata = teemInfo
-	_ = r.client.SendTeem(teemInfo)
+	_ = r.client.WithContext(ctx).SendTeem(teemInfo)
 	// if err != nil {
 	// 	resp.Diagnostics.AddError("Teem Error", fmt.Sprintf("Sending Teem Data failed: %s", err))
 	// }
-	intfData, err := r.client.GetInterface(data.Name.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetInterface(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Interface, got error: %s", err))
 		return
@@ -181,7 +202,7 @@ func (r *InterfaceResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 	tflog.Info(ctx, fmt.Sprintf("[READ] Reading Interface :%+v", data.Id.ValueString()))
 
-	intfData, err := r.client.GetInterface(data.Id.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetInterface(data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Interface, got error: %s", err))
 		return
@@ -202,11 +223,11 @@ func (r *InterfaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_vlan` resource is supported with Velos Partition level.")
 		return
 	}
-	if diags := r.validate200Fields(data); diags.HasError() {
+	if diags := r.validate200Fields(ctx, data); diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
@@ -214,14 +235,14 @@ func (r *InterfaceResource) Update(ctx context.Context, req resource.UpdateReque
 	interfaceReqConfig := getInterfaceConfig(ctx, data)
 	tflog.Info(ctx, fmt.Sprintf("interfaceReqConfig Data:%+v", interfaceReqConfig))
 
-	respByte, err := r.client.UpdateInterface(data.Name.ValueString(), interfaceReqConfig)
+	respByte, err := r.client.WithContext(ctx).UpdateInterface(data.Name.ValueString(), interfaceReqConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Update Vlan failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, interfaceErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Update Vlan failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("interfaceReqConfig Response:%+v", string(respByte)))
 	data.Id = types.StringValue(data.Name.ValueString())
-	intfData, err := r.client.GetInterface(data.Name.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetInterface(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Interface, got error: %s", err))
 		return
@@ -243,7 +264,7 @@ func (r *InterfaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	err := r.client.RemoveNativeVlans(data.Name.ValueString())
+	err := r.client.WithContext(ctx).RemoveNativeVlans(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Removing Native vlan failed, got error: %s", err))
 		return
@@ -251,7 +272,7 @@ func (r *InterfaceResource) Delete(ctx context.Context, req resource.DeleteReque
 	var trunkIds []int
 	data.TrunkVlans.ElementsAs(ctx, &trunkIds, false)
 	for _, trunkId := range trunkIds {
-		err := r.client.RemoveTrunkVlans(data.Name.ValueString(), trunkId)
+		err := r.client.WithContext(ctx).RemoveTrunkVlans(data.Name.ValueString(), trunkId)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Removing Trunk vlan ID failed, got error: %s", err))
 			return
@@ -307,20 +328,15 @@ func (r *InterfaceResource) interfaceResourceModelToState(ctx context.Context, r
 
 // validate200Fields returns an error diagnostic when the caller
 // populated any F5OS 2.0.0+ additive interface attributes on a device
-// running an older version. It is called from Create and Update before
-// any payload is built so no partial write reaches the device.
-func (r *InterfaceResource) validate200Fields(data *InterfaceResourceModel) diag.Diagnostics {
+// that does not support them; see attributeRequirements. It is called
+// from Create and Update before any payload is built so no partial write
+// reaches the device.
+func (r *InterfaceResource) validate200Fields(ctx context.Context, data *InterfaceResourceModel) diag.Diagnostics {
 	var diags diag.Diagnostics
 	if data.Description.IsNull() || data.Description.IsUnknown() {
 		return diags
 	}
-	if platformVersionAtLeast(r.client.PlatformVersion, "v2.0") {
-		return diags
-	}
-	diags.AddError("Unsupported attribute",
-		fmt.Sprintf("The interface `description` attribute requires F5OS 2.0.0 or later. "+
-			"Detected device version: %q. Remove `description` from the resource or "+
-			"target a 2.0.0+ device.", r.client.PlatformVersion))
+	checkAttributeSupport(ctx, r.client, "f5os_interface", &diags, "description")
 	return diags
 }
 
@@ -1570,8 +1570,8 @@ func testAccPreCheckInterface2_0(t *testing.T) {
 	if err != nil {
 		t.Fatalf("testAccPreCheckInterface2_0: failed to create session: %s", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.Version())
 	}
 }
 
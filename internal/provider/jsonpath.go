@@ -0,0 +1,374 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. It supports the subset
+// needed to pick values out of RESTCONF documents:
+//
+//	$                     the document
+//	.name, ['name']       an object member, matched by local name so the
+//	                      module prefix may be left out
+//	.*, [*]               every member or element
+//	[2], [-1]             an array element, counted from the end if negative
+//	..name, ..*           recursive descent
+//	[?(@.a.b == 'x')]     elements (or member values) for which the
+//	                      condition holds; ==, !=, <, <=, > and >= compare
+//	                      with a string, number or boolean literal, and a
+//	                      bare @.a.b tests that the member exists
+type jsonPath []jsonPathStep
+
+type jsonPathStep struct {
+	recursive bool
+	wildcard  bool
+	name      string
+	index     *int
+	filter    *jsonPathFilter
+}
+
+type jsonPathFilter struct {
+	path  jsonPath
+	op    string
+	value interface{}
+}
+
+// parseJSONPath compiles expr.
+func parseJSONPath(expr string) (jsonPath, error) {
+	p := &jsonPathParser{s: strings.TrimSpace(expr)}
+	if !p.consume("$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	steps, err := p.steps(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, p.s[p.pos:])
+	}
+	return steps, nil
+}
+
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) consume(tok string) bool {
+	if strings.HasPrefix(p.s[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// steps parses steps until the end of the expression or, in a filter, the
+// first character that cannot continue a path.
+func (p *jsonPathParser) steps(inFilter bool) (jsonPath, error) {
+	var steps jsonPath
+	for p.pos < len(p.s) {
+		var step jsonPathStep
+		switch {
+		case p.consume(".."):
+			step.recursive = true
+			if p.pos < len(p.s) && p.s[p.pos] == '[' {
+				p.pos++
+				if err := p.bracket(&step); err != nil {
+					return nil, err
+				}
+			} else if err := p.dotted(&step); err != nil {
+				return nil, err
+			}
+		case p.consume("."):
+			if err := p.dotted(&step); err != nil {
+				return nil, err
+			}
+		case p.consume("["):
+			if err := p.bracket(&step); err != nil {
+				return nil, err
+			}
+		default:
+			if inFilter {
+				return steps, nil
+			}
+			return nil, fmt.Errorf("unexpected %q", p.s[p.pos:])
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// dotted parses the member name or * after a dot.
+func (p *jsonPathParser) dotted(step *jsonPathStep) error {
+	if p.consume("*") {
+		step.wildcard = true
+		return nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(".[ =!<>)", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return fmt.Errorf("missing member name at offset %d", start)
+	}
+	step.name = p.s[start:p.pos]
+	return nil
+}
+
+// bracket parses the contents of [...] after the opening bracket.
+func (p *jsonPathParser) bracket(step *jsonPathStep) error {
+	p.skipSpace()
+	switch {
+	case p.consume("*"):
+		step.wildcard = true
+	case p.consume("?("):
+		f, err := p.filter()
+		if err != nil {
+			return err
+		}
+		step.filter = f
+	case p.pos < len(p.s) && (p.s[p.pos] == '\'' || p.s[p.pos] == '"'):
+		name, err := p.quoted()
+		if err != nil {
+			return err
+		}
+		step.name = name
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && (p.s[p.pos] == '-' || (p.s[p.pos] >= '0' && p.s[p.pos] <= '9')) {
+			p.pos++
+		}
+		i, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return fmt.Errorf("invalid subscript at offset %d", start)
+		}
+		step.index = &i
+	}
+	p.skipSpace()
+	if !p.consume("]") {
+		return fmt.Errorf("missing ] at offset %d", p.pos)
+	}
+	return nil
+}
+
+func (p *jsonPathParser) quoted() (string, error) {
+	q := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], q)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at offset %d", p.pos)
+	}
+	s := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return s, nil
+}
+
+// filter parses "@path [op literal])" after "?(".
+func (p *jsonPathParser) filter() (*jsonPathFilter, error) {
+	p.skipSpace()
+	if !p.consume("@") {
+		return nil, fmt.Errorf("filter must start with @ at offset %d", p.pos)
+	}
+	steps, err := p.steps(true)
+	if err != nil {
+		return nil, err
+	}
+	f := &jsonPathFilter{path: steps}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			f.op = op
+			break
+		}
+	}
+	if f.op != "" {
+		p.skipSpace()
+		if f.value, err = p.literal(); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+	}
+	if !p.consume(")") {
+		return nil, fmt.Errorf("missing ) at offset %d", p.pos)
+	}
+	return f, nil
+}
+
+func (p *jsonPathParser) literal() (interface{}, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '\'' || p.s[p.pos] == '"') {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" )", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	lit := p.s[start:p.pos]
+	switch lit {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(lit, 64); err != nil {
+		return nil, fmt.Errorf("invalid literal %q at offset %d", lit, start)
+	}
+	return lit, nil
+}
+
+// eval returns the values p selects in doc, in document order with the
+// members of an object taken by name.
+func (p jsonPath) eval(doc interface{}) []interface{} {
+	nodes := []interface{}{doc}
+	for _, step := range p {
+		var next []interface{}
+		for _, n := range nodes {
+			if step.recursive {
+				for _, d := range descendants(n) {
+					next = append(next, step.apply(d)...)
+				}
+				continue
+			}
+			next = append(next, step.apply(n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants returns n and every value below it.
+func descendants(n interface{}) []interface{} {
+	out := []interface{}{n}
+	for _, c := range children(n) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+// children returns the member values of an object, in member name order,
+// or the elements of an array.
+func children(n interface{}) []interface{} {
+	switch x := n.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]interface{}, len(keys))
+		for i, k := range keys {
+			out[i] = x[k]
+		}
+		return out
+	case []interface{}:
+		return x
+	}
+	return nil
+}
+
+func (s jsonPathStep) apply(n interface{}) []interface{} {
+	switch {
+	case s.wildcard:
+		return children(n)
+	case s.filter != nil:
+		var out []interface{}
+		for _, c := range children(n) {
+			if s.filter.holds(c) {
+				out = append(out, c)
+			}
+		}
+		return out
+	case s.index != nil:
+		a, ok := n.([]interface{})
+		if !ok {
+			return nil
+		}
+		i := *s.index
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil
+		}
+		return []interface{}{a[i]}
+	}
+	if m, ok := n.(map[string]interface{}); ok {
+		if v, ok := jsonMember(m, s.name); ok {
+			return []interface{}{v}
+		}
+	}
+	return nil
+}
+
+func (f *jsonPathFilter) holds(n interface{}) bool {
+	for _, v := range f.path.eval(n) {
+		if f.op == "" || compareJSON(v, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareJSON compares a scalar with a literal, numerically when both are
+// numbers and by text otherwise.
+func compareJSON(v interface{}, op string, lit interface{}) bool {
+	a, ok := jsonScalar(v)
+	b, ok2 := jsonScalar(lit)
+	if !ok || !ok2 {
+		return false
+	}
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	numeric := errA == nil && errB == nil
+	switch op {
+	case "==":
+		return a == b || (numeric && x == y)
+	case "!=":
+		return a != b && !(numeric && x == y)
+	}
+	if !numeric {
+		switch op {
+		case "<":
+			return a < b
+		case "<=":
+			return a <= b
+		case ">":
+			return a > b
+		}
+		return a >= b
+	}
+	switch op {
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	}
+	return x >= y
+}
+
+// jsonPathValue renders the values selected by a JSONPath as a string: a
+// single scalar as its text, anything else as compact JSON (an array when
+// there are several values).
+func jsonPathValue(values []interface{}) string {
+	if len(values) == 1 {
+		if s, ok := jsonScalar(values[0]); ok {
+			return s
+		}
+		return compactJSON(values[0])
+	}
+	if values == nil {
+		values = []interface{}{}
+	}
+	return compactJSON(values)
+}
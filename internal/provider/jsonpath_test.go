@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"testing"
+)
+
+const jsonPathTestDoc = `{
+  "f5-tenants:tenants": {
+    "tenant": [
+      {"name": "t1", "config": {"vcpu-cores-per-node": "4"}, "state": {"status": "Running", "mgmt-ip": "10.1.1.1"}},
+      {"name": "t2", "config": {"vcpu-cores-per-node": 8}, "state": {"status": "Pending"}}
+    ]
+  }
+}`
+
+func TestUnitJSONPath(t *testing.T) {
+	doc := mustDecodeJSON(t, jsonPathTestDoc)
+	for _, tc := range []struct {
+		expr, want string
+	}{
+		{`$.tenants.tenant[0].name`, `t1`},
+		{`$['f5-tenants:tenants'].tenant[-1].state.status`, `Pending`},
+		{`$.tenants.tenant[*].name`, `["t1","t2"]`},
+		{`$..status`, `["Running","Pending"]`},
+		{`$.tenants.tenant[?(@.name == 't2')].config`, `{"vcpu-cores-per-node":8}`},
+		{`$.tenants.tenant[?(@.config.vcpu-cores-per-node > 4)].name`, `t2`},
+		{`$.tenants.tenant[?(@.config.vcpu-cores-per-node == 4)].name`, `t1`},
+		{`$.tenants.tenant[?(@.state.mgmt-ip)].name`, `t1`},
+		{`$.tenants.tenant[?(@.state.status != "Running")].name`, `t2`},
+		{`$.tenants.tenant[5]`, `[]`},
+	} {
+		p, err := parseJSONPath(tc.expr)
+		if err != nil {
+			t.Errorf("parseJSONPath(%s): %v", tc.expr, err)
+			continue
+		}
+		if got := jsonPathValue(p.eval(doc)); got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestUnitJSONPathInvalid(t *testing.T) {
+	for _, expr := range []string{
+		`tenants.tenant`,
+		`$.tenants[`,
+		`$.tenants[abc]`,
+		`$.tenants[?(@.name == )]`,
+		`$.tenants[?(name == 'x')]`,
+		`$.tenants['t1]`,
+		`$.`,
+	} {
+		if _, err := parseJSONPath(expr); err == nil {
+			t.Errorf("parseJSONPath(%s) succeeded, want an error", expr)
+		}
+	}
+}
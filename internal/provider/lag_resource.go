@@ -21,6 +21,12 @@ var _ resource.Resource = &LagResource{}
 var _ resource.ResourceWithImportState = &LagResource{}
 var _ resource.ResourceWithValidateConfig = &LagResource{}
 
+// lagErrorAttributes maps LAG RESTCONF nodes to the attributes that set
+// them, so device errors are reported against the offending field.
+var lagErrorAttributes = restconfAttributes(map[string]string{
+	"aggregate-id": "members",
+}, "name", "native_vlan", "trunk_vlans", "lag_type", "mode", "interval")
+
 func NewLagResource() resource.Resource {
 	return &LagResource{}
 }
@@ -161,7 +167,7 @@ func (r *LagResource) Create(ctx context.Context, req resource.CreateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_lag` resource is supported with Velos Partition level/rSeries appliance.")
 		return
 	}
@@ -185,16 +191,16 @@ func (r *LagResource) Create(ctx context.Context, req resource.CreateRequest, re
 		modeIntervalConfig = getLagModeIntervalConfig(ctx, data)
 	}
 
-	respByte, err := r.client.CreateLagInterface(interfaceReqConfig, membersConfig, modeIntervalConfig)
+	respByte, err := r.client.WithContext(ctx).CreateLagInterface(interfaceReqConfig, membersConfig, modeIntervalConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Creating LAG interface failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, lagErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Creating LAG interface failed, got error: %s", err))
 		return
 	}
 
 	tflog.Debug(ctx, fmt.Sprintf("lagInterfaceReqConfig Response:%+v", string(respByte)))
 	data.Id = types.StringValue(data.Name.ValueString())
 
-	intfData, err := r.client.GetLagInterface(data.Name.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetLagInterface(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LAG Interface, got error: %s", err))
 		return
@@ -204,7 +210,7 @@ func (r *LagResource) Create(ctx context.Context, req resource.CreateRequest, re
 	// Only query LACP interface for LACP LAGs; static LAGs have no LACP entry.
 	var lacpData *f5ossdk.LacpInterfaceResponses
 	if isLACP {
-		lacpData, err = r.client.GetLacpInterface(data.Name.ValueString())
+		lacpData, err = r.client.WithContext(ctx).GetLacpInterface(data.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LACP Interface, got error: %s", err))
 			return
@@ -230,7 +236,7 @@ func (r *LagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	}
 	tflog.Info(ctx, fmt.Sprintf("[READ] Reading LAG interface :%+v", data.Id.ValueString()))
 
-	intfData, err := r.client.GetLagInterface(data.Id.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetLagInterface(data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LAG interface, got error: %s", err))
 		return
@@ -247,7 +253,7 @@ func (r *LagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	// Only query LACP interface for LACP LAGs; static LAGs have no LACP entry.
 	var lacpData *f5ossdk.LacpInterfaceResponses
 	if isLACP {
-		lacpData, err = r.client.GetLacpInterface(data.Id.ValueString())
+		lacpData, err = r.client.WithContext(ctx).GetLacpInterface(data.Id.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LACP Interface, got error: %s", err))
 			return
@@ -270,7 +276,7 @@ func (r *LagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_lag` resource is supported with Velos Partition level/rSeries appliance.")
 		return
 	}
@@ -289,7 +295,7 @@ func (r *LagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	}
 
 	if !data.Members.IsNull() && !data.Members.IsUnknown() {
-		memberData, err := r.client.GetLagInterface(data.Id.ValueString())
+		memberData, err := r.client.WithContext(ctx).GetLagInterface(data.Id.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read LAG interface members got error: %s", err))
 			return
@@ -299,30 +305,30 @@ func (r *LagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 			for _, member := range memberData.OpenconfigInterfacesInterface[0].OpenconfigIfAggregateAggregation.State.Members.Member {
 				haveMembers = append(haveMembers, member.Name)
 			}
-			err := r.client.RemoveLagMembers(haveMembers)
+			err := r.client.WithContext(ctx).RemoveLagMembers(haveMembers)
 			if err != nil {
 				resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to remove members from LAG interface, got error: %s", err))
 				return
 			}
 			membersConfig := getLagMembersConfig(ctx, data)
-			_, err = r.client.UpdateLagMembers(membersConfig)
+			_, err = r.client.WithContext(ctx).UpdateLagMembers(membersConfig)
 			if err != nil {
-				resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to update LAG interface members, got error: %s", err))
+				addClientErrorDiagnostic(&resp.Diagnostics, lagErrorAttributes, err, "F5OS Client Error", fmt.Sprintf("Unable to update LAG interface members, got error: %s", err))
 				return
 			}
 		}
 	}
 
-	respByte, err := r.client.UpdateLagInterface(data.Id.ValueString(), lagInterfaceReqConfig, modeIntervalConfig)
+	respByte, err := r.client.WithContext(ctx).UpdateLagInterface(data.Id.ValueString(), lagInterfaceReqConfig, modeIntervalConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Update LAG interface failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, lagErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Update LAG interface failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("lagInterfaceReqConfig Response:%+v", string(respByte)))
 
 	data.Id = types.StringValue(data.Name.ValueString())
 
-	intfData, err := r.client.GetLagInterface(data.Name.ValueString())
+	intfData, err := r.client.WithContext(ctx).GetLagInterface(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LAG Interface, got error: %s", err))
 		return
@@ -331,7 +337,7 @@ func (r *LagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	// Only query LACP interface for LACP LAGs.
 	var lacpData *f5ossdk.LacpInterfaceResponses
 	if isLACP {
-		lacpData, err = r.client.GetLacpInterface(data.Name.ValueString())
+		lacpData, err = r.client.WithContext(ctx).GetLacpInterface(data.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get LACP Interface, got error: %s", err))
 			return
@@ -355,7 +361,7 @@ func (r *LagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 	// Check if we have any physical interfaces that are a member of the LAG interface
-	memberData, err1 := r.client.GetLagInterface(data.Id.ValueString())
+	memberData, err1 := r.client.WithContext(ctx).GetLagInterface(data.Id.ValueString())
 	if err1 != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read LAG interface members got error: %s", err1))
 		return
@@ -366,7 +372,7 @@ func (r *LagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		for _, member := range memberData.OpenconfigInterfacesInterface[0].OpenconfigIfAggregateAggregation.State.Members.Member {
 			haveMembers = append(haveMembers, member.Name)
 		}
-		err := r.client.RemoveLagMembers(haveMembers)
+		err := r.client.WithContext(ctx).RemoveLagMembers(haveMembers)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Removing LAG interface member failed, got error: %s", err))
 			return
@@ -376,14 +382,14 @@ func (r *LagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	// Only remove the LACP interface for LACP LAGs; static LAGs have no LACP entry.
 	isLACP := data.LagType.ValueString() == "LACP"
 	if isLACP {
-		err2 := r.client.RemoveLacpInterface(data.Id.ValueString())
+		err2 := r.client.WithContext(ctx).RemoveLacpInterface(data.Id.ValueString())
 		if err2 != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to delete LACP interface, got error: %s", err2))
 			return
 		}
 	}
 
-	err3 := r.client.RemoveLagInterface(data.Id.ValueString())
+	err3 := r.client.WithContext(ctx).RemoveLagInterface(data.Id.ValueString())
 	if err3 != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to delete LAG interface, got error: %s", err3))
 		return
@@ -86,14 +86,14 @@ func (r *LicenseResource) Create(ctx context.Context, req resource.CreateRequest
 
 	regKey := data.RegistrationKey.ValueString()
 
-	err := r.client.Eula(regKey, addonKeys)
+	err := r.client.WithContext(ctx).Eula(regKey, addonKeys)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Error during EULA", err.Error())
 		return
 	}
 
-	err = r.client.LicenseInstall(regKey, addonKeys)
+	err = r.client.WithContext(ctx).LicenseInstall(regKey, addonKeys)
 	if err != nil {
 		resp.Diagnostics.AddError("Error during License Install", err.Error())
 		return
@@ -113,7 +113,7 @@ func (r *LicenseResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	license, err := r.client.GetLicense()
+	license, err := r.client.WithContext(ctx).GetLicense()
 	if err != nil {
 		resp.Diagnostics.AddError("Error during Get License", err.Error())
 		return
@@ -157,13 +157,13 @@ func (r *LicenseResource) Update(ctx context.Context, req resource.UpdateRequest
 		updateAddonKeys = planAddonKeys
 	}
 
-	err := r.client.Eula(regKey, updateAddonKeys)
+	err := r.client.WithContext(ctx).Eula(regKey, updateAddonKeys)
 	if err != nil {
 		resp.Diagnostics.AddError("Error during EULA", err.Error())
 		return
 	}
 
-	err = r.client.LicenseInstall(regKey, updateAddonKeys)
+	err = r.client.WithContext(ctx).LicenseInstall(regKey, updateAddonKeys)
 	if err != nil {
 		resp.Diagnostics.AddError("Error during License Install", err.Error())
 		return
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// concurrencyBackend is a fake F5OS endpoint that records the peak number
+// of requests it served at once, overall and for writes only.
+type concurrencyBackend struct {
+	*httptest.Server
+	inFlight, peak             int32
+	writesInFlight, peakWrites int32
+	mu                         sync.Mutex
+	starts                     []time.Time
+}
+
+func newConcurrencyBackend(hold time.Duration) *concurrencyBackend {
+	b := &concurrencyBackend{}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa") {
+			w.Header().Set("X-Auth-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if strings.Contains(r.URL.Path, "openconfig-platform:components") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b.mu.Lock()
+		b.starts = append(b.starts, time.Now())
+		b.mu.Unlock()
+		bump(&b.inFlight, &b.peak)
+		defer atomic.AddInt32(&b.inFlight, -1)
+		if r.Method != http.MethodGet {
+			bump(&b.writesInFlight, &b.peakWrites)
+			defer atomic.AddInt32(&b.writesInFlight, -1)
+		}
+		time.Sleep(hold)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	return b
+}
+
+func bump(n, peak *int32) {
+	v := atomic.AddInt32(n, 1)
+	for {
+		p := atomic.LoadInt32(peak)
+		if v <= p || atomic.CompareAndSwapInt32(peak, p, v) {
+			return
+		}
+	}
+}
+
+func newLimitedSession(t *testing.T, url string, cfg f5os.F5osConfig) *f5os.F5os {
+	t.Helper()
+	cfg.Host, cfg.User, cfg.Password = url, "admin", "admin"
+	session, err := f5os.NewSession(&cfg)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	return session
+}
+
+// parallel runs n copies of fn concurrently and waits for them.
+func parallel(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestRequestLimits_MaxConcurrent verifies that no more than
+// MaxConcurrentRequests requests reach the device at once.
+func TestRequestLimits_MaxConcurrent(t *testing.T) {
+	backend := newConcurrencyBackend(20 * time.Millisecond)
+	defer backend.Close()
+	session := newLimitedSession(t, backend.URL, f5os.F5osConfig{MaxConcurrentRequests: 2})
+
+	parallel(10, func(int) {
+		if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+			t.Errorf("GetRequest failed: %v", err)
+		}
+	})
+	if peak := atomic.LoadInt32(&backend.peak); peak > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, saw %d", peak)
+	}
+}
+
+// TestRequestLimits_MaxConcurrentStreaming verifies that a request keeps
+// its slot while the device is still streaming the response body.
+func TestRequestLimits_MaxConcurrentStreaming(t *testing.T) {
+	var inFlight, peak int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa") {
+			w.Header().Set("X-Auth-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if strings.Contains(r.URL.Path, "openconfig-platform:components") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		bump(&inFlight, &peak)
+		defer atomic.AddInt32(&inFlight, -1)
+		// Send the headers at once and the body later, like a large
+		// listing.
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"items":[`))
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`]}`))
+	}))
+	defer backend.Close()
+	session := newLimitedSession(t, backend.URL, f5os.F5osConfig{MaxConcurrentRequests: 1})
+
+	parallel(4, func(int) {
+		if _, err := session.GetRequest("/f5-tenant-images:images"); err != nil {
+			t.Errorf("GetRequest failed: %v", err)
+		}
+	})
+	if p := atomic.LoadInt32(&peak); p > 1 {
+		t.Fatalf("expected at most 1 request streaming at once, saw %d", p)
+	}
+}
+
+// TestRequestLimits_RequestsPerSecond verifies that request starts are
+// spaced according to RequestsPerSecond.
+func TestRequestLimits_RequestsPerSecond(t *testing.T) {
+	backend := newConcurrencyBackend(0)
+	defer backend.Close()
+	session := newLimitedSession(t, backend.URL, f5os.F5osConfig{RequestsPerSecond: 20})
+
+	parallel(5, func(int) {
+		if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+			t.Errorf("GetRequest failed: %v", err)
+		}
+	})
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.starts) != 5 {
+		t.Fatalf("expected 5 requests, got %d", len(backend.starts))
+	}
+	// Five requests at 20/s need at least four 50ms gaps; allow for
+	// timer slack but not for unpaced bursts.
+	if spread := backend.starts[4].Sub(backend.starts[0]); spread < 150*time.Millisecond {
+		t.Fatalf("expected requests spread over ~200ms, got %s", spread)
+	}
+}
+
+// TestRequestLimits_SerializeWrites verifies that writes from separate
+// sessions to one device are sent one at a time while reads still run
+// in parallel.
+func TestRequestLimits_SerializeWrites(t *testing.T) {
+	backend := newConcurrencyBackend(20 * time.Millisecond)
+	defer backend.Close()
+	sessions := []*f5os.F5os{
+		newLimitedSession(t, backend.URL, f5os.F5osConfig{SerializeWrites: true}),
+		newLimitedSession(t, backend.URL, f5os.F5osConfig{SerializeWrites: true}),
+	}
+
+	parallel(12, func(i int) {
+		s := sessions[i%2]
+		var err error
+		if i%3 == 0 {
+			_, err = s.GetRequest("/openconfig-vlan:vlans")
+		} else {
+			_, err = s.PatchRequest("/openconfig-vlan:vlans", []byte(`{}`))
+		}
+		if err != nil {
+			t.Errorf("request %d failed: %v", i, err)
+		}
+	})
+	if peak := atomic.LoadInt32(&backend.peakWrites); peak != 1 {
+		t.Fatalf("expected writes to be serialized, saw %d at once", peak)
+	}
+	if peak := atomic.LoadInt32(&backend.peak); peak < 2 {
+		t.Fatalf("expected reads to overlap writes, peak concurrency was %d", peak)
+	}
+}
+
+// TestUnitProviderConfigureRequestLimits verifies that the limit
+// attributes reach the client and that invalid environment values are
+// reported against the matching attribute.
+func TestUnitProviderConfigureRequestLimits(t *testing.T) {
+	t.Setenv("F5OS_MAX_CONCURRENT_REQUESTS", "")
+	t.Setenv("F5OS_REQUESTS_PER_SECOND", "")
+	t.Setenv("F5OS_SERIALIZE_WRITES", "true")
+	backend := newConcurrencyBackend(0)
+	defer backend.Close()
+
+	attrs := map[string]tftypes.Value{
+		"host":                    tftypes.NewValue(tftypes.String, backend.URL),
+		"username":                tftypes.NewValue(tftypes.String, "admin"),
+		"password":                tftypes.NewValue(tftypes.String, "admin"),
+		"max_concurrent_requests": tftypes.NewValue(tftypes.Number, 4),
+		"requests_per_second":     tftypes.NewValue(tftypes.Number, 2.5),
+	}
+	resp := testProviderConfigure(t, attrs)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	if client.MaxConcurrentRequests != 4 || client.RequestsPerSecond != 2.5 || !client.SerializeWrites {
+		t.Fatalf("unexpected limits: max=%d rps=%g serialize=%t", client.MaxConcurrentRequests, client.RequestsPerSecond, client.SerializeWrites)
+	}
+
+	t.Setenv("F5OS_REQUESTS_PER_SECOND", "fast")
+	delete(attrs, "requests_per_second")
+	resp = testProviderConfigure(t, attrs)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid F5OS_REQUESTS_PER_SECOND")
+	}
+	if d := resp.Diagnostics.Errors()[0]; d.Summary() != "Invalid F5OS_REQUESTS_PER_SECOND" {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary(), d.Detail())
+	}
+}
@@ -84,7 +84,7 @@ func (r *PartitionChangePasswordResource) Create(ctx context.Context, req resour
 		return
 	}
 
-	if r.client.PlatformType != "Velos Partition" {
+	if r.client.WithContext(ctx).Platform() != "Velos Partition" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_partition_change_password` resource is supported with Velos Partition level.")
 		return
 	}
@@ -92,7 +92,7 @@ func (r *PartitionChangePasswordResource) Create(ctx context.Context, req resour
 	passwordChangeConfig := getPartitionPasswordChangeConfig(data)
 	tflog.Info(ctx, fmt.Sprintf("passwordChangeConfig Data:%+v", passwordChangeConfig))
 
-	respByte, err := r.client.PartitionPasswordChange(data.UserName.ValueString(), passwordChangeConfig)
+	respByte, err := r.client.WithContext(ctx).PartitionPasswordChange(data.UserName.ValueString(), passwordChangeConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Partition Password change failed, got error: %s", err))
 		return
@@ -134,7 +134,7 @@ func (r *PartitionChangePasswordResource) Update(ctx context.Context, req resour
 	passwordChangeConfig := getPartitionPasswordChangeConfig(data)
 	tflog.Info(ctx, fmt.Sprintf("passwordChangeConfig Data:%+v", passwordChangeConfig))
 
-	respByte, err := r.client.PartitionPasswordChange(data.UserName.ValueString(), passwordChangeConfig)
+	respByte, err := r.client.WithContext(ctx).PartitionPasswordChange(data.UserName.ValueString(), passwordChangeConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Partition Password change failed, got error: %s", err))
 		return
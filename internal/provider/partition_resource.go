@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
@@ -24,6 +25,26 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PartitionResource{}
 var _ resource.ResourceWithImportState = &PartitionResource{}
+var _ resource.ResourceWithUpgradeState = &PartitionResource{}
+
+// partitionDefaultWait is how long create and update wait for the
+// partition to be running when neither the resource nor the provider set
+// a limit.
+const partitionDefaultWait = 360 * time.Second
+
+// partitionErrorAttributes maps partition RESTCONF nodes to the attributes
+// that set them, so device errors are reported against the offending field.
+var partitionErrorAttributes = restconfAttributes(map[string]string{
+	"iso-version":          "os_version",
+	"configuration-volume": "configuration_volume_size",
+	"images-volume":        "images_volume_size",
+	"shared-volume":        "shared_volume_size",
+	"ipv4/address":         "ipv4_mgmt_address",
+	"ipv4/gateway":         "ipv4_mgmt_gateway",
+	"ipv6/address":         "ipv6_mgmt_address",
+	"ipv6/gateway":         "ipv6_mgmt_gateway",
+	"slot/partition":       "slots",
+}, "name", "enabled")
 
 func NewPartitionResource() resource.Resource {
 	return &PartitionResource{}
@@ -47,7 +68,7 @@ type PartitionResourceModel struct {
 	ConfigurationVolumeSize types.Int64  `tfsdk:"configuration_volume_size"`
 	ImagesVolumeSize        types.Int64  `tfsdk:"images_volume_size"`
 	SharedVolumeSize        types.Int64  `tfsdk:"shared_volume_size"`
-	Timeout                 types.Int64  `tfsdk:"timeout"`
+	Timeouts                types.Object `tfsdk:"timeouts"`
 	Id                      types.String `tfsdk:"id"`
 }
 
@@ -136,12 +157,6 @@ func (r *PartitionResource) Schema(ctx context.Context, req resource.SchemaReque
 				Computed: true,
 				Default:  int64default.StaticInt64(10),
 			},
-			"timeout": schema.Int64Attribute{
-				MarkdownDescription: "The number of seconds to wait for partition to transition to running state.",
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(360),
-			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Unique Partition identifier",
@@ -150,6 +165,10 @@ func (r *PartitionResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock("the partition to be running", partitionDefaultWait),
+		},
+		Version: 1,
 	}
 }
 
@@ -170,7 +189,7 @@ func (r *PartitionResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	if r.client.PlatformType != "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() != "Velos Controller" {
 		resp.Diagnostics.AddError("F5OS Client Error", "`f5os_partition` resource is supported on Velos Controllers only")
 		return
 	}
@@ -178,9 +197,9 @@ func (r *PartitionResource) Create(ctx context.Context, req resource.CreateReque
 	partitionConfig := getPartitionCreateConfig(ctx, req, resp)
 	tflog.Info(ctx, fmt.Sprintf("partitionConfig Data:%+v", partitionConfig))
 
-	respByte, err := r.client.CreatePartition(partitionConfig)
+	respByte, err := r.client.WithContext(ctx).CreatePartition(partitionConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Create Partition failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, partitionErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Create Partition failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("partitionConfig Response:%+v", string(respByte)))
@@ -188,13 +207,17 @@ func (r *PartitionResource) Create(ctx context.Context, req resource.CreateReque
 	if !data.Slots.IsNull() && !data.Slots.IsUnknown() {
 		var slots []int64
 		data.Slots.ElementsAs(ctx, &slots, false)
-		_, err := r.client.SetSlot(data.Name.ValueString(), slots)
+		_, err := r.client.WithContext(ctx).SetSlot(data.Name.ValueString(), slots)
 		if err != nil {
-			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to add slots to Partition, got error: %s", err))
+			addClientErrorDiagnostic(&resp.Diagnostics, partitionErrorAttributes, err, "F5OS Client Error", fmt.Sprintf("Unable to add slots to Partition, got error: %s", err))
 			return
 		}
 	}
-	respByte3, err := r.client.CheckPartitionState(data.Name.ValueString(), int(data.Timeout.ValueInt64()))
+	wait, diags := operationTimeout(ctx, data.Timeouts, "create", r.client, partitionDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	respByte3, err := r.client.WithContext(ctx).CheckPartitionState(data.Name.ValueString(), timeoutSeconds(wait))
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Waiting for Partition deploy, got error: %s", err))
 		return
@@ -202,14 +225,14 @@ func (r *PartitionResource) Create(ctx context.Context, req resource.CreateReque
 	tflog.Info(ctx, fmt.Sprintf("Partition Deploy Response:%+v", string(respByte3)))
 	data.Id = types.StringValue(data.Name.ValueString())
 
-	partData, err := r.client.GetPartition(data.Name.ValueString())
+	partData, err := r.client.WithContext(ctx).GetPartition(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Partition, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("get partitionConfig :%+v", partData))
 
-	slotData, err := r.client.GetPartitionSlots(data.Name.ValueString())
+	slotData, err := r.client.WithContext(ctx).GetPartitionSlots(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read Partition slots got error: %s", err))
 		return
@@ -232,7 +255,7 @@ func (r *PartitionResource) Create(ctx context.Context, req resource.CreateReque
 	teemInfo := make(map[string]interface{})
 	teemInfo["teemData"] = r.teemData
 	r.client.Metadata = teemInfo
-	err = r.client.SendTeem(teemInfo)
+	err = r.client.WithContext(ctx).SendTeem(teemInfo)
 	if err != nil {
 		resp.Diagnostics.AddError("Teem Error", fmt.Sprintf("Sending Teem Data failed: %s", err))
 	}
@@ -250,14 +273,19 @@ func (r *PartitionResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "read", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 
-	partData, err := r.client.GetPartition(data.Name.ValueString())
+	partData, err := r.client.WithContext(ctx).GetPartition(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Partition, got error: %s", err))
 		return
 	}
 
-	slotData, err := r.client.GetPartitionSlots(data.Name.ValueString())
+	slotData, err := r.client.WithContext(ctx).GetPartitionSlots(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read Partition slots got error: %s", err))
 		return
@@ -292,9 +320,9 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	if !data.OsVersion.IsNull() && !data.OsVersion.IsUnknown() {
-		success, err := r.client.UpdatePartitionIso(data.Name.ValueString(), data.OsVersion.ValueString())
+		success, err := r.client.WithContext(ctx).UpdatePartitionIso(data.Name.ValueString(), data.OsVersion.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to change partition os_version got error: %s", err))
+			addClientErrorDiagnostic(&resp.Diagnostics, partitionErrorAttributes, err, "F5OS Client Error", fmt.Sprintf("Unable to change partition os_version got error: %s", err))
 			return
 		}
 		if success {
@@ -303,7 +331,7 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	if !data.Slots.IsNull() && !data.Slots.IsUnknown() {
-		slotData, err := r.client.GetPartitionSlots(data.Name.ValueString())
+		slotData, err := r.client.WithContext(ctx).GetPartitionSlots(data.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read Partition slots got error: %s", err))
 			return
@@ -314,7 +342,7 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 			data.Slots.ElementsAs(ctx, &slots, false)
 			slotDiff := getIntSliceDifference(slotData, slots)
 			if len(slotDiff) > 0 {
-				_, err := r.client.SetSlot("none", slotDiff)
+				_, err := r.client.WithContext(ctx).SetSlot("none", slotDiff)
 				if err != nil {
 					resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to disassociate slots from Partition, got error: %s", err))
 					return
@@ -322,9 +350,9 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 			}
 			// next we update slots on partition
 			data.Slots.ElementsAs(ctx, &slots, false)
-			_, err := r.client.SetSlot(data.Name.ValueString(), slots)
+			_, err := r.client.WithContext(ctx).SetSlot(data.Name.ValueString(), slots)
 			if err != nil {
-				resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to update slots on Partition, got error: %s", err))
+				addClientErrorDiagnostic(&resp.Diagnostics, partitionErrorAttributes, err, "F5OS Client Error", fmt.Sprintf("Unable to update slots on Partition, got error: %s", err))
 				return
 			}
 		}
@@ -332,14 +360,18 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 
 	partitionConfig := getPartitionUpdateConfig(ctx, req, resp)
 
-	respByte, err := r.client.UpdatePartition(data.Name.ValueString(), partitionConfig)
+	respByte, err := r.client.WithContext(ctx).UpdatePartition(data.Name.ValueString(), partitionConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Tenant Deploy failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, partitionErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Tenant Deploy failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("partitionConfig Data:%+v", string(respByte)))
 
-	respByte2, err := r.client.CheckPartitionState(data.Name.ValueString(), int(data.Timeout.ValueInt64()))
+	wait, diags := operationTimeout(ctx, data.Timeouts, "update", r.client, partitionDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	respByte2, err := r.client.WithContext(ctx).CheckPartitionState(data.Name.ValueString(), timeoutSeconds(wait))
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Waiting for Partition state after update, got error: %s", err))
 		return
@@ -347,14 +379,14 @@ func (r *PartitionResource) Update(ctx context.Context, req resource.UpdateReque
 	tflog.Info(ctx, fmt.Sprintf("Partition Deploy Response:%+v", string(respByte2)))
 	data.Id = types.StringValue(data.Name.ValueString())
 
-	partData, err := r.client.GetPartition(data.Name.ValueString())
+	partData, err := r.client.WithContext(ctx).GetPartition(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Partition, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("get partitionConfig :%+v", partData))
 
-	slotData, err := r.client.GetPartitionSlots(data.Name.ValueString())
+	slotData, err := r.client.WithContext(ctx).GetPartitionSlots(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read Partition slots got error: %s", err))
 		return
@@ -388,29 +420,64 @@ func (r *PartitionResource) Delete(ctx context.Context, req resource.DeleteReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "delete", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	// first we read slots associated with partition to disassociate them
-	slotData, err1 := r.client.GetPartitionSlots(data.Name.ValueString())
+	slotData, err1 := r.client.WithContext(ctx).GetPartitionSlots(data.Name.ValueString())
 	if err1 != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read Partition slots got error: %s", err1))
 		return
 	}
 
 	if slotData != nil {
-		_, err2 := r.client.SetSlot("none", slotData)
+		_, err2 := r.client.WithContext(ctx).SetSlot("none", slotData)
 		if err2 != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to disassociate slots from Partition, got error: %s", err2))
 			return
 		}
 	}
 
-	err3 := r.client.DeletePartition(data.Name.ValueString())
+	err3 := r.client.WithContext(ctx).DeletePartition(data.Name.ValueString())
 	if err3 != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Partition, got error: %s", err3))
 		return
 	}
 }
 
+// UpgradeState moves the integer `timeout` of schema version 0 into the
+// `timeouts` block.
+func (r *PartitionResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	return map[int64]resource.StateUpgrader{
+		0: timeoutsStateUpgrader(partitionSchemaV0, schemaResp.Schema, int64(partitionDefaultWait/time.Second), "create", "update"),
+	}
+}
+
+// partitionSchemaV0 is the shape of schema version 0, kept to read state
+// written by provider releases before 1.14.0.
+var partitionSchemaV0 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"name":                      schema.StringAttribute{Required: true},
+		"ipv4_mgmt_address":         schema.StringAttribute{Optional: true},
+		"ipv4_mgmt_gateway":         schema.StringAttribute{Optional: true},
+		"ipv6_mgmt_address":         schema.StringAttribute{Optional: true},
+		"ipv6_mgmt_gateway":         schema.StringAttribute{Optional: true},
+		"os_version":                schema.StringAttribute{Optional: true, Computed: true},
+		"slots":                     schema.ListAttribute{Optional: true, Computed: true, ElementType: types.Int64Type},
+		"enabled":                   schema.BoolAttribute{Optional: true, Computed: true},
+		"configuration_volume_size": schema.Int64Attribute{Optional: true, Computed: true},
+		"images_volume_size":        schema.Int64Attribute{Optional: true, Computed: true},
+		"shared_volume_size":        schema.Int64Attribute{Optional: true, Computed: true},
+		"timeout":                   schema.Int64Attribute{Optional: true, Computed: true},
+		"id":                        schema.StringAttribute{Computed: true},
+	},
+}
+
 func (r *PartitionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
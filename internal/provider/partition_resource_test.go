@@ -318,7 +318,7 @@ func TestUnitPartitionCreateReadDelete(t *testing.T) {
 				ResourceName:            "f5os_partition.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"timeout", "slots.#", "slots.0", "slots.1"},
+				ImportStateVerifyIgnore: []string{"timeouts", "slots.#", "slots.0", "slots.1"},
 			},
 		},
 	})
@@ -1005,8 +1005,8 @@ func testAccPreCheckVelosController(t *testing.T) {
 	if err != nil {
 		t.Fatalf("testAccPreCheckVelosController: failed to create session: %s", err)
 	}
-	if client.PlatformType != "Velos Controller" {
-		t.Skipf("skipping: test requires Velos Controller but device is %q", client.PlatformType)
+	if client.Platform() != "Velos Controller" {
+		t.Skipf("skipping: test requires Velos Controller but device is %q", client.Platform())
 	}
 }
 
@@ -1075,7 +1075,7 @@ func TestAccPartitionCreateTC1Resource(t *testing.T) {
 				ResourceName:            "f5os_partition.acctest",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"timeout", "slots.#", "slots.0"},
+				ImportStateVerifyIgnore: []string{"timeouts", "slots.#", "slots.0"},
 			},
 			// Step 3: Update volume sizes
 			{
@@ -1151,7 +1151,9 @@ resource "f5os_partition" "test" {
   ipv4_mgmt_address = "10.144.140.125/24"
   ipv4_mgmt_gateway = "10.144.140.253"
   slots             = [1, 2]
-  timeout           = 1
+  timeouts {
+    create = "1s"
+  }
 }
 `
 
@@ -1179,7 +1181,9 @@ resource "f5os_partition" "test" {
   configuration_volume_size = 12
   images_volume_size        = 20
   shared_volume_size        = 12
-  timeout                   = 1
+  timeouts {
+    create = "1s"
+  }
 }
 `
 
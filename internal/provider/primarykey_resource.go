@@ -137,7 +137,7 @@ func (r *PrimaryKeyResource) waitForPrimaryKeyMigration(ctx context.Context) err
 	}
 
 	for poll := 0; poll < maxPolls; poll++ {
-		keyData, err := r.client.GetPrimaryKey()
+		keyData, err := r.client.WithContext(ctx).GetPrimaryKey()
 		switch {
 		case err != nil:
 			tflog.Warn(ctx, fmt.Sprintf("Error polling primary key status: %s", err))
@@ -178,12 +178,12 @@ func (r *PrimaryKeyResource) Create(ctx context.Context, req resource.CreateRequ
 
 	tflog.Debug(ctx, fmt.Sprintf("PrimaryKey Request Payload: %+v", primaryKeyReq))
 
-	_ = r.client.SendTeem(map[string]any{"teemData": r.teemData})
+	_ = r.client.WithContext(ctx).SendTeem(map[string]any{"teemData": r.teemData})
 
 	// Always set the key on Create. Create is only called for new or
 	// recreated resources (passphrase/salt have RequiresReplace), so
 	// SetPrimaryKey must always run to apply the configured credentials.
-	_, err := r.client.SetPrimaryKey(primaryKeyReq)
+	_, err := r.client.WithContext(ctx).SetPrimaryKey(primaryKeyReq)
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Failed to create PrimaryKey: %s", err))
 		return
@@ -196,7 +196,7 @@ func (r *PrimaryKeyResource) Create(ctx context.Context, req resource.CreateRequ
 	}
 
 	// Now get the state from the device and update the Terraform state
-	keyData, err := r.client.GetPrimaryKey()
+	keyData, err := r.client.WithContext(ctx).GetPrimaryKey()
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Failed to fetch state after setting PrimaryKey: %s", err))
 		return
@@ -222,7 +222,7 @@ func (r *PrimaryKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 	tflog.Info(ctx, "[READ] Reading F5OS Primary Key Configuration")
 
 	// Fetch the primary key state from the device
-	keyData, err := r.client.GetPrimaryKey()
+	keyData, err := r.client.WithContext(ctx).GetPrimaryKey()
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Failed to fetch Primary Key configuration: %s", err))
 		return
@@ -258,7 +258,7 @@ func (r *PrimaryKeyResource) Update(ctx context.Context, req resource.UpdateRequ
 		keyReqConfig := getPrimaryKeyConfig(data)
 		tflog.Debug(ctx, fmt.Sprintf("PrimaryKey Update Payload: %+v", keyReqConfig))
 
-		_, err := r.client.SetPrimaryKey(keyReqConfig)
+		_, err := r.client.WithContext(ctx).SetPrimaryKey(keyReqConfig)
 		if err != nil {
 			resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Failed to update Primary Key: %s", err))
 			return
@@ -273,7 +273,7 @@ func (r *PrimaryKeyResource) Update(ctx context.Context, req resource.UpdateRequ
 	}
 
 	// Fetch the latest status after update
-	keyData, err := r.client.GetPrimaryKey()
+	keyData, err := r.client.WithContext(ctx).GetPrimaryKey()
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Failed to retrieve Primary Key after update: %s", err))
 		return
@@ -1,24 +1,39 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 // Ensure F5osProvider satisfies various provider interfaces.
@@ -34,13 +49,47 @@ type F5osProvider struct {
 
 // F5osProviderModel describes the provider data model.
 type F5osProviderModel struct {
-	Host             types.String `tfsdk:"host"`
-	Username         types.String `tfsdk:"username"`
-	Password         types.String `tfsdk:"password"`
-	Port             types.Int64  `tfsdk:"port"`
-	TeemDisable      types.Bool   `tfsdk:"teem_disable"`
-	DisableSslVerify types.Bool   `tfsdk:"disable_tls_verify"`
-	CustomHeaders    types.Map    `tfsdk:"custom_headers"`
+	Host                  types.String  `tfsdk:"host"`
+	Username              types.String  `tfsdk:"username"`
+	Password              types.String  `tfsdk:"password"`
+	Port                  types.Int64   `tfsdk:"port"`
+	TeemDisable           types.Bool    `tfsdk:"teem_disable"`
+	DisableSslVerify      types.Bool    `tfsdk:"disable_tls_verify"`
+	CustomHeaders         types.Map     `tfsdk:"custom_headers"`
+	CACertPEM             types.String  `tfsdk:"ca_cert_pem"`
+	CACertFile            types.String  `tfsdk:"ca_cert_file"`
+	ClientCert            types.String  `tfsdk:"client_cert"`
+	ClientKey             types.String  `tfsdk:"client_key"`
+	TLSServerName         types.String  `tfsdk:"tls_server_name"`
+	AuthToken             types.String  `tfsdk:"auth_token"`
+	Retry                 types.Object  `tfsdk:"retry"`
+	HTTPTraceFile         types.String  `tfsdk:"http_trace_file"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	SerializeWrites       types.Bool    `tfsdk:"serialize_writes"`
+	PasswordCommand       types.List    `tfsdk:"password_command"`
+	Transport             types.String  `tfsdk:"transport"`
+	NetconfPort           types.Int64   `tfsdk:"netconf_port"`
+	SSHHostKey            types.String  `tfsdk:"ssh_host_key"`
+	APITimeout            types.String  `tfsdk:"api_timeout"`
+	PollInterval          types.String  `tfsdk:"poll_interval"`
+	MaxWait               types.String  `tfsdk:"max_wait"`
+	ProxyURL              types.String  `tfsdk:"proxy_url"`
+	ProxyUsername         types.String  `tfsdk:"proxy_username"`
+	ProxyPassword         types.String  `tfsdk:"proxy_password"`
+	NoProxy               types.String  `tfsdk:"no_proxy"`
+	SessionCacheDir       types.String  `tfsdk:"session_cache_dir"`
+}
+
+// retryConfigModel maps the provider `retry` block onto
+// f5ossdk.RetryPolicy.
+type retryConfigModel struct {
+	MaxAttempts          types.Int64   `tfsdk:"max_attempts"`
+	InitialBackoff       types.String  `tfsdk:"initial_backoff"`
+	MaxBackoff           types.String  `tfsdk:"max_backoff"`
+	Jitter               types.Float64 `tfsdk:"jitter"`
+	RetryableStatusCodes types.List    `tfsdk:"retryable_status_codes"`
+	RetryableErrors      types.List    `tfsdk:"retryable_errors"`
 }
 type TeemData struct {
 	ResourceName      string
@@ -67,41 +116,145 @@ var teemData = &TeemData{}
 // (Terraform spawns and tears down the plugin per invocation), and in
 // tests the process ends when `go test` exits, so unbounded growth is
 // not a concern for realistic key cardinality.
+//
+// uncachedSessions holds the clients created while the cache is disabled,
+// so that CloseSessions logs them out as well.
 var (
-	sessionCacheMu sync.Mutex
-	sessionCache   = map[string]*f5ossdk.F5os{}
+	sessionCacheMu   sync.Mutex
+	sessionCache     = map[string]*f5ossdk.F5os{}
+	uncachedSessions []*f5ossdk.F5os
 )
 
+// CloseSessions logs every session opened by Configure out of its device
+// and clears the credentials it holds. main calls it when the plugin server shuts
+// down, so a plan or apply does not leave RESTCONF sessions open against
+// the device's session limit. Sessions are closed in parallel and the
+// whole call is bounded by sessionCloseTimeout, because Terraform kills
+// the plugin shortly after asking it to stop. Failures are logged to
+// stderr, which Terraform records in its log, as no request context
+// carries a logger by then.
+func CloseSessions() {
+	sessionCacheMu.Lock()
+	clients := uncachedSessions
+	uncachedSessions = nil
+	for key, client := range sessionCache {
+		clients = append(clients, client)
+		delete(sessionCache, key)
+	}
+	sessionCacheMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCloseTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *f5ossdk.F5os) {
+			defer wg.Done()
+			if err := client.WithContext(ctx).Close(); err != nil {
+				log.Printf("[WARN] Failed to log out of F5OS session on %s: %s", client.Host, err)
+			}
+		}(client)
+	}
+	wg.Wait()
+}
+
+// sessionCloseTimeout bounds CloseSessions.
+const sessionCloseTimeout = time.Second
+
+// passwordCommandCredentials returns a credentials callback that runs
+// command and uses its standard output, minus the trailing newline, as
+// the password for username.
+func passwordCommandCredentials(username string, command []string) f5ossdk.CredentialsFunc {
+	return func(ctx context.Context) (string, string, error) {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", "", fmt.Errorf("password_command %q failed: %w: %s", command[0], err, msg)
+			}
+			return "", "", fmt.Errorf("password_command %q failed: %w", command[0], err)
+		}
+		password := strings.TrimRight(string(out), "\r\n")
+		if password == "" {
+			return "", "", fmt.Errorf("password_command %q printed no password", command[0])
+		}
+		return username, password, nil
+	}
+}
+
 // sessionCacheKey derives a stable, opaque key from the fields that
 // determine session identity. The key itself is a SHA-256 digest so
 // the map key does not contain the plaintext password.
 //
 // Security caveat: the cached value is a *f5ossdk.F5os client, which
-// currently retains the plaintext password on the struct itself (see
+// retains the plaintext password on the struct itself when the
+// provider authenticates with username/password (see
 // vendor/gitswarm.f5net.com/terraform-providers/f5osclient/f5os.go).
 // Hashing the key therefore does NOT prevent credentials from being
 // resident in process memory for the lifetime of the cached client.
 // The design tradeoff is intentional: the provider process is
 // short-lived (Terraform spawns and tears down the plugin per
 // invocation) and the alternative — re-authenticating on every
-// Configure — trips F5OS 2.0's stricter auth rate-limit. A future
-// refactor could store token-only sessions or clear the password
-// after the initial NewSession returns.
-func sessionCacheKey(host string, port int, user, password string, disableSSL bool, headers map[string]string) string {
+// Configure — trips F5OS 2.0's stricter auth rate-limit. Configuring
+// `auth_token` instead yields a token-only session with no password, and
+// `password_command` a session that runs the command for each login
+// rather than holding the password. The command line is part of the key.
+//
+// The TLS trust and client-certificate settings are part of the key:
+// two provider aliases pointing at the same host with different CA
+// bundles must not share a transport. So are the NETCONF port and pinned
+// SSH host key, the proxy settings, and the timing settings the session
+// carries, as is the file a session keeps its token in between runs.
+func sessionCacheKey(cfg *f5ossdk.F5osConfig, passwordCommand []string, sshHostKey string) string {
 	h := sha256.New()
-	fmt.Fprintf(h, "host=%s\x00port=%d\x00user=%s\x00pw=%s\x00tlsSkip=%t\x00", host, port, user, password, disableSSL)
+	fmt.Fprintf(h, "host=%s\x00port=%d\x00user=%s\x00pw=%s\x00tlsSkip=%t\x00", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DisableSSLVerify)
+	fmt.Fprintf(h, "pwcmd=%q\x00", passwordCommand)
+	fmt.Fprintf(h, "token=%s\x00", cfg.Token)
+	if cfg.Retry != nil {
+		fmt.Fprintf(h, "retry=%+v\x00", *cfg.Retry)
+	}
+	fmt.Fprintf(h, "ca=%s\x00cert=%s\x00key=%s\x00sni=%s\x00", cfg.CACertPEM, cfg.ClientCertPEM, cfg.ClientKeyPEM, cfg.TLSServerName)
+	fmt.Fprintf(h, "trace=%s\x00cassette=%s\x00", cfg.HTTPTraceFile, cfg.CassetteFile)
+	fmt.Fprintf(h, "limits=%d/%g/%t\x00", cfg.MaxConcurrentRequests, cfg.RequestsPerSecond, cfg.SerializeWrites)
+	if cfg.ConfigOptions != nil {
+		fmt.Fprintf(h, "apiTimeout=%s\x00", cfg.ConfigOptions.APICallTimeout)
+	}
+	fmt.Fprintf(h, "poll=%s\x00maxWait=%s\x00", cfg.PollInterval, cfg.MaxWait)
+	if cfg.Netconf != nil {
+		fmt.Fprintf(h, "netconf=%d\x00hostkey=%s\x00", cfg.Netconf.Port, sshHostKey)
+	}
+	if c, ok := cfg.TokenCache.(*f5ossdk.FileTokenCache); ok {
+		fmt.Fprintf(h, "tokenCache=%s\x00", c.Path)
+	}
+	if cfg.Proxy != nil {
+		fmt.Fprintf(h, "proxy=%s\x00proxyUser=%s\x00proxyPw=%s\x00noProxy=%s\x00", cfg.Proxy.URL, cfg.Proxy.Username, cfg.Proxy.Password, cfg.Proxy.NoProxy)
+	}
 	// Sort header keys so map iteration order doesn't perturb the key.
-	keys := make([]string, 0, len(headers))
-	for k := range headers {
+	keys := make([]string, 0, len(cfg.CustomHeaders))
+	for k := range cfg.CustomHeaders {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		fmt.Fprintf(h, "hdr:%s=%s\x00", k, headers[k])
+		fmt.Fprintf(h, "hdr:%s=%s\x00", k, cfg.CustomHeaders[k])
 	}
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// tokenCacheFile returns the file in dir that keeps the token of sessions
+// configured like cfg between runs. It is named after the sessionCacheKey
+// of cfg without the password, so the name reveals nothing about the
+// password, which only goes into the key the token is encrypted with, and
+// a changed password replaces the token rather than leaving a stale file.
+func tokenCacheFile(dir string, cfg *f5ossdk.F5osConfig, passwordCommand []string, sshHostKey string) string {
+	keyed := *cfg
+	keyed.Password = ""
+	keyed.TokenCache = nil
+	return filepath.Join(dir, sessionCacheKey(&keyed, passwordCommand, sshHostKey)+".token")
+}
+
 // isTerraformVersionAtLeast returns true when v (e.g. "1.10.0",
 // "1.5.0-beta1") is >= major.minor.patch under normal semver
 // ordering. It replaces a previous lexicographic string comparison
@@ -155,18 +308,172 @@ func (p *F5osProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"password_command": schema.ListAttribute{
+				MarkdownDescription: "Command and arguments run to obtain the password whenever the provider logs in, for example `[\"vault\", \"kv\", \"get\", \"-field=password\", \"secret/f5os\"]`. The command must print the password on standard output. The password is used for the login only and is not kept in memory, so an expired session logs in again by running the command. Conflicts with `password`.\nCan be provided via `F5OS_PASSWORD_COMMAND` environment variable, as a space-separated command line.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("password")),
+				},
+			},
+			"auth_token": schema.StringAttribute{
+				MarkdownDescription: "Pre-issued F5OS `X-Auth-Token` used instead of `username`/`password`. When set, the provider skips the basic-auth login and does not keep a password in memory.\nA token session cannot re-authenticate: once the token expires, requests fail with a token expired error and a new token must be supplied.\nCan be provided via `F5OS_TOKEN` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
 			"port": schema.Int64Attribute{
 				MarkdownDescription: "Port Number to be used to make API calls to HOST",
 				Optional:            true,
 			},
 			"disable_tls_verify": schema.BoolAttribute{
-				MarkdownDescription: "`disable_tls_verify` controls whether a client verifies the server's certificate chain and host name. default it is set to `true`. If `disable_tls_verify` is true, crypto/tls accepts any certificate presented by the server and any host name in that certificate. In this mode, TLS is susceptible to machine-in-the-middle attacks unless custom verification is used.\ncan be provided by `DISABLE_TLS_VERIFY` environment variable.\n\n~> **NOTE** If it is set to `false`, certificate/ca certificates should be added to `trusted store` of host where we are running this provider.",
+				MarkdownDescription: "`disable_tls_verify` controls whether a client verifies the server's certificate chain and host name. default it is set to `true`, unless `ca_cert_pem` or `ca_cert_file` is provided. If `disable_tls_verify` is true, crypto/tls accepts any certificate presented by the server and any host name in that certificate. In this mode, TLS is susceptible to machine-in-the-middle attacks unless custom verification is used.\ncan be provided by `DISABLE_TLS_VERIFY` environment variable.\n\n~> **NOTE** If it is set to `false`, the device certificate must chain to a CA in `ca_cert_pem`/`ca_cert_file` or in the `trusted store` of host where we are running this provider.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate bundle used to verify the F5OS device certificate, in addition to the host trust store.\nSetting it enables certificate verification unless `disable_tls_verify` is explicitly `true`.\nConflicts with `ca_cert_file`. Can be provided via `F5OS_CA_CERT_PEM` environment variable.",
 				Optional:            true,
 			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded CA certificate bundle used to verify the F5OS device certificate, in addition to the host trust store.\nSetting it enables certificate verification unless `disable_tls_verify` is explicitly `true`.\nConflicts with `ca_cert_pem`. Can be provided via `F5OS_CA_CERT_FILE` environment variable.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate presented to the F5OS device for mutual TLS. Requires `client_key`.\nCan be provided via `F5OS_CLIENT_CERT` environment variable.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key for `client_cert`. Requires `client_cert`.\nCan be provided via `F5OS_CLIENT_KEY` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: "Host name used to verify the F5OS device certificate and sent as TLS SNI, when it differs from `host` (for example when `host` is an IP address).\nCan be provided via `F5OS_TLS_SERVER_NAME` environment variable.",
+				Optional:            true,
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Retry and backoff policy for F5OS API calls, including the initial login. Unset fields keep the defaults: 6 attempts, 10 seconds apart, retrying transient connection errors.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Total number of attempts per API call, including the first. Defaults to `6`.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"initial_backoff": schema.StringAttribute{
+						MarkdownDescription: "Delay before the first retry, for example `2s`. Each further retry doubles the delay up to `max_backoff`. Defaults to `10s`.",
+						Optional:            true,
+						Validators:          []validator.String{durationValidator{}},
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Upper bound for the delay between retries, for example `1m`. Defaults to `initial_backoff` when neither is set (a fixed delay), otherwise `60s`.",
+						Optional:            true,
+						Validators:          []validator.String{durationValidator{}},
+					},
+					"jitter": schema.Float64Attribute{
+						MarkdownDescription: "Fraction (`0` to `1`) by which each delay is randomly lengthened or shortened, so parallel operations do not retry in lockstep. Defaults to `0`.",
+						Optional:            true,
+					},
+					"retryable_status_codes": schema.ListAttribute{
+						MarkdownDescription: "HTTP status codes that are retried, for example `[429, 502, 503, 504]`. Any other error status fails immediately. When unset, error responses keep their historical handling, which retries most of them.",
+						Optional:            true,
+						ElementType:         types.Int64Type,
+						Validators: []validator.List{
+							listvalidator.ValueInt64sAre(int64validator.Between(100, 599)),
+						},
+					},
+					"retryable_errors": schema.ListAttribute{
+						MarkdownDescription: "Substrings of transport error messages that are retried. Defaults to `context deadline exceeded`, `connection refused`, `connection reset`, `EOF` and `no such host`.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
 			"teem_disable": schema.BoolAttribute{
 				MarkdownDescription: "If this flag set to true,sending telemetry data to TEEM will be disabled,can be provided via `TEEM_DISABLE` environment variable.",
 				Optional:            true,
 			},
+			"http_trace_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a HAR file that records every F5OS API request and response, with timings and retry attempts, for troubleshooting. Requests are added to an existing file. Auth tokens, basic-auth headers and password-like JSON and XML fields are redacted.\nCan be provided via `F5OS_HTTP_TRACE_FILE` environment variable.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of API requests in flight to the device at once, across every resource sharing this provider's session. Unset means no limit.\nCan be provided via `F5OS_MAX_CONCURRENT_REQUESTS` environment variable.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum rate at which API requests, including logins, are started against the device, for example `5` or `0.5`. Unset means no limit.\nCan be provided via `F5OS_REQUESTS_PER_SECOND` environment variable.",
+				Optional:            true,
+			},
+			"serialize_writes": schema.BoolAttribute{
+				MarkdownDescription: "When `true`, configuration changes (POST, PUT, PATCH and DELETE requests) are sent to the device one at a time, while reads continue in parallel. Defaults to `false`.\nCan be provided via `F5OS_SERIALIZE_WRITES` environment variable.",
+				Optional:            true,
+			},
+			"api_timeout": schema.StringAttribute{
+				MarkdownDescription: "Time limit for a single F5OS API call, for example `3m`. Raise it when large RPCs such as qkview or config backup exports take longer on a loaded system. Defaults to `60s`.\nCan be provided via `F5OS_API_TIMEOUT` environment variable.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"poll_interval": schema.StringAttribute{
+				MarkdownDescription: "Delay between status reads while waiting for tenant deployment, image import, partition creation and similar operations, and between retries when `retry` sets no backoff. Unset keeps the per-operation defaults of 5 to 80 seconds. The interval attributes of `f5os_rpc` and `f5os_tenant` `wait_for_ready` take precedence over it.\nCan be provided via `F5OS_POLL_INTERVAL` environment variable.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"max_wait": schema.StringAttribute{
+				MarkdownDescription: "Default limit on how long a resource waits for an operation to complete, for example `30m`, used when its `timeouts` block does not set one. Unset keeps each resource's own default.\nCan be provided via `F5OS_MAX_WAIT` environment variable.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"transport": schema.StringAttribute{
+				MarkdownDescription: "Protocol used to manage the device: `restconf` (the default) or `netconf`, which carries the same requests as NETCONF RPCs over SSH, for environments where only SSH reaches the device. Over NETCONF the TLS settings, `custom_headers` and `auth_token` do not apply, and `f5os_restconf`, `f5os_rpc` and the `f5os_restconf` data source are not supported.\nCan be provided via `F5OS_TRANSPORT` environment variable.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("restconf", "netconf"),
+				},
+			},
+			"netconf_port": schema.Int64Attribute{
+				MarkdownDescription: "SSH port of the device's NETCONF service, used when `transport` is `netconf`. Defaults to `830`.\nCan be provided via `F5OS_NETCONF_PORT` environment variable.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"ssh_host_key": schema.StringAttribute{
+				MarkdownDescription: "Public SSH host key of the device in `authorized_keys` format (for example `ssh-ed25519 AAAA...`), which the NETCONF connection must present. When unset, the host key is checked against `~/.ssh/known_hosts`, or not at all if `disable_tls_verify` is `true`.\nCan be provided via `F5OS_SSH_HOST_KEY` environment variable.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "Proxy that API requests to this device go through, instead of the one selected by `HTTPS_PROXY`/`HTTP_PROXY`, for example `http://proxy.example.com:3128` or `socks5://jumphost:1080`. Supports `http`, `https` and `socks5` proxies. Does not apply to the NETCONF transport.\nCan be provided via `F5OS_PROXY_URL` environment variable.",
+				Optional:            true,
+			},
+			"proxy_username": schema.StringAttribute{
+				MarkdownDescription: "Username for authenticating to `proxy_url`. Requires `proxy_url`.\nCan be provided via `F5OS_PROXY_USERNAME` environment variable.",
+				Optional:            true,
+			},
+			"proxy_password": schema.StringAttribute{
+				MarkdownDescription: "Password for authenticating to `proxy_url`. Requires `proxy_username`.\nCan be provided via `F5OS_PROXY_PASSWORD` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"no_proxy": schema.StringAttribute{
+				MarkdownDescription: "Comma-separated hosts, domain suffixes, IP addresses and CIDR ranges reached without `proxy_url`, in `NO_PROXY` syntax. Requires `proxy_url`.\nCan be provided via `F5OS_NO_PROXY` environment variable.",
+				Optional:            true,
+			},
+			"session_cache_dir": schema.StringAttribute{
+				MarkdownDescription: "Directory in which the provider keeps its session token between Terraform runs, so a run reuses the token of an earlier one instead of logging in again. The token is reused until the device's token lifetime (see `token_lifetime` on `f5os_system`) runs out. Only the token is stored, encrypted with a key derived from the password; the password itself is never written. Unset means every run logs in. Does not apply with `auth_token` or the NETCONF transport.\nCan be provided via `F5OS_SESSION_CACHE_DIR` environment variable.",
+				Optional:            true,
+			},
 			"custom_headers": schema.MapAttribute{
 				MarkdownDescription: "Optional map of custom HTTP headers added to every F5OS API request. When an HTTPS proxy is in use, these headers are also sent in the CONNECT tunnel request.",
 				Optional:            true,
@@ -186,9 +493,24 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		return
 	}
 
+	// A provider block that takes its host or credentials from another
+	// resource is configured during plan with those values unknown. The
+	// session is deferred rather than rejected, so the plan can go ahead;
+	// only a resource that needs the device before apply fails.
+	if unknown := unknownConnectionAttributes(&config); len(unknown) > 0 {
+		tflog.Info(ctx, "Deferring F5OS client until the provider configuration is known", map[string]any{"unknown": unknown})
+		client := f5ossdk.NewDeferredSession(fmt.Errorf(
+			"the F5OS provider configuration is not known until apply (unknown: %s), so the device cannot be reached yet",
+			strings.Join(unknown, ", ")))
+		resp.DataSourceData = client
+		resp.ResourceData = client
+		return
+	}
+
 	host := os.Getenv("F5OS_HOST")
 	username := os.Getenv("F5OS_USERNAME")
 	password := os.Getenv("F5OS_PASSWORD")
+	authToken := os.Getenv("F5OS_TOKEN")
 	teemTmp := os.Getenv("TEEM_DISABLE")
 
 	hostPort := 8888
@@ -203,11 +525,32 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		teemDisable = true
 	}
 	disableSSL := true
+	disableSSLSet := false
 	if disableSSLtemp, ok := os.LookupEnv("DISABLE_TLS_VERIFY"); ok {
+		disableSSLSet = true
 		if disableSSLtemp == "false" {
 			disableSSL = false
 		}
 	}
+	caCertPEM := os.Getenv("F5OS_CA_CERT_PEM")
+	caCertFile := os.Getenv("F5OS_CA_CERT_FILE")
+	clientCert := os.Getenv("F5OS_CLIENT_CERT")
+	clientKey := os.Getenv("F5OS_CLIENT_KEY")
+	tlsServerName := os.Getenv("F5OS_TLS_SERVER_NAME")
+	httpTraceFile := os.Getenv("F5OS_HTTP_TRACE_FILE")
+	// Cassette recording is a tool for capturing test fixtures from a
+	// real device, so it is only exposed through the environment.
+	cassetteFile := os.Getenv("F5OS_RECORD_CASSETTE")
+	passwordCommand := strings.Fields(os.Getenv("F5OS_PASSWORD_COMMAND"))
+	transport := os.Getenv("F5OS_TRANSPORT")
+	netconfPort := f5ossdk.DefaultNetconfPort
+	if portEnv := os.Getenv("F5OS_NETCONF_PORT"); portEnv != "" {
+		if port, err := strconv.Atoi(portEnv); err == nil {
+			netconfPort = port
+		}
+	}
+	sshHostKey := os.Getenv("F5OS_SSH_HOST_KEY")
+	sessionCacheDir := os.Getenv("F5OS_SESSION_CACHE_DIR")
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
@@ -219,6 +562,18 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	if !config.Password.IsNull() {
 		password = config.Password.ValueString()
 	}
+	if !config.PasswordCommand.IsNull() && !config.PasswordCommand.IsUnknown() {
+		passwordCommand = nil
+		resp.Diagnostics.Append(config.PasswordCommand.ElementsAs(ctx, &passwordCommand, false)...)
+	}
+	if len(passwordCommand) > 0 && config.Password.IsNull() {
+		// The command replaces a password from F5OS_PASSWORD; one set
+		// in the configuration is rejected by the schema instead.
+		password = ""
+	}
+	if !config.AuthToken.IsNull() {
+		authToken = config.AuthToken.ValueString()
+	}
 	if !config.Port.IsNull() {
 		hostPort = int(config.Port.ValueInt64())
 	}
@@ -226,16 +581,101 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		teemDisable = config.TeemDisable.ValueBool()
 	}
 	if !config.DisableSslVerify.IsNull() {
+		disableSSLSet = true
 		disableSSL = config.DisableSslVerify.ValueBool()
 	}
-	// if !disableSSL && config.TrustedCertpath.IsNull() {
-	// 	resp.Diagnostics.AddError("trusted_cert_path is required when disable_tls_verify is set to false", "trusted_cert_path is required when disable_tls_verify is set to false")
-	// 	return
-	// }
-	// trustedCAPath := ""
-	// if !config.TrustedCertpath.IsNull() {
-	// 	trustedCAPath = config.TrustedCertpath.ValueString()
-	// }
+	if !config.CACertPEM.IsNull() {
+		caCertPEM = config.CACertPEM.ValueString()
+	}
+	if !config.CACertFile.IsNull() {
+		caCertFile = config.CACertFile.ValueString()
+	}
+	if !config.ClientCert.IsNull() {
+		clientCert = config.ClientCert.ValueString()
+	}
+	if !config.ClientKey.IsNull() {
+		clientKey = config.ClientKey.ValueString()
+	}
+	if !config.TLSServerName.IsNull() {
+		tlsServerName = config.TLSServerName.ValueString()
+	}
+	if !config.HTTPTraceFile.IsNull() {
+		httpTraceFile = config.HTTPTraceFile.ValueString()
+	}
+	if !config.Transport.IsNull() {
+		transport = config.Transport.ValueString()
+	}
+	if !config.NetconfPort.IsNull() {
+		netconfPort = int(config.NetconfPort.ValueInt64())
+	}
+	if !config.SSHHostKey.IsNull() {
+		sshHostKey = config.SSHHostKey.ValueString()
+	}
+	if !config.SessionCacheDir.IsNull() {
+		sessionCacheDir = config.SessionCacheDir.ValueString()
+	}
+	if caCertPEM != "" && caCertFile != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_file"),
+			"Conflicting CA certificate configuration",
+			"Only one of 'ca_cert_pem' (F5OS_CA_CERT_PEM) and 'ca_cert_file' "+
+				"(F5OS_CA_CERT_FILE) may be set.",
+		)
+	} else if caCertFile != "" {
+		caBytes, err := os.ReadFile(caCertFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Unable to read CA certificate file",
+				fmt.Sprintf("While configuring the provider, the CA bundle %q could not be read: %s", caCertFile, err),
+			)
+		}
+		caCertPEM = string(caBytes)
+	}
+	if (clientCert == "") != (clientKey == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Incomplete client certificate configuration",
+			"'client_cert' (F5OS_CLIENT_CERT) and 'client_key' (F5OS_CLIENT_KEY) "+
+				"must be provided together for mutual TLS.",
+		)
+	}
+	// Supplying a CA bundle is a request to verify the device; honor it
+	// unless verification was explicitly disabled.
+	if caCertPEM != "" && !disableSSLSet {
+		disableSSL = false
+	}
+	if caCertPEM != "" && disableSSL {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("disable_tls_verify"),
+			"CA certificate bundle is ignored",
+			"'disable_tls_verify' is true, so the device certificate is not "+
+				"verified and the configured CA bundle has no effect.",
+		)
+	}
+	var netconf *f5ossdk.NetconfConfig
+	switch transport {
+	case "", "restconf":
+	case "netconf":
+		if authToken != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auth_token"),
+				"Auth token not supported over NETCONF",
+				"The NETCONF transport logs in over SSH with 'username' and 'password'; "+
+					"'auth_token' (F5OS_TOKEN) can only be used with the RESTCONF transport.",
+			)
+		}
+		netconf = netconfConfig(netconfPort, sshHostKey, disableSSL, &resp.Diagnostics)
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("transport"),
+			"Invalid transport",
+			fmt.Sprintf("F5OS_TRANSPORT must be \"restconf\" or \"netconf\", got %q.", transport),
+		)
+	}
+	proxy := proxyFromConfig(&config, netconf != nil, &resp.Diagnostics)
+	retryPolicy := retryPolicyFromConfig(ctx, config.Retry, &resp.Diagnostics)
+	maxConcurrent, perSecond, serializeWrites := requestLimitsFromConfig(&config, &resp.Diagnostics)
 	if host == "" {
 		resp.Diagnostics.AddError(
 			"Missing 'host' in provider configuration",
@@ -244,23 +684,32 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 				"configuration block host attribute.",
 		)
 	}
-	if username == "" {
+	// A pre-issued token replaces the basic-auth login, so username and
+	// password are only required without one. The password is dropped
+	// so it is never held by a token session.
+	if authToken != "" {
+		password = ""
+	}
+	if username == "" && authToken == "" {
 		resp.Diagnostics.AddError(
 			"Missing 'username' in provider configuration",
 			"While configuring the provider, username was not found in "+
 				"the F5OS_USERNAME environment variable or provider "+
-				"configuration block 'username' attribute.",
+				"configuration block 'username' attribute, and no "+
+				"'auth_token' (F5OS_TOKEN) was provided.",
 		)
 	}
-	if password == "" {
+	if password == "" && authToken == "" && len(passwordCommand) == 0 {
 		resp.Diagnostics.AddError(
 			"Missing 'password' in provider configuration",
 			"While configuring the provider, 'password' was not found in "+
 				"the F5OS_PASSWORD environment variable or provider "+
-				"configuration block 'password' attribute.",
+				"configuration block 'password' attribute, and no "+
+				"'auth_token' (F5OS_TOKEN) or 'password_command' "+
+				"(F5OS_PASSWORD_COMMAND) was provided.",
 		)
 	}
-	// Bail out before touching NewSession if any required credential
+	// Bail out before creating the session if any required credential
 	// is missing. Continuing here would produce a confusing
 	// downstream connection error instead of the clear diagnostics
 	// we just recorded.
@@ -281,14 +730,52 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 		Host:             host,
 		User:             username,
 		Password:         password,
+		Token:            authToken,
+		Retry:            retryPolicy,
 		Port:             hostPort,
 		DisableSSLVerify: disableSSL,
 		CustomHeaders:    customHeaders,
-		// TrustedCACertificate: trustedCAPath,
+		Proxy:            proxy,
+		CACertPEM:        caCertPEM,
+		ClientCertPEM:    clientCert,
+		ClientKeyPEM:     clientKey,
+		TLSServerName:    tlsServerName,
+		HTTPTraceFile:    httpTraceFile,
+		CassetteFile:     cassetteFile,
+		Netconf:          netconf,
+		Logger:           newClientLogger(ctx),
+	}
+	f5osConfig.MaxConcurrentRequests, f5osConfig.RequestsPerSecond, f5osConfig.SerializeWrites = maxConcurrent, perSecond, serializeWrites
+	apiTimeout, pollInterval, maxWait := timingFromConfig(&config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if apiTimeout > 0 {
+		f5osConfig.ConfigOptions = &f5ossdk.ConfigOptions{APICallTimeout: apiTimeout}
+	}
+	f5osConfig.PollInterval, f5osConfig.MaxWait = pollInterval, maxWait
+	if len(passwordCommand) > 0 && authToken == "" {
+		f5osConfig.Credentials = passwordCommandCredentials(username, passwordCommand)
 	}
-	// Reuse an existing session if we've already authenticated to this
+	if sessionCacheDir != "" {
+		if authToken != "" || netconf != nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("session_cache_dir"),
+				"Session cache directory is ignored",
+				"Only sessions that log in with a password keep their token between runs; "+
+					"with 'auth_token' or the NETCONF transport 'session_cache_dir' has no effect.",
+			)
+		} else {
+			f5osConfig.TokenCache = &f5ossdk.FileTokenCache{
+				Path: tokenCacheFile(sessionCacheDir, f5osConfig, passwordCommand, sshHostKey),
+			}
+		}
+	}
+	// Reuse an existing session if we've already set one up for this
 	// endpoint with these credentials in the current process. See the
-	// comment on sessionCache for the motivation.
+	// comment on sessionCache for the motivation. Sessions log in on
+	// their first request rather than here, so plan works without the
+	// device until a resource has to read it.
 	//
 	// Skip the cache for unit tests: testAccPreUnitCheck sets
 	// F5OS_HOST to the httptest server's URL (e.g.
@@ -312,42 +799,49 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	}
 	var client *f5ossdk.F5os
 	if cacheable {
-		cacheKey := sessionCacheKey(host, hostPort, username, password, disableSSL, customHeaders)
+		cacheKey := sessionCacheKey(f5osConfig, passwordCommand, sshHostKey)
 		sessionCacheMu.Lock()
 		client = sessionCache[cacheKey]
 		sessionCacheMu.Unlock()
 		if client == nil {
 			var err error
-			client, err = f5ossdk.NewSession(f5osConfig)
+			client, err = f5ossdk.NewLazySession(f5osConfig)
 			if err != nil {
-				resp.Diagnostics.AddError(fmt.Sprintf("%+v", err.Error()), "")
+				addSessionErrorDiagnostic(&resp.Diagnostics, err)
 				return
 			}
 			sessionCacheMu.Lock()
 			// Double-check in case a concurrent Configure raced us.
 			// Prefer the value already in the cache so all callers
 			// share one session and the loser's client becomes garbage.
+			loser := client
 			if existing, ok := sessionCache[cacheKey]; ok {
 				client = existing
 			} else {
 				sessionCache[cacheKey] = client
+				loser = nil
 			}
 			sessionCacheMu.Unlock()
+			if loser != nil {
+				// Free the device session, should the loser have opened one.
+				_ = loser.WithContext(ctx).Close()
+			}
 		}
 	} else {
 		var err error
-		client, err = f5ossdk.NewSession(f5osConfig)
+		client, err = f5ossdk.NewLazySession(f5osConfig)
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("%+v", err.Error()), "")
+			addSessionErrorDiagnostic(&resp.Diagnostics, err)
 			return
 		}
+		sessionCacheMu.Lock()
+		uncachedSessions = append(uncachedSessions, client)
+		sessionCacheMu.Unlock()
 	}
 	client.Teem = teemDisable
 	teemData.TerraformVersion = req.TerraformVersion
 	teemData.ProviderName = "f5os"
 	teemData.ProviderVersion = p.version
-	teemData.F5Platform = fmt.Sprintf("F5OS %s", client.PlatformType)
-	teemData.F5SoftwareVersion = client.PlatformVersion
 	teemData.TerraformLicense = "open"
 	if isTerraformVersionAtLeast(req.TerraformVersion, 1, 5, 0) {
 		teemData.TerraformLicense = "business"
@@ -358,7 +852,7 @@ func (p *F5osProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 }
 
 func (p *F5osProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+	return withRequestIDs([]func() resource.Resource{
 		NewTenantImageResource,
 		NewTenantResource,
 		NewPartitionResource,
@@ -379,14 +873,19 @@ func (p *F5osProvider) Resources(ctx context.Context) []func() resource.Resource
 		NewQkviewResource,
 		NewSnmpResource,
 		NewAuthResource,
-	}
+		NewRestconfResource,
+		NewRpcResource,
+	})
 }
 
 func (p *F5osProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{
+	return withDataSourceRequestIDs([]func() datasource.DataSource{
 		NewImageInfoDataSource,
 		NewDeviceInfoDataSource,
-	}
+		NewRestconfDataSource,
+		NewTenantCapacityDataSource,
+		NewTenantsDataSource,
+	})
 }
 
 func New(version string) func() provider.Provider {
@@ -397,6 +896,309 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// requestLimitsFromConfig resolves the request limiting settings from the
+// environment and the provider configuration, which takes precedence.
+// Zero values mean no limit.
+func requestLimitsFromConfig(config *F5osProviderModel, diags *diag.Diagnostics) (int, float64, bool) {
+	maxConcurrent := 0
+	if v := os.Getenv("F5OS_MAX_CONCURRENT_REQUESTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			diags.AddAttributeError(
+				path.Root("max_concurrent_requests"),
+				"Invalid F5OS_MAX_CONCURRENT_REQUESTS",
+				fmt.Sprintf("F5OS_MAX_CONCURRENT_REQUESTS must be a whole number of at least 1, got %q.", v),
+			)
+		}
+		maxConcurrent = n
+	}
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrent = int(config.MaxConcurrentRequests.ValueInt64())
+	}
+
+	perSecond := 0.0
+	if v := os.Getenv("F5OS_REQUESTS_PER_SECOND"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			diags.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid F5OS_REQUESTS_PER_SECOND",
+				fmt.Sprintf("F5OS_REQUESTS_PER_SECOND must be a number greater than 0, got %q.", v),
+			)
+		}
+		perSecond = f
+	}
+	if !config.RequestsPerSecond.IsNull() {
+		perSecond = config.RequestsPerSecond.ValueFloat64()
+		if perSecond <= 0 {
+			diags.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid requests_per_second",
+				fmt.Sprintf("'requests_per_second' must be greater than 0, got %g.", perSecond),
+			)
+		}
+	}
+
+	serializeWrites := os.Getenv("F5OS_SERIALIZE_WRITES") == "true"
+	if !config.SerializeWrites.IsNull() {
+		serializeWrites = config.SerializeWrites.ValueBool()
+	}
+	return maxConcurrent, perSecond, serializeWrites
+}
+
+// timingFromConfig resolves the API call timeout, poll interval and
+// default wait limit from the environment and the provider
+// configuration, which takes precedence. Zero values keep the defaults.
+func timingFromConfig(config *F5osProviderModel, diags *diag.Diagnostics) (time.Duration, time.Duration, time.Duration) {
+	var out [3]time.Duration
+	for i, setting := range []struct {
+		attr  string
+		env   string
+		value types.String
+	}{
+		{"api_timeout", "F5OS_API_TIMEOUT", config.APITimeout},
+		{"poll_interval", "F5OS_POLL_INTERVAL", config.PollInterval},
+		{"max_wait", "F5OS_MAX_WAIT", config.MaxWait},
+	} {
+		if v := os.Getenv(setting.env); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				diags.AddAttributeError(
+					path.Root(setting.attr),
+					"Invalid "+setting.env,
+					fmt.Sprintf("%s must be a positive duration such as '30s' or '5m', got %q.", setting.env, v),
+				)
+			}
+			out[i] = d
+		}
+		// The value is checked by durationValidator.
+		if d, err := time.ParseDuration(setting.value.ValueString()); err == nil {
+			out[i] = d
+		}
+	}
+	return out[0], out[1], out[2]
+}
+
+// retryPolicyFromConfig converts the provider `retry` block into a client
+// retry policy. It returns nil when the block is not configured so the
+// client keeps its defaults.
+func retryPolicyFromConfig(ctx context.Context, obj types.Object, diags *diag.Diagnostics) *f5ossdk.RetryPolicy {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil
+	}
+	var m retryConfigModel
+	diags.Append(obj.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil
+	}
+	policy := &f5ossdk.RetryPolicy{}
+	if !m.MaxAttempts.IsNull() {
+		policy.MaxAttempts = int(m.MaxAttempts.ValueInt64())
+	}
+	// Durations are checked by durationValidator; parse errors here can
+	// only come from unknown values and leave the default in place.
+	if d, err := time.ParseDuration(m.InitialBackoff.ValueString()); err == nil {
+		policy.InitialBackoff = d
+	}
+	if d, err := time.ParseDuration(m.MaxBackoff.ValueString()); err == nil {
+		policy.MaxBackoff = d
+	}
+	if policy.InitialBackoff > 0 && policy.MaxBackoff > 0 && policy.MaxBackoff < policy.InitialBackoff {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("max_backoff"),
+			"Invalid retry configuration",
+			fmt.Sprintf("'max_backoff' (%s) must not be shorter than 'initial_backoff' (%s).", policy.MaxBackoff, policy.InitialBackoff),
+		)
+	}
+	if !m.Jitter.IsNull() {
+		policy.Jitter = m.Jitter.ValueFloat64()
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("jitter"),
+				"Invalid retry configuration",
+				fmt.Sprintf("'jitter' must be between 0 and 1, got %v.", policy.Jitter),
+			)
+		}
+	}
+	if !m.RetryableStatusCodes.IsNull() {
+		var codes []int64
+		diags.Append(m.RetryableStatusCodes.ElementsAs(ctx, &codes, false)...)
+		for _, c := range codes {
+			policy.RetryableStatusCodes = append(policy.RetryableStatusCodes, int(c))
+		}
+	}
+	if !m.RetryableErrors.IsNull() {
+		diags.Append(m.RetryableErrors.ElementsAs(ctx, &policy.RetryableErrors, false)...)
+	}
+	return policy
+}
+
+// netconfConfig returns the NETCONF settings of the provider. The device's
+// SSH host key is pinned by sshHostKey or else looked up in the user's
+// known_hosts file, unless verification is disabled.
+func netconfConfig(port int, sshHostKey string, disableVerify bool, diags *diag.Diagnostics) *f5ossdk.NetconfConfig {
+	cfg := &f5ossdk.NetconfConfig{Port: port}
+	switch {
+	case sshHostKey != "":
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(sshHostKey))
+		if err != nil {
+			diags.AddAttributeError(path.Root("ssh_host_key"), "Invalid SSH host key",
+				fmt.Sprintf("'ssh_host_key' (F5OS_SSH_HOST_KEY) must be a public key in authorized_keys format: %s", err))
+			return nil
+		}
+		cfg.HostKeyCallback = ssh.FixedHostKey(key)
+	case disableVerify:
+		cfg.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		home, err := os.UserHomeDir()
+		var callback ssh.HostKeyCallback
+		if err == nil {
+			callback, err = knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		}
+		if err != nil {
+			diags.AddAttributeError(path.Root("ssh_host_key"), "Unable to verify the device's SSH host key",
+				fmt.Sprintf("'disable_tls_verify' is false and no 'ssh_host_key' (F5OS_SSH_HOST_KEY) is set, "+
+					"but the known_hosts file could not be read: %s", err))
+			return nil
+		}
+		cfg.HostKeyCallback = callback
+	}
+	return cfg
+}
+
+// proxyFromConfig resolves the proxy settings from the environment and the
+// provider configuration, which takes precedence. It returns nil when no
+// proxy_url is set, so the session keeps honoring HTTPS_PROXY, HTTP_PROXY
+// and NO_PROXY.
+func proxyFromConfig(config *F5osProviderModel, netconf bool, diags *diag.Diagnostics) *f5ossdk.ProxyConfig {
+	proxy := &f5ossdk.ProxyConfig{
+		URL:      os.Getenv("F5OS_PROXY_URL"),
+		Username: os.Getenv("F5OS_PROXY_USERNAME"),
+		Password: os.Getenv("F5OS_PROXY_PASSWORD"),
+		NoProxy:  os.Getenv("F5OS_NO_PROXY"),
+	}
+	if !config.ProxyURL.IsNull() {
+		proxy.URL = config.ProxyURL.ValueString()
+	}
+	if !config.ProxyUsername.IsNull() {
+		proxy.Username = config.ProxyUsername.ValueString()
+	}
+	if !config.ProxyPassword.IsNull() {
+		proxy.Password = config.ProxyPassword.ValueString()
+	}
+	if !config.NoProxy.IsNull() {
+		proxy.NoProxy = config.NoProxy.ValueString()
+	}
+	if proxy.URL == "" {
+		if proxy.Username != "" || proxy.Password != "" || proxy.NoProxy != "" {
+			diags.AddAttributeError(
+				path.Root("proxy_url"),
+				"Missing 'proxy_url' in provider configuration",
+				"'proxy_username', 'proxy_password' and 'no_proxy' only apply to "+
+					"the proxy set by 'proxy_url' (F5OS_PROXY_URL).",
+			)
+		}
+		return nil
+	}
+	if proxy.Password != "" && proxy.Username == "" {
+		diags.AddAttributeError(
+			path.Root("proxy_username"),
+			"Incomplete proxy credentials",
+			"'proxy_password' (F5OS_PROXY_PASSWORD) requires 'proxy_username' (F5OS_PROXY_USERNAME).",
+		)
+	}
+	if err := proxy.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("proxy_url"), "Invalid proxy URL",
+			fmt.Sprintf("'proxy_url' (F5OS_PROXY_URL) is not usable: %s.", err))
+	}
+	if netconf {
+		diags.AddAttributeWarning(
+			path.Root("proxy_url"),
+			"Proxy is ignored",
+			"The NETCONF transport connects to the device over SSH directly, "+
+				"so 'proxy_url' has no effect.",
+		)
+	}
+	return proxy
+}
+
+// unknownConnectionAttributes returns the names of the provider attributes
+// used to reach and log in to the device whose values are not yet known.
+func unknownConnectionAttributes(config *F5osProviderModel) []string {
+	var unknown []string
+	for _, a := range []struct {
+		name  string
+		value attr.Value
+	}{
+		{"host", config.Host},
+		{"port", config.Port},
+		{"username", config.Username},
+		{"password", config.Password},
+		{"password_command", config.PasswordCommand},
+		{"auth_token", config.AuthToken},
+		{"ca_cert_pem", config.CACertPEM},
+		{"ca_cert_file", config.CACertFile},
+		{"client_cert", config.ClientCert},
+		{"client_key", config.ClientKey},
+		{"tls_server_name", config.TLSServerName},
+		{"transport", config.Transport},
+		{"netconf_port", config.NetconfPort},
+		{"ssh_host_key", config.SSHHostKey},
+		{"proxy_url", config.ProxyURL},
+		{"proxy_username", config.ProxyUsername},
+		{"proxy_password", config.ProxyPassword},
+		{"no_proxy", config.NoProxy},
+		{"session_cache_dir", config.SessionCacheDir},
+	} {
+		if a.value.IsUnknown() {
+			unknown = append(unknown, a.name)
+		}
+	}
+	return unknown
+}
+
+// addSessionErrorDiagnostic records a failure to set up or log in to the
+// device session. Certificate verification failures and rejected auth
+// tokens get a dedicated summary and a hint pointing at the provider
+// attribute that fixes them; anything else keeps the historical shape of
+// the raw error as the summary.
+func addSessionErrorDiagnostic(diags *diag.Diagnostics, err error) {
+	if summary, detail, ok := sessionErrorDiagnostic(err); ok {
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddError(fmt.Sprintf("%+v", err.Error()), "")
+}
+
+// sessionErrorDiagnostic returns the summary and detail for a login
+// failure that the provider configuration can fix, or false for any
+// other error. Sessions log in on their first request, so these failures
+// surface from resource operations rather than from Configure.
+func sessionErrorDiagnostic(err error) (string, string, bool) {
+	if errors.Is(err, f5ossdk.ErrTokenExpired) {
+		return "F5OS auth token expired",
+			fmt.Sprintf("%s\n\nThe device rejected the configured 'auth_token' (F5OS_TOKEN). "+
+				"Obtain a new token and update the provider configuration.", err),
+			true
+	}
+	var tlsErr *f5ossdk.TLSVerifyError
+	if !errors.As(err, &tlsErr) {
+		return "", "", false
+	}
+	var hint string
+	switch tlsErr.Reason {
+	case f5ossdk.TLSVerifyUnknownAuthority:
+		hint = "Provide the CA that issued the device certificate with 'ca_cert_pem' or 'ca_cert_file'."
+	case f5ossdk.TLSVerifyHostname:
+		hint = "Set 'tls_server_name' to a name present in the device certificate's subject alternative names, or connect using that name as 'host'."
+	case f5ossdk.TLSVerifyClientRejected:
+		hint = "Check that 'client_cert' is issued by a CA the device trusts for client authentication and that 'client_key' matches it."
+	default:
+		hint = "Check that the device certificate is valid and not expired."
+	}
+	return "F5OS device TLS verification failed", fmt.Sprintf("%s\n\n%s", tlsErr.Error(), hint), true
+}
+
 // toProvider can be used to cast a generic provider.Provider reference to this specific provider.
 // This is ideally used in DataSourceType.NewDataSource and ResourceType.NewResource calls.
 func toF5osProvider(in any) (*f5ossdk.F5os, diag.Diagnostics) {
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,9 +11,15 @@ import (
 	"testing"
 	"time"
 
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
 )
 
 const (
@@ -217,8 +224,8 @@ func testAccPreCheckPlatformRSeries(t *testing.T) {
 	}
 	// PlatformType for rSeries is the model name (e.g. "r5900", "r12800-DS").
 	// Skip if the device is a VELOS partition or controller.
-	if client.PlatformType == "Velos Partition" || client.PlatformType == "Velos Controller" {
-		t.Skipf("skipping: test requires rSeries but device is %q", client.PlatformType)
+	if client.Platform() == "Velos Partition" || client.Platform() == "Velos Controller" {
+		t.Skipf("skipping: test requires rSeries but device is %q", client.Platform())
 	}
 }
 
@@ -245,6 +252,258 @@ func setupMockPlatformVersion(m *http.ServeMux, version string) {
 	})
 }
 
+// testProviderConfigure invokes F5osProvider.Configure directly, without
+// the Terraform CLI, using the given provider attribute values. Attributes
+// not present in attrs are passed as null, matching an omitted argument in
+// the provider block.
+func testProviderConfigure(t *testing.T, attrs map[string]tftypes.Value) *provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+	p := New("test")()
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	vals := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+	for name, typ := range objType.AttributeTypes {
+		if v, ok := attrs[name]; ok {
+			vals[name] = v
+			continue
+		}
+		vals[name] = tftypes.NewValue(typ, nil)
+	}
+	req := provider.ConfigureRequest{
+		TerraformVersion: "1.5.0",
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objType, vals),
+		},
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, req, resp)
+	return resp
+}
+
+// testProtoCreate plans and applies the creation of a typeName resource
+// from config through the provider's protocol server, configured with
+// providerAttrs, the way Terraform would, so that schema defaults and plan
+// modifiers apply. Attributes missing from either map are null. It returns
+// the new state, or the diagnostics of the step that failed.
+func testProtoCreate(t *testing.T, providerAttrs map[string]tftypes.Value, typeName string, config map[string]tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	_, state, diags := testProtoApply(t, providerAttrs, typeName, tftypes.Value{}, config)
+	return state, diags
+}
+
+// testProtoApply is testProtoCreate for a resource whose prior state is
+// prior, or that is created when prior is the zero Value. Like Terraform,
+// it proposes the prior value of computed attributes left out of config.
+// It returns the planned and the new state.
+func testProtoApply(t *testing.T, providerAttrs map[string]tftypes.Value, typeName string, prior tftypes.Value, config map[string]tftypes.Value) (tftypes.Value, tftypes.Value, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("NewProtocol6WithError failed: %v", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil || len(schemas.Diagnostics) > 0 {
+		t.Fatalf("GetProviderSchema failed: %v %v", err, schemas.Diagnostics)
+	}
+	object := func(typ tftypes.Type, attrs map[string]tftypes.Value) tftypes.Value {
+		objType := typ.(tftypes.Object)
+		vals := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+		for name, attrType := range objType.AttributeTypes {
+			if v, ok := attrs[name]; ok {
+				vals[name] = v
+				continue
+			}
+			vals[name] = tftypes.NewValue(attrType, nil)
+		}
+		return tftypes.NewValue(typ, vals)
+	}
+	dynamic := func(typ tftypes.Type, v tftypes.Value) *tfprotov6.DynamicValue {
+		dv, err := tfprotov6.NewDynamicValue(typ, v)
+		if err != nil {
+			t.Fatalf("NewDynamicValue failed: %v", err)
+		}
+		return &dv
+	}
+
+	providerType := schemas.Provider.ValueType()
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.5.0",
+		Config:           dynamic(providerType, object(providerType, providerAttrs)),
+	})
+	if err != nil {
+		t.Fatalf("ConfigureProvider failed: %v", err)
+	}
+	if len(configured.Diagnostics) > 0 {
+		return tftypes.Value{}, tftypes.Value{}, configured.Diagnostics
+	}
+
+	resourceSchema := schemas.ResourceSchemas[typeName]
+	resourceType := resourceSchema.ValueType()
+	if prior.Type() == nil {
+		prior = tftypes.NewValue(resourceType, nil)
+	}
+	proposed := map[string]tftypes.Value{}
+	for name, v := range config {
+		proposed[name] = v
+	}
+	if !prior.IsNull() {
+		var priorAttrs map[string]tftypes.Value
+		if err := prior.As(&priorAttrs); err != nil {
+			t.Fatalf("reading the prior state failed: %v", err)
+		}
+		for _, a := range resourceSchema.Block.Attributes {
+			if _, ok := config[a.Name]; !ok && a.Computed {
+				proposed[a.Name] = priorAttrs[a.Name]
+			}
+		}
+	}
+	cfg := dynamic(resourceType, object(resourceType, config))
+	priorState := dynamic(resourceType, prior)
+	planned, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       priorState,
+		ProposedNewState: dynamic(resourceType, object(resourceType, proposed)),
+		Config:           cfg,
+	})
+	if err != nil {
+		t.Fatalf("PlanResourceChange failed: %v", err)
+	}
+	if len(planned.Diagnostics) > 0 {
+		return tftypes.Value{}, tftypes.Value{}, planned.Diagnostics
+	}
+	plan, err := planned.PlannedState.Unmarshal(resourceType)
+	if err != nil {
+		t.Fatalf("Unmarshal of the planned state failed: %v", err)
+	}
+	applied, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   priorState,
+		PlannedState: planned.PlannedState,
+		Config:       cfg,
+	})
+	if err != nil {
+		t.Fatalf("ApplyResourceChange failed: %v", err)
+	}
+	if len(applied.Diagnostics) > 0 {
+		return plan, tftypes.Value{}, applied.Diagnostics
+	}
+	state, err := applied.NewState.Unmarshal(resourceType)
+	if err != nil {
+		t.Fatalf("Unmarshal of the new state failed: %v", err)
+	}
+	return plan, state, nil
+}
+
+// testProviderConnect is testProviderConfigure followed by the login that
+// the first resource operation would trigger; a login failure is recorded
+// in the response diagnostics as a resource would record it.
+func testProviderConnect(t *testing.T, attrs map[string]tftypes.Value) *provider.ConfigureResponse {
+	t.Helper()
+	resp := testProviderConfigure(t, attrs)
+	if resp.Diagnostics.HasError() {
+		return resp
+	}
+	if err := resp.ResourceData.(*f5ossdk.F5os).Connect(); err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err, "Client Error", err.Error())
+	}
+	return resp
+}
+
+// testEmulatorClient starts a device emulated with opts and returns the
+// client the provider, configured for it, hands to resources and data
+// sources.
+func testEmulatorClient(t *testing.T, opts f5osemu.Options) (*f5ossdk.F5os, *f5osemu.Server) {
+	t.Helper()
+	server, err := f5osemu.NewServer(opts)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(server.Close)
+	resp := testProviderConfigure(t, map[string]tftypes.Value{
+		"host":     tftypes.NewValue(tftypes.String, server.URL),
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"password": tftypes.NewValue(tftypes.String, "admin"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.ResourceData.(*f5ossdk.F5os), server
+}
+
+// testResourceState returns model as a state, plan or configuration of
+// r's schema.
+func testResourceState(t *testing.T, r fwresource.Resource, model any) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("state.Set returned diagnostics: %v", diags)
+	}
+	return state
+}
+
+// testResourceCreate runs Create of r for the plan model and, when it
+// succeeds, reads the new state into got.
+func testResourceCreate(t *testing.T, r fwresource.Resource, model, got any) *fwresource.CreateResponse {
+	t.Helper()
+	plan := testResourceState(t, r, model)
+	resp := &fwresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}}
+	r.Create(context.Background(), fwresource.CreateRequest{Plan: tfsdk.Plan(plan)}, resp)
+	testStateGet(t, resp.Diagnostics.HasError(), resp.State, got)
+	return resp
+}
+
+// testResourceRead runs Read of r for state and, when it succeeds and the
+// resource still exists, reads the refreshed state into got.
+func testResourceRead(t *testing.T, r fwresource.Resource, state tfsdk.State, got any) *fwresource.ReadResponse {
+	t.Helper()
+	resp := &fwresource.ReadResponse{State: state}
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, resp)
+	testStateGet(t, resp.Diagnostics.HasError(), resp.State, got)
+	return resp
+}
+
+// testDataSourceRead runs Read of d for the configuration model and, when
+// it succeeds, reads the resulting state into got.
+func testDataSourceRead(t *testing.T, d fwdatasource.DataSource, model, got any) *fwdatasource.ReadResponse {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwdatasource.SchemaResponse{}
+	d.Schema(ctx, fwdatasource.SchemaRequest{}, schemaResp)
+	config := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := config.Set(ctx, model); diags.HasError() {
+		t.Fatalf("config.Set returned diagnostics: %v", diags)
+	}
+	resp := &fwdatasource.ReadResponse{State: config}
+	d.Read(ctx, fwdatasource.ReadRequest{Config: tfsdk.Config(config)}, resp)
+	testStateGet(t, resp.Diagnostics.HasError(), resp.State, got)
+	return resp
+}
+
+// testStateGet reads state into got, unless got is nil, the operation
+// that produced state failed or state is null.
+func testStateGet(t *testing.T, failed bool, state tfsdk.State, got any) {
+	t.Helper()
+	if got == nil || failed || state.Raw.IsNull() {
+		return
+	}
+	if diags := state.Get(context.Background(), got); diags.HasError() {
+		t.Fatalf("failed to read back state: %v", diags)
+	}
+}
+
 // TestSessionCacheConcurrencyDedupe exercises the session-cache
 // "double-check" pattern used in Configure by simulating concurrent
 // callers that attempt to get-or-create a session for the same cache
@@ -267,7 +526,14 @@ func TestSessionCacheConcurrencyDedupe(t *testing.T) {
 	disableSSL := false
 	headers := map[string]string{}
 
-	cacheKey := sessionCacheKey(host, port, user, password, disableSSL, headers)
+	cacheKey := sessionCacheKey(&f5ossdk.F5osConfig{
+		Host:             host,
+		Port:             port,
+		User:             user,
+		Password:         password,
+		DisableSSLVerify: disableSSL,
+		CustomHeaders:    headers,
+	}, nil, "")
 
 	results := make(chan *f5ossdk.F5os, goroutines)
 	var wg sync.WaitGroup
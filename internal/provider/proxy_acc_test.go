@@ -198,7 +198,7 @@ func TestAccProxyConnectivity(t *testing.T) {
 
 	t.Logf("Successfully connected to F5OS device through proxy")
 	t.Logf("  Host: %s", client.Host)
-	t.Logf("  Platform: %s", client.PlatformType)
+	t.Logf("  Platform: %s", client.Platform())
 	t.Logf("  Token obtained: yes (length=%d)", len(client.Token))
 
 	// Verify transport proxy is configured
@@ -389,5 +389,5 @@ func TestAccNoProxyBypass(t *testing.T) {
 
 	t.Logf("Successfully connected with NO_PROXY set")
 	t.Logf("  Host: %s", client.Host)
-	t.Logf("  Platform: %s", client.PlatformType)
+	t.Logf("  Platform: %s", client.Platform())
 }
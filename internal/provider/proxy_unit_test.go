@@ -2,6 +2,7 @@ package provider
 
 import (
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net"
@@ -12,6 +13,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
 )
 
@@ -677,3 +680,368 @@ func TestNewSession_ReauthPreservesCustomHeaders(t *testing.T) {
 			reconstructedCfg.CustomHeaders["X-Tenant-ID"])
 	}
 }
+
+// =========================================================================
+// Section 5: Explicit proxy settings
+// These verify that F5osConfig.Proxy routes a session through its own
+// HTTP or SOCKS5 proxy, independently of the environment and of other
+// sessions, and that the provider attributes reach it.
+// =========================================================================
+
+// proxiedDevice is the host name sessions in this section use for the
+// device. It does not resolve, so a request only reaches the backend if
+// the proxy connects it; loopback addresses would bypass the proxy.
+const proxiedDevice = "f5os-device.test"
+
+// newProxiedSession creates a session to the device behind backend,
+// addressed as proxiedDevice, through proxy.
+func newProxiedSession(t *testing.T, scheme string, backend *httptest.Server, proxy *f5os.ProxyConfig, headers map[string]string) (*f5os.F5os, error) {
+	t.Helper()
+	_, port, _ := net.SplitHostPort(backend.Listener.Addr().String())
+	return f5os.NewSession(&f5os.F5osConfig{
+		Host:             fmt.Sprintf("%s://%s:%s", scheme, proxiedDevice, port),
+		User:             "admin",
+		Password:         "admin",
+		DisableSSLVerify: true,
+		CustomHeaders:    headers,
+		Proxy:            proxy,
+	})
+}
+
+// proxyRecorder is an HTTP proxy that sends every request for
+// proxiedDevice to backend, tunnelling CONNECT requests, and records the
+// headers it received.
+type proxyRecorder struct {
+	*httptest.Server
+	mu      sync.Mutex
+	methods []string
+	headers []http.Header
+}
+
+func newProxyRecorder(backend *httptest.Server) *proxyRecorder {
+	p := &proxyRecorder{}
+	backendAddr := backend.Listener.Addr().String()
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.methods = append(p.methods, r.Method)
+		p.headers = append(p.headers, r.Header.Clone())
+		p.mu.Unlock()
+		if r.Method == http.MethodConnect {
+			clientConn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			defer clientConn.Close()
+			targetConn, err := net.Dial("tcp", backendAddr)
+			if err != nil {
+				return
+			}
+			defer targetConn.Close()
+			_, _ = fmt.Fprint(clientConn, "HTTP/1.1 200 Connection Established\r\n\r\n")
+			pipe(clientConn, targetConn)
+			return
+		}
+		outURL := *r.URL
+		outURL.Host = backendAddr
+		out, err := http.NewRequest(r.Method, outURL.String(), r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		out.Header = r.Header.Clone()
+		out.Header.Del("Proxy-Authorization")
+		resp, err := http.DefaultTransport.RoundTrip(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	return p
+}
+
+func (p *proxyRecorder) requests() ([]string, []http.Header) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.methods...), append([]http.Header(nil), p.headers...)
+}
+
+// pipe copies between a and b until either side closes.
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() { _, _ = io.Copy(a, b); done <- struct{}{} }()
+	go func() { _, _ = io.Copy(b, a); done <- struct{}{} }()
+	<-done
+}
+
+// socks5Recorder is a SOCKS5 proxy requiring username/password auth that
+// connects every request to backend and records the credentials and
+// addresses it was given.
+type socks5Recorder struct {
+	net.Listener
+	mu          sync.Mutex
+	credentials []string
+	targets     []string
+}
+
+func newSOCKS5Recorder(t *testing.T, backend *httptest.Server) *socks5Recorder {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	s := &socks5Recorder{Listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, backend.Listener.Addr().String())
+		}
+	}()
+	return s
+}
+
+func (s *socks5Recorder) serve(conn net.Conn, backendAddr string) {
+	defer conn.Close()
+	read := func(n int) []byte {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return nil
+		}
+		return buf
+	}
+	// Greeting: offer username/password auth (RFC 1928, RFC 1929).
+	hdr := read(2)
+	if hdr == nil || hdr[0] != 5 || read(int(hdr[1])) == nil {
+		return
+	}
+	_, _ = conn.Write([]byte{5, 2})
+	auth := read(2)
+	if auth == nil || auth[0] != 1 {
+		return
+	}
+	user := read(int(auth[1]))
+	plen := read(1)
+	if user == nil || plen == nil {
+		return
+	}
+	pass := read(int(plen[0]))
+	s.mu.Lock()
+	s.credentials = append(s.credentials, string(user)+":"+string(pass))
+	s.mu.Unlock()
+	_, _ = conn.Write([]byte{1, 0})
+	// CONNECT request.
+	req := read(4)
+	if req == nil || req[1] != 1 {
+		return
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		host = net.IP(read(4)).String()
+	case 3:
+		host = string(read(int(read(1)[0])))
+	case 4:
+		host = net.IP(read(16)).String()
+	}
+	port := read(2)
+	s.mu.Lock()
+	s.targets = append(s.targets, net.JoinHostPort(host, fmt.Sprint(int(port[0])<<8|int(port[1]))))
+	s.mu.Unlock()
+	target, err := net.Dial("tcp", backendAddr)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	pipe(conn, target)
+}
+
+func (s *socks5Recorder) recorded() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.credentials...), append([]string(nil), s.targets...)
+}
+
+// TestExplicitProxy_HTTPWithCredentials verifies that a session sends its
+// requests through Proxy.URL with Basic proxy credentials.
+func TestExplicitProxy_HTTPWithCredentials(t *testing.T) {
+	backend := newMockBackend()
+	defer backend.Close()
+	proxy := newProxyRecorder(backend)
+	defer proxy.Close()
+
+	session, err := newProxiedSession(t, "http", backend, &f5os.ProxyConfig{URL: proxy.URL, Username: "jump", Password: "s3cret"}, nil)
+	if err != nil {
+		t.Fatalf("NewSession through proxy failed: %v", err)
+	}
+	if session.Token != "test-token" {
+		t.Fatalf("expected the login to succeed through the proxy, got token %q", session.Token)
+	}
+	methods, headers := proxy.requests()
+	if len(methods) == 0 {
+		t.Fatal("expected the login to go through the proxy")
+	}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("jump:s3cret"))
+	if got := headers[0].Get("Proxy-Authorization"); got != want {
+		t.Fatalf("expected Proxy-Authorization %q, got %q", want, got)
+	}
+}
+
+// TestExplicitProxy_SOCKS5 verifies that a socks5 Proxy.URL tunnels the
+// session's requests, authenticating with the proxy credentials and
+// leaving name resolution to the proxy.
+func TestExplicitProxy_SOCKS5(t *testing.T) {
+	backend := newMockBackend()
+	defer backend.Close()
+	socks := newSOCKS5Recorder(t, backend)
+	defer socks.Close()
+
+	session, err := newProxiedSession(t, "http", backend, &f5os.ProxyConfig{
+		URL:      "socks5://" + socks.Addr().String(),
+		Username: "jump",
+		Password: "s3cret",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewSession through SOCKS5 proxy failed: %v", err)
+	}
+	if session.Token != "test-token" {
+		t.Fatalf("expected the login to succeed through the proxy, got token %q", session.Token)
+	}
+	credentials, targets := socks.recorded()
+	if len(credentials) == 0 || credentials[0] != "jump:s3cret" {
+		t.Fatalf("expected SOCKS5 credentials jump:s3cret, got %v", credentials)
+	}
+	if !strings.HasPrefix(targets[0], proxiedDevice+":") {
+		t.Fatalf("expected the proxy to be asked for %s, got %v", proxiedDevice, targets)
+	}
+}
+
+// TestExplicitProxy_CONNECTKeepsCustomHeaders verifies that CustomHeaders
+// are still sent in the CONNECT request to an explicit HTTPS-capable
+// proxy, together with the proxy credentials.
+func TestExplicitProxy_CONNECTKeepsCustomHeaders(t *testing.T) {
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth-Token", "test-token")
+		_, _ = w.Write([]byte(`{"openconfig-system:aaa":{}}`))
+	}))
+	defer backend.Close()
+	proxy := newProxyRecorder(backend)
+	defer proxy.Close()
+
+	_, err := newProxiedSession(t, "https", backend, &f5os.ProxyConfig{URL: proxy.URL, Username: "jump", Password: "s3cret"},
+		map[string]string{"X-Tenant-ID": "hsbc-prod"})
+	if err != nil {
+		t.Fatalf("NewSession through proxy failed: %v", err)
+	}
+	methods, headers := proxy.requests()
+	if len(methods) == 0 || methods[0] != http.MethodConnect {
+		t.Fatalf("expected a CONNECT request, got %v", methods)
+	}
+	if got := headers[0].Get("X-Tenant-ID"); got != "hsbc-prod" {
+		t.Fatalf("expected CONNECT to carry X-Tenant-ID='hsbc-prod', got %q", got)
+	}
+	if headers[0].Get("Proxy-Authorization") == "" {
+		t.Fatal("expected CONNECT to carry the proxy credentials")
+	}
+}
+
+// TestExplicitProxy_NoProxy verifies that hosts matching NoProxy are
+// reached directly and that an explicit proxy ignores HTTPS_PROXY.
+func TestExplicitProxy_NoProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy.example.net:8080")
+	session, err := f5os.NewLazySession(&f5os.F5osConfig{
+		Host:  "192.0.2.1",
+		User:  "admin",
+		Proxy: &f5os.ProxyConfig{URL: "proxy.example.net:3128", NoProxy: ".mgmt.example.com,10.1.0.0/16"},
+	})
+	if err != nil {
+		t.Fatalf("NewLazySession failed: %v", err)
+	}
+	for target, want := range map[string]string{
+		"https://chassis1.lab.example.com":  "http://proxy.example.net:3128",
+		"https://chassis2.mgmt.example.com": "",
+		"https://10.1.4.20":                 "",
+		"https://10.2.4.20":                 "http://proxy.example.net:3128",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		got, err := session.Transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: Proxy failed: %v", target, err)
+		}
+		if (got == nil && want != "") || (got != nil && got.String() != want) {
+			t.Fatalf("%s: expected proxy %q, got %v", target, want, got)
+		}
+	}
+
+	if _, err := f5os.NewLazySession(&f5os.F5osConfig{Host: "192.0.2.1", Proxy: &f5os.ProxyConfig{URL: "ftp://proxy:21"}}); err == nil ||
+		!strings.Contains(err.Error(), `unsupported proxy scheme "ftp"`) {
+		t.Fatalf("expected an unsupported scheme error, got %v", err)
+	}
+}
+
+// TestUnitProviderConfigureProxy verifies that provider aliases keep
+// separate proxies and that incomplete settings are reported against the
+// matching attribute.
+func TestUnitProviderConfigureProxy(t *testing.T) {
+	for _, env := range []string{"F5OS_PROXY_URL", "F5OS_PROXY_USERNAME", "F5OS_PROXY_PASSWORD", "F5OS_NO_PROXY"} {
+		t.Setenv(env, "")
+	}
+	configure := func(extra map[string]tftypes.Value) *provider.ConfigureResponse {
+		attrs := map[string]tftypes.Value{
+			"host":     tftypes.NewValue(tftypes.String, "192.0.2.10"),
+			"username": tftypes.NewValue(tftypes.String, "admin"),
+			"password": tftypes.NewValue(tftypes.String, "admin"),
+		}
+		for k, v := range extra {
+			attrs[k] = v
+		}
+		return testProviderConfigure(t, attrs)
+	}
+
+	clients := map[string]*f5os.F5os{}
+	for _, proxyURL := range []string{"socks5://jump-a:1080", "http://jump-b:3128"} {
+		resp := configure(map[string]tftypes.Value{
+			"proxy_url":      tftypes.NewValue(tftypes.String, proxyURL),
+			"proxy_username": tftypes.NewValue(tftypes.String, "ops"),
+			"proxy_password": tftypes.NewValue(tftypes.String, "pw"),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		clients[proxyURL] = resp.ResourceData.(*f5os.F5os)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://192.0.2.10/restconf", nil)
+	for proxyURL, client := range clients {
+		got, err := client.Transport.Proxy(req)
+		want := strings.Replace(proxyURL, "://", "://ops:pw@", 1)
+		if err != nil || got == nil || got.String() != want {
+			t.Fatalf("expected the session for %s to use %s, got %v (%v)", proxyURL, want, got, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		attrs   map[string]tftypes.Value
+		summary string
+	}{
+		{map[string]tftypes.Value{"no_proxy": tftypes.NewValue(tftypes.String, "10.0.0.0/8")}, "Missing 'proxy_url' in provider configuration"},
+		{map[string]tftypes.Value{
+			"proxy_url":      tftypes.NewValue(tftypes.String, "http://jump:3128"),
+			"proxy_password": tftypes.NewValue(tftypes.String, "pw"),
+		}, "Incomplete proxy credentials"},
+		{map[string]tftypes.Value{"proxy_url": tftypes.NewValue(tftypes.String, "ftp://jump:21")}, "Invalid proxy URL"},
+	} {
+		resp := configure(tc.attrs)
+		if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tc.summary {
+			t.Fatalf("expected %q, got %v", tc.summary, resp.Diagnostics)
+		}
+	}
+}
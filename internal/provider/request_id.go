@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// requestIDField is the log field that carries the correlation ID.
+const requestIDField = "f5os_request_id"
+
+// withRequestID returns ctx carrying a new correlation ID for op on the
+// typeName resource or data source, such as
+// "f5os_tenant/create/2f1c0e0e-...". Every API request made with the
+// context sends it in the X-Request-ID header, and it is logged with
+// every entry, so the device's audit log can be matched to the Terraform
+// operation.
+func withRequestID(ctx context.Context, typeName, op string) (context.Context, string) {
+	id := fmt.Sprintf("%s/%s/%s", typeName, op, uuid.NewString())
+	ctx = f5ossdk.ContextWithRequestID(ctx, id)
+	ctx = tflog.SetField(ctx, requestIDField, id)
+	return ctx, id
+}
+
+// annotateRequestID appends the correlation ID to the detail of each of
+// diags, so a failure reported by Terraform can be found in the device's
+// audit log.
+func annotateRequestID(diags *diag.Diagnostics, id string) {
+	if len(*diags) == 0 {
+		return
+	}
+	annotated := make(diag.Diagnostics, 0, len(*diags))
+	for _, d := range *diags {
+		detail := "Request ID: " + id
+		if d.Detail() != "" {
+			detail = d.Detail() + "\n\n" + detail
+		}
+		var attrPath path.Path
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			attrPath = withPath.Path()
+		}
+		switch {
+		case d.Severity() == diag.SeverityWarning && !attrPath.Equal(path.Empty()):
+			annotated = append(annotated, diag.NewAttributeWarningDiagnostic(attrPath, d.Summary(), detail))
+		case d.Severity() == diag.SeverityWarning:
+			annotated = append(annotated, diag.NewWarningDiagnostic(d.Summary(), detail))
+		case !attrPath.Equal(path.Empty()):
+			annotated = append(annotated, diag.NewAttributeErrorDiagnostic(attrPath, d.Summary(), detail))
+		default:
+			annotated = append(annotated, diag.NewErrorDiagnostic(d.Summary(), detail))
+		}
+	}
+	*diags = annotated
+}
+
+// withRequestIDs wraps each resource so that every operation that reaches
+// the device runs with its own correlation ID; see withRequestID.
+func withRequestIDs(factories []func() resource.Resource) []func() resource.Resource {
+	wrapped := make([]func() resource.Resource, len(factories))
+	for i, factory := range factories {
+		factory := factory
+		wrapped[i] = func() resource.Resource {
+			r := factory()
+			metadata := &resource.MetadataResponse{}
+			r.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "f5os"}, metadata)
+			return &requestIDResource{Resource: r, typeName: metadata.TypeName}
+		}
+	}
+	return wrapped
+}
+
+// requestIDResource is a resource that runs the operations of the one it
+// wraps with a correlation ID. It implements every optional interface the
+// provider's resources use, and forwards each to the wrapped resource when
+// it implements it.
+type requestIDResource struct {
+	resource.Resource
+	typeName string
+}
+
+var (
+	_ resource.ResourceWithConfigure      = &requestIDResource{}
+	_ resource.ResourceWithImportState    = &requestIDResource{}
+	_ resource.ResourceWithModifyPlan     = &requestIDResource{}
+	_ resource.ResourceWithUpgradeState   = &requestIDResource{}
+	_ resource.ResourceWithValidateConfig = &requestIDResource{}
+)
+
+func (r *requestIDResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, id := withRequestID(ctx, r.typeName, "create")
+	r.Resource.Create(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, id := withRequestID(ctx, r.typeName, "read")
+	r.Resource.Read(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, id := withRequestID(ctx, r.typeName, "update")
+	r.Resource.Update(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, id := withRequestID(ctx, r.typeName, "delete")
+	r.Resource.Delete(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if inner, ok := r.Resource.(resource.ResourceWithConfigure); ok {
+		inner.Configure(ctx, req, resp)
+	}
+}
+
+func (r *requestIDResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	inner, ok := r.Resource.(resource.ResourceWithImportState)
+	if !ok {
+		// The message the framework reports for a resource without import.
+		resp.Diagnostics.AddError(
+			"Resource Import Not Implemented",
+			"This resource does not support import. Please contact the provider developer for additional information.",
+		)
+		return
+	}
+	ctx, id := withRequestID(ctx, r.typeName, "import")
+	inner.ImportState(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	inner, ok := r.Resource.(resource.ResourceWithModifyPlan)
+	if !ok {
+		return
+	}
+	ctx, id := withRequestID(ctx, r.typeName, "plan")
+	inner.ModifyPlan(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (r *requestIDResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	if inner, ok := r.Resource.(resource.ResourceWithUpgradeState); ok {
+		return inner.UpgradeState(ctx)
+	}
+	return nil
+}
+
+func (r *requestIDResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if inner, ok := r.Resource.(resource.ResourceWithValidateConfig); ok {
+		inner.ValidateConfig(ctx, req, resp)
+	}
+}
+
+// withDataSourceRequestIDs is withRequestIDs for data sources.
+func withDataSourceRequestIDs(factories []func() datasource.DataSource) []func() datasource.DataSource {
+	wrapped := make([]func() datasource.DataSource, len(factories))
+	for i, factory := range factories {
+		factory := factory
+		wrapped[i] = func() datasource.DataSource {
+			d := factory()
+			metadata := &datasource.MetadataResponse{}
+			d.Metadata(context.Background(), datasource.MetadataRequest{ProviderTypeName: "f5os"}, metadata)
+			return &requestIDDataSource{DataSource: d, typeName: metadata.TypeName}
+		}
+	}
+	return wrapped
+}
+
+// requestIDDataSource is requestIDResource for data sources.
+type requestIDDataSource struct {
+	datasource.DataSource
+	typeName string
+}
+
+var (
+	_ datasource.DataSourceWithConfigure      = &requestIDDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &requestIDDataSource{}
+)
+
+func (d *requestIDDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, id := withRequestID(ctx, d.typeName, "read")
+	d.DataSource.Read(ctx, req, resp)
+	annotateRequestID(&resp.Diagnostics, id)
+}
+
+func (d *requestIDDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if inner, ok := d.DataSource.(datasource.DataSourceWithConfigure); ok {
+		inner.Configure(ctx, req, resp)
+	}
+}
+
+func (d *requestIDDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	if inner, ok := d.DataSource.(datasource.DataSourceWithValidateConfig); ok {
+		inner.ValidateConfig(ctx, req, resp)
+	}
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// newRequestIDBackend returns a fake F5OS endpoint that accepts any login
+// and records the X-Request-ID header of every request, keyed by path.
+func newRequestIDBackend(t *testing.T) (*httptest.Server, func() map[string]string) {
+	t.Helper()
+	var mu sync.Mutex
+	ids := map[string]string{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids[r.URL.Path] = r.Header.Get(f5os.RequestIDHeader)
+		mu.Unlock()
+		w.Header().Set("X-Auth-Token", "token")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(backend.Close)
+	return backend, func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return ids
+	}
+}
+
+// TestRequestID_SentWithRequests verifies that a request made with a
+// context carrying a correlation ID sends it, and that the login made
+// without one does not.
+func TestRequestID_SentWithRequests(t *testing.T) {
+	backend, ids := newRequestIDBackend(t)
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: backend.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+
+	ctx, id := withRequestID(context.Background(), "f5os_vlan", "read")
+	if !regexp.MustCompile(`^f5os_vlan/read/[0-9a-f-]{36}$`).MatchString(id) {
+		t.Fatalf("unexpected request ID format %q", id)
+	}
+	if got := f5os.RequestIDFromContext(ctx); got != id {
+		t.Fatalf("expected the context to carry %q, got %q", id, got)
+	}
+	if _, err := session.WithContext(ctx).GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+
+	for p, got := range ids() {
+		switch {
+		case strings.HasSuffix(p, "/openconfig-vlan:vlans") && got != id:
+			t.Fatalf("expected %s to send %q, got %q", p, id, got)
+		case strings.HasSuffix(p, "/openconfig-system:system/aaa") && got != "":
+			t.Fatalf("expected the login not to send a request ID, got %q", got)
+		}
+	}
+}
+
+// requestIDStub is a resource whose Read records the request ID of its
+// context and reports the diagnostics it is given.
+type requestIDStub struct {
+	id    string
+	diags diag.Diagnostics
+}
+
+func (r *requestIDStub) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stub"
+}
+
+func (r *requestIDStub) Schema(context.Context, resource.SchemaRequest, *resource.SchemaResponse) {}
+
+func (r *requestIDStub) Create(context.Context, resource.CreateRequest, *resource.CreateResponse) {}
+
+func (r *requestIDStub) Read(ctx context.Context, _ resource.ReadRequest, resp *resource.ReadResponse) {
+	r.id = f5os.RequestIDFromContext(ctx)
+	resp.Diagnostics.Append(r.diags...)
+}
+
+func (r *requestIDStub) Update(context.Context, resource.UpdateRequest, *resource.UpdateResponse) {}
+
+func (r *requestIDStub) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {}
+
+// TestRequestID_AnnotatesDiagnostics verifies that a wrapped resource runs
+// with a correlation ID named after its type and operation, and that the
+// ID is appended to every diagnostic without changing its severity or
+// attribute path.
+func TestRequestID_AnnotatesDiagnostics(t *testing.T) {
+	stub := &requestIDStub{diags: diag.Diagnostics{
+		diag.NewAttributeErrorDiagnostic(path.Root("name"), "Invalid name", "The name is taken."),
+		diag.NewWarningDiagnostic("Deprecated", ""),
+	}}
+	r := withRequestIDs([]func() resource.Resource{func() resource.Resource { return stub }})[0]()
+	if _, ok := r.(resource.ResourceWithImportState); !ok {
+		t.Fatal("expected the wrapped resource to implement import")
+	}
+
+	resp := &resource.ReadResponse{}
+	r.Read(context.Background(), resource.ReadRequest{}, resp)
+
+	if !strings.HasPrefix(stub.id, "f5os_stub/read/") {
+		t.Fatalf("expected a read request ID for f5os_stub, got %q", stub.id)
+	}
+	if len(resp.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", resp.Diagnostics)
+	}
+	errDiag, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+	if !ok || !errDiag.Path().Equal(path.Root("name")) || errDiag.Severity() != diag.SeverityError {
+		t.Fatalf("expected an error on name, got %#v", resp.Diagnostics[0])
+	}
+	if want := "The name is taken.\n\nRequest ID: " + stub.id; errDiag.Detail() != want {
+		t.Fatalf("expected detail %q, got %q", want, errDiag.Detail())
+	}
+	if warn := resp.Diagnostics[1]; warn.Severity() != diag.SeverityWarning || warn.Detail() != "Request ID: "+stub.id {
+		t.Fatalf("expected the warning to carry only the request ID, got %#v", warn)
+	}
+
+	importResp := &resource.ImportStateResponse{}
+	r.(resource.ResourceWithImportState).ImportState(context.Background(), resource.ImportStateRequest{ID: "x"}, importResp)
+	if !importResp.Diagnostics.HasError() || importResp.Diagnostics[0].Summary() != "Resource Import Not Implemented" {
+		t.Fatalf("expected import to be rejected for a resource without it, got %v", importResp.Diagnostics)
+	}
+}
+
+// recordingLogger is a ContextLogger that records its entries and the
+// request ID of the context it was last bound to.
+type recordingLogger struct {
+	mu      *sync.Mutex
+	entries *[]string
+	ids     *[]string
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{mu: &sync.Mutex{}, entries: &[]string{}, ids: &[]string{}}
+}
+
+func (l recordingLogger) WithContext(ctx context.Context) f5os.Logger {
+	l.mu.Lock()
+	*l.ids = append(*l.ids, f5os.RequestIDFromContext(ctx))
+	l.mu.Unlock()
+	return l
+}
+
+func (l recordingLogger) record(msg string) {
+	l.mu.Lock()
+	*l.entries = append(*l.entries, msg)
+	l.mu.Unlock()
+}
+
+func (l recordingLogger) Trace(msg string, _ ...interface{}) { l.record(msg) }
+func (l recordingLogger) Debug(msg string, _ ...interface{}) { l.record(msg) }
+func (l recordingLogger) Info(msg string, _ ...interface{})  { l.record(msg) }
+func (l recordingLogger) Warn(msg string, _ ...interface{})  { l.record(msg) }
+func (l recordingLogger) Error(msg string, _ ...interface{}) { l.record(msg) }
+
+// TestLogger_Injected verifies that a session logs to the Logger it is
+// configured with, and binds it to the context of each operation.
+func TestLogger_Injected(t *testing.T) {
+	backend, _ := newRequestIDBackend(t)
+	logger := newRecordingLogger()
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: backend.URL, User: "admin", Password: "admin", Logger: logger})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if len(*logger.entries) == 0 {
+		t.Fatal("expected the login to be logged to the configured logger")
+	}
+
+	ctx, id := withRequestID(context.Background(), "f5os_vlan", "read")
+	before := len(*logger.entries)
+	if _, err := session.WithContext(ctx).GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if len(*logger.entries) == before {
+		t.Fatal("expected the request to be logged to the configured logger")
+	}
+	if ids := *logger.ids; len(ids) == 0 || ids[len(ids)-1] != id {
+		t.Fatalf("expected the logger to be bound to the request's context, got %v", ids)
+	}
+}
+
+// TestLogger_Fields verifies the conversion of hclog-style arguments to
+// tflog fields.
+func TestLogger_Fields(t *testing.T) {
+	fields := f5os.Fields([]interface{}{"URL", hclog.Fmt("%s:%d", "host", 443), "", "free text", "dangling"})
+	if fields["URL"] != "host:443" {
+		t.Fatalf("expected a formatted URL, got %#v", fields["URL"])
+	}
+	if fields["detail"] != "free text" {
+		t.Fatalf("expected an empty key to map to detail, got %#v", fields)
+	}
+	if fields[hclog.MissingKey] != "dangling" {
+		t.Fatalf("expected a trailing value under %q, got %#v", hclog.MissingKey, fields)
+	}
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+var (
+	_ datasource.DataSource                   = &RestconfDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &RestconfDataSource{}
+)
+
+func NewRestconfDataSource() datasource.DataSource {
+	return &RestconfDataSource{}
+}
+
+// RestconfDataSource reads any RESTCONF path, for operational state the
+// typed data sources do not model.
+type RestconfDataSource struct {
+	client *f5ossdk.F5os
+}
+
+// RestconfDataSourceModel describes the data source data model.
+type RestconfDataSourceModel struct {
+	Id        types.String `tfsdk:"id"`
+	Path      types.String `tfsdk:"path"`
+	Depth     types.Int64  `tfsdk:"depth"`
+	Fields    types.String `tfsdk:"fields"`
+	Content   types.String `tfsdk:"content"`
+	Selectors types.Map    `tfsdk:"selectors"`
+	Json      types.String `tfsdk:"json"`
+	Values    types.Map    `tfsdk:"values"`
+}
+
+func (d *RestconfDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_restconf"
+}
+
+func (d *RestconfDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Read any RESTCONF path on F5OS platforms (VELOS or rSeries).\n\n" +
+			"Use this data source to read configuration or operational state (alarms, counters, optics) that no other data source exposes, " +
+			"and pick values out of it with JSONPath `selectors`.\n\n" +
+			"Not supported with the provider's `transport` set to `netconf`.",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "RESTCONF path below the data root, e.g. `/openconfig-system:system/f5-alarms:alarms`. List keys must be URL encoded.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^/[^?\s]*$`), "must start with / and contain no spaces or query"),
+				},
+			},
+			"depth": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "RESTCONF `depth` query parameter: the number of levels of child nodes returned.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"fields": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "RESTCONF `fields` query parameter selecting the nodes returned, e.g. `config/name;state(oper-status)`.",
+			},
+			"content": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "RESTCONF `content` query parameter: `config`, `nonconfig` or `all` (the device default).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("config", "nonconfig", "all"),
+				},
+			},
+			"selectors": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: "JSONPath expressions to evaluate on the response, by name, e.g. `{ status = \"$.tenant[0].state.status\" }`. " +
+					"Member names match with or without their module prefix. Supported are `.name`, `['name']`, `*`, `[n]`, `..` and filters such as `[?(@.name == 'x')]`.",
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The path and query that were read.",
+			},
+			"json": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The response as returned by the device. Use `jsondecode()` to access it.",
+			},
+			"values": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				MarkdownDescription: "The value each selector selected: a scalar as its text, anything else as JSON (an array when the selector matched several values). " +
+					"Selectors that match nothing are left out, so use `lookup()` with a default for optional data.",
+			},
+		},
+	}
+}
+
+func (d *RestconfDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client, resp.Diagnostics = toF5osProvider(req.ProviderData)
+	rejectNetconfTransport(d.client, "data source f5os_restconf", &resp.Diagnostics)
+}
+
+func (d *RestconfDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data RestconfDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Selectors.IsNull() || data.Selectors.IsUnknown() {
+		return
+	}
+	for name, v := range data.Selectors.Elements() {
+		s, ok := v.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+		if _, err := parseJSONPath(s.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("selectors").AtMapKey(name), "Invalid JSONPath", err.Error())
+		}
+	}
+}
+
+// restconfQuery returns uri with the RESTCONF query parameters set in
+// data.
+func restconfQuery(uri string, data *RestconfDataSourceModel) string {
+	query := url.Values{}
+	if !data.Depth.IsNull() {
+		query.Set("depth", strconv.FormatInt(data.Depth.ValueInt64(), 10))
+	}
+	if data.Fields.ValueString() != "" {
+		query.Set("fields", data.Fields.ValueString())
+	}
+	if data.Content.ValueString() != "" {
+		query.Set("content", data.Content.ValueString())
+	}
+	if len(query) == 0 {
+		return uri
+	}
+	return uri + "?" + query.Encode()
+}
+
+func (d *RestconfDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RestconfDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	uri := restconfQuery(data.Path.ValueString(), &data)
+	tflog.Info(ctx, "Reading RESTCONF path", map[string]interface{}{"path": uri})
+	body, err := d.client.WithContext(ctx).GetRequest(uri)
+	if err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err, "RESTCONF Read Error", fmt.Sprintf("Failed to read %s: %s", uri, err))
+		return
+	}
+	if errs := restconfBodyErrors(body); len(errs) > 0 {
+		detail := fmt.Sprintf("Failed to read %s: %s", uri, restconfBodyError(body))
+		if restconfMissing(errs) {
+			detail = fmt.Sprintf("%s does not exist on the device, or has no data.", uri)
+		}
+		resp.Diagnostics.AddError("RESTCONF Read Error", detail)
+		return
+	}
+	raw := strings.TrimSpace(string(body))
+	if raw == "" {
+		raw = "{}"
+	}
+	doc, err := decodeJSON([]byte(raw))
+	if err != nil {
+		resp.Diagnostics.AddError("RESTCONF Read Error", fmt.Sprintf("Invalid JSON in response from %s: %s", uri, err))
+		return
+	}
+
+	values := map[string]string{}
+	selectors := map[string]string{}
+	resp.Diagnostics.Append(data.Selectors.ElementsAs(ctx, &selectors, false)...)
+	names := make([]string, 0, len(selectors))
+	for name := range selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		expr, err := parseJSONPath(selectors[name])
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("selectors").AtMapKey(name), "Invalid JSONPath", err.Error())
+			continue
+		}
+		if selected := expr.eval(doc); len(selected) > 0 {
+			values[name] = jsonPathValue(selected)
+		} else {
+			tflog.Debug(ctx, "JSONPath selector matched nothing", map[string]interface{}{"selector": name})
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(uri)
+	data.Json = types.StringValue(raw)
+	valuesMap, diags := types.MapValueFrom(ctx, types.StringType, values)
+	resp.Diagnostics.Append(diags...)
+	data.Values = valuesMap
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+func restconfDataSourceModel(uri string, selectors map[string]string) RestconfDataSourceModel {
+	data := RestconfDataSourceModel{
+		Id:        types.StringNull(),
+		Path:      types.StringValue(uri),
+		Depth:     types.Int64Null(),
+		Fields:    types.StringNull(),
+		Content:   types.StringNull(),
+		Selectors: types.MapNull(types.StringType),
+		Json:      types.StringNull(),
+		Values:    types.MapNull(types.StringType),
+	}
+	if selectors != nil {
+		data.Selectors, _ = types.MapValueFrom(context.Background(), types.StringType, selectors)
+	}
+	return data
+}
+
+func TestUnitRestconfDataSourceRead(t *testing.T) {
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	data := restconfDataSourceModel("/openconfig-system:system", map[string]string{
+		"hostname": "$.system.config.hostname",
+		"roles":    "$..role[*].rolename",
+		"missing":  "$.system.alarms",
+	})
+	data.Depth = types.Int64Value(3)
+	data.Content = types.StringValue("config")
+	var got RestconfDataSourceModel
+	resp := testDataSourceRead(t, &RestconfDataSource{client: client}, &data, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+
+	values := map[string]string{}
+	resp.Diagnostics.Append(got.Values.ElementsAs(context.Background(), &values, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read back state: %v", resp.Diagnostics)
+	}
+	if len(values) != 2 || values["hostname"] != "appliance-1.example.net" || values["roles"] != `["admin","operator"]` {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if !strings.HasPrefix(got.Json.ValueString(), `{"openconfig-system:system":`) {
+		t.Fatalf("unexpected json %s", got.Json.ValueString())
+	}
+	const wantURI = "/openconfig-system:system?content=config&depth=3"
+	if got.Id.ValueString() != wantURI || !contains(server.Requests(), "GET /restconf/data"+wantURI) {
+		t.Fatalf("expected a read of %s, got id %s and requests %v", wantURI, got.Id.ValueString(), server.Requests())
+	}
+}
+
+func TestUnitRestconfDataSourceNotFound(t *testing.T) {
+	client, _ := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	data := restconfDataSourceModel("/f5-tenants:tenants/tenant=none", nil)
+	resp := testDataSourceRead(t, &RestconfDataSource{client: client}, &data, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "does not exist") {
+		t.Fatalf("expected a not found error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestUnitRestconfDataSource(t *testing.T) {
+	testAccPreUnitCheck(t)
+	setupVlanCommonMock(t)
+	mux.HandleFunc("/restconf/data/openconfig-system:system/f5-alarms:alarms", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"f5-alarms:alarms":{"alarm":[{"id":"65793","state":{"severity":"critical","description":"PSU fault"}}]}}`)
+	})
+	defer teardown()
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRestconfDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.f5os_restconf.alarms", "id", "/openconfig-system:system/f5-alarms:alarms"),
+					resource.TestCheckResourceAttr("data.f5os_restconf.alarms", "values.critical", "PSU fault"),
+				),
+			},
+			{
+				Config:      testAccRestconfDataSourceInvalidConfig,
+				ExpectError: regexp.MustCompile(`Invalid JSONPath`),
+			},
+		},
+	})
+}
+
+const testAccRestconfDataSourceConfig = `
+data "f5os_restconf" "alarms" {
+  path = "/openconfig-system:system/f5-alarms:alarms"
+  selectors = {
+    critical = "$.alarms.alarm[?(@.state.severity == 'critical')].state.description"
+  }
+}
+`
+
+const testAccRestconfDataSourceInvalidConfig = `
+data "f5os_restconf" "alarms" {
+  path      = "/openconfig-system:system/f5-alarms:alarms"
+  selectors = { bad = "alarms.alarm" }
+}
+`
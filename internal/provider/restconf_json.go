@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// decodeJSON decodes a JSON document, keeping numbers as json.Number so
+// they re-encode exactly as the device sent them.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// compactJSON encodes v as compact JSON with object members sorted.
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// jsonLocalName strips the YANG module prefix from a JSON member name, so
+// "openconfig-vlan:config" becomes "config".
+func jsonLocalName(name string) string {
+	return name[strings.LastIndex(name, ":")+1:]
+}
+
+// jsonLocalPath strips the module prefixes from every node of a
+// "/"-separated path.
+func jsonLocalPath(p string) string {
+	nodes := strings.Split(strings.Trim(p, "/"), "/")
+	for i, n := range nodes {
+		nodes[i] = jsonLocalName(n)
+	}
+	return strings.Join(nodes, "/")
+}
+
+// jsonMember returns the member of m named name. The device qualifies a
+// member with its module only where the module changes, so a configured
+// name matches either exactly or by local name.
+func jsonMember(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	local := jsonLocalName(name)
+	for k, v := range m {
+		if jsonLocalName(k) == local {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// jsonScalar returns the text of a JSON scalar. YANG numbers are sent as
+// strings or numbers depending on their type, so 5 and "5" compare equal.
+func jsonScalar(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "null", true
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case bool, float64:
+		return fmt.Sprint(x), true
+	}
+	return "", false
+}
+
+// jsonEqual reports whether a and b are semantically equal: object members
+// match by local name and scalars by their text. Arrays compare in order.
+func jsonEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := jsonMember(y, k)
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	s, ok := jsonScalar(a)
+	t, ok2 := jsonScalar(b)
+	return ok && ok2 && s == t
+}
+
+// projectJSON returns the part of actual that configured sets, so leaves
+// the device populates itself (state, defaults, counters) do not show up
+// as drift. Objects keep only the configured members, under their
+// configured names; each configured array element is matched against an
+// element of the device array, and extra device elements are dropped.
+// Members whose local path (for example "vlan/config/name") is in ignore
+// keep their configured value.
+func projectJSON(configured, actual interface{}, ignore map[string]bool) interface{} {
+	return projectJSONAt(configured, actual, ignore, "")
+}
+
+func projectJSONAt(configured, actual interface{}, ignore map[string]bool, at string) interface{} {
+	switch c := configured.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return actual
+		}
+		out := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			p := strings.TrimPrefix(at+"/"+jsonLocalName(k), "/")
+			if ignore[p] {
+				out[k] = v
+				continue
+			}
+			if w, ok := jsonMember(a, k); ok {
+				out[k] = projectJSONAt(v, w, ignore, p)
+			}
+		}
+		return out
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return actual
+		}
+		// Pair each configured element with a device element it matches
+		// exactly, then pair the rest with the unused device element
+		// sharing the most top-level leaves (typically the list key),
+		// so a changed leaf in an entry shows as drift in that entry.
+		matched := make([]int, len(c))
+		used := make([]bool, len(a))
+		for j, v := range c {
+			matched[j] = -1
+			for i, w := range a {
+				if !used[i] && jsonEqual(v, projectJSONAt(v, w, ignore, at)) {
+					matched[j], used[i] = i, true
+					break
+				}
+			}
+		}
+		for j, v := range c {
+			if matched[j] >= 0 {
+				continue
+			}
+			best, bestShared := -1, 0
+			for i, w := range a {
+				if n := sharedLeaves(v, w); !used[i] && n > bestShared {
+					best, bestShared = i, n
+				}
+			}
+			if best >= 0 {
+				matched[j], used[best] = best, true
+			}
+		}
+		out := make([]interface{}, 0, len(c))
+		for j, v := range c {
+			if matched[j] >= 0 {
+				out = append(out, projectJSONAt(v, a[matched[j]], ignore, at))
+			}
+		}
+		return out
+	}
+	if jsonEqual(configured, actual) {
+		return configured
+	}
+	return actual
+}
+
+// sharedLeaves counts the scalar members of object c that object a has
+// with the same value.
+func sharedLeaves(c, a interface{}) int {
+	cm, ok := c.(map[string]interface{})
+	am, ok2 := a.(map[string]interface{})
+	if !ok || !ok2 {
+		return 0
+	}
+	n := 0
+	for k, v := range cm {
+		if _, scalar := jsonScalar(v); !scalar {
+			continue
+		}
+		if w, ok := jsonMember(am, k); ok && jsonEqual(v, w) {
+			n++
+		}
+	}
+	return n
+}
+
+// jsonDifferences lists the local paths at which projected, the result of
+// projectJSON, differs from configured.
+func jsonDifferences(configured, projected interface{}) []string {
+	var diffs []string
+	var walk func(c, p interface{}, at string)
+	walk = func(c, p interface{}, at string) {
+		if jsonEqual(c, p) {
+			return
+		}
+		if cl, ok := c.([]interface{}); ok {
+			if pl, ok := p.([]interface{}); ok && len(cl) == len(pl) {
+				for i := range cl {
+					walk(cl[i], pl[i], at)
+				}
+				return
+			}
+		}
+		cm, ok := c.(map[string]interface{})
+		pm, ok2 := p.(map[string]interface{})
+		if !ok || !ok2 {
+			diffs = append(diffs, "/"+at)
+			return
+		}
+		for k, v := range cm {
+			child := strings.TrimPrefix(at+"/"+jsonLocalName(k), "/")
+			w, ok := jsonMember(pm, k)
+			if !ok {
+				diffs = append(diffs, "/"+child)
+				continue
+			}
+			walk(v, w, child)
+		}
+	}
+	walk(configured, projected, "")
+	sort.Strings(diffs)
+	// Entries of one list may differ at the same path.
+	out := diffs[:0]
+	for i, d := range diffs {
+		if i == 0 || d != diffs[i-1] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// restconfBodyErrors returns the errors in an ietf-restconf:errors
+// document. The client returns the body of a 404 without an error, so a
+// missing object or a failed write is only visible here.
+func restconfBodyErrors(body []byte) []f5ossdk.RestconfError {
+	var doc f5ossdk.F5osError
+	if json.Unmarshal(body, &doc) != nil {
+		return nil
+	}
+	return doc.IetfRestconfErrors.Error
+}
+
+// restconfBodyError returns an error describing the errors in body, or nil
+// when there are none.
+func restconfBodyError(body []byte) error {
+	errs := restconfBodyErrors(body)
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msg := e.Tag
+		if e.Message != "" {
+			msg += ": " + e.Message
+		}
+		if e.Path != "" {
+			msg += " (" + e.Path + ")"
+		}
+		msgs = append(msgs, msg)
+	}
+	return fmt.Errorf("device returned an error: %s", strings.Join(msgs, "; "))
+}
+
+// restconfMissing reports whether errs is the device's answer for a path
+// that does not exist.
+func restconfMissing(errs []f5ossdk.RestconfError) bool {
+	for _, e := range errs {
+		if e.Tag == "invalid-value" || e.Tag == "data-missing" {
+			return true
+		}
+	}
+	return false
+}
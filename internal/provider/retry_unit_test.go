@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// retryBackend is a mock F5OS device that answers the login handshake and
+// hands every other request to status, recording when each one arrived.
+type retryBackend struct {
+	*httptest.Server
+	mu     sync.Mutex
+	logins int
+	hits   []time.Time
+}
+
+func newRetryBackend(loginStatus int, status func(n int, w http.ResponseWriter)) *retryBackend {
+	b := &retryBackend{}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa") {
+			b.logins++
+			if loginStatus != http.StatusOK {
+				w.WriteHeader(loginStatus)
+				return
+			}
+			w.Header().Set("X-Auth-Token", "test-token")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"openconfig-system:aaa":{}}`))
+			return
+		}
+		if strings.Contains(r.URL.Path, "openconfig-platform:components") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b.hits = append(b.hits, time.Now())
+		status(len(b.hits), w)
+	}))
+	return b
+}
+
+func (b *retryBackend) hitCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.hits)
+}
+
+func newRetrySession(t *testing.T, url string, policy *f5os.RetryPolicy) *f5os.F5os {
+	t.Helper()
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:     url,
+		User:     "admin",
+		Password: "admin",
+		Retry:    policy,
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	return session
+}
+
+// TestRetryPolicy_MaxAttempts verifies that a retryable status is retried
+// exactly MaxAttempts times in total.
+func TestRetryPolicy_MaxAttempts(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err == nil {
+		t.Fatal("expected GetRequest to fail on persistent 503")
+	}
+	if got := backend.hitCount(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+// TestRetryPolicy_NonRetryableStatusFailsFast verifies that a status not
+// listed in RetryableStatusCodes is reported after a single attempt.
+func TestRetryPolicy_NonRetryableStatusFailsFast(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ietf-restconf:errors":{"error":[{"error-type":"application","error-tag":"invalid-value","error-message":"bad vlan"}]}}`))
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:          6,
+		InitialBackoff:       time.Millisecond,
+		RetryableStatusCodes: []int{502, 503, 504},
+	})
+	_, err := session.GetRequest("/openconfig-vlan:vlans")
+	if err == nil || !strings.Contains(err.Error(), "bad vlan") {
+		t.Fatalf("expected the device error message, got %v", err)
+	}
+	if got := backend.hitCount(); got != 1 {
+		t.Fatalf("expected a single attempt, got %d", got)
+	}
+}
+
+// TestRetryPolicy_ExponentialBackoff verifies that the delay doubles from
+// InitialBackoff and is capped at MaxBackoff.
+func TestRetryPolicy_ExponentialBackoff(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(n int, w http.ResponseWriter) {
+		if n < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     40 * time.Millisecond,
+	})
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("expected the fourth attempt to succeed, got %v", err)
+	}
+	backend.mu.Lock()
+	hits := backend.hits
+	backend.mu.Unlock()
+	if len(hits) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(hits))
+	}
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, min := range want {
+		if gap := hits[i+1].Sub(hits[i]); gap < min {
+			t.Fatalf("retry %d: expected a delay of at least %s, got %s", i+1, min, gap)
+		}
+	}
+}
+
+// TestRetryPolicy_RetryAfter verifies that a Retry-After header longer than
+// the computed backoff is honored.
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(n int, w http.ResponseWriter) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer backend.Close()
+
+	session := newRetrySession(t, backend.URL, &f5os.RetryPolicy{
+		MaxAttempts:          2,
+		InitialBackoff:       time.Millisecond,
+		RetryableStatusCodes: []int{429},
+	})
+	start := time.Now()
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected Retry-After: 1 to delay the retry by 1s, took %s", elapsed)
+	}
+}
+
+// TestRetryPolicy_LoginStatusFailsFast verifies that excluding 401 from
+// the retryable statuses surfaces a bad password on the first attempt
+// instead of after the auth rate-limit retry window.
+func TestRetryPolicy_LoginStatusFailsFast(t *testing.T) {
+	backend := newRetryBackend(http.StatusUnauthorized, nil)
+	defer backend.Close()
+
+	_, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:     backend.URL,
+		User:     "admin",
+		Password: "typo",
+		Retry: &f5os.RetryPolicy{
+			InitialBackoff:       time.Millisecond,
+			RetryableStatusCodes: []int{503},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected a 401 error, got %v", err)
+	}
+	if backend.logins != 1 {
+		t.Fatalf("expected a single login attempt, got %d", backend.logins)
+	}
+}
+
+// TestRetryPolicy_TransportErrors verifies that only transport errors
+// matching RetryableErrors are retried.
+func TestRetryPolicy_TransportErrors(t *testing.T) {
+	var mu sync.Mutex
+	accepted := 0
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		accepted++
+		mu.Unlock()
+		// Drop the connection without a response so the client sees EOF.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer backend.Close()
+
+	cases := []struct {
+		name      string
+		retryable []string
+		want      int
+	}{
+		{name: "EOF retried", retryable: []string{"EOF"}, want: 3},
+		{name: "EOF not retried", retryable: []string{"connection refused"}, want: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			accepted = 0
+			mu.Unlock()
+			_, err := f5os.NewSession(&f5os.F5osConfig{
+				Host:     backend.URL,
+				User:     "admin",
+				Password: "admin",
+				Retry: &f5os.RetryPolicy{
+					MaxAttempts:     3,
+					InitialBackoff:  time.Millisecond,
+					RetryableErrors: tc.retryable,
+				},
+			})
+			if err == nil {
+				t.Fatal("expected NewSession to fail")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if accepted != tc.want {
+				t.Fatalf("expected %d attempts, got %d", tc.want, accepted)
+			}
+		})
+	}
+}
+
+// TestUnitProviderConfigureRetry verifies the provider `retry` block is
+// validated and handed to the client.
+func TestUnitProviderConfigureRetry(t *testing.T) {
+	backend := newMockBackend()
+	defer backend.Close()
+
+	retryType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"max_attempts":           tftypes.Number,
+		"initial_backoff":        tftypes.String,
+		"max_backoff":            tftypes.String,
+		"jitter":                 tftypes.Number,
+		"retryable_status_codes": tftypes.List{ElementType: tftypes.Number},
+		"retryable_errors":       tftypes.List{ElementType: tftypes.String},
+	}}
+	retryBlock := func(initial, max string, jitter float64) tftypes.Value {
+		return tftypes.NewValue(retryType, map[string]tftypes.Value{
+			"max_attempts":    tftypes.NewValue(tftypes.Number, 3),
+			"initial_backoff": tftypes.NewValue(tftypes.String, initial),
+			"max_backoff":     tftypes.NewValue(tftypes.String, max),
+			"jitter":          tftypes.NewValue(tftypes.Number, jitter),
+			"retryable_status_codes": tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{
+				tftypes.NewValue(tftypes.Number, 503),
+			}),
+			"retryable_errors": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		})
+	}
+	configure := func(retry tftypes.Value) *f5os.F5os {
+		resp := testProviderConfigure(t, map[string]tftypes.Value{
+			"host":     tftypes.NewValue(tftypes.String, backend.URL),
+			"username": tftypes.NewValue(tftypes.String, "admin"),
+			"password": tftypes.NewValue(tftypes.String, "admin"),
+			"retry":    retry,
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		return resp.ResourceData.(*f5os.F5os)
+	}
+
+	client := configure(retryBlock("2s", "30s", 0.2))
+	want := f5os.RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       2 * time.Second,
+		MaxBackoff:           30 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{503},
+	}
+	if client.Retry == nil || client.Retry.MaxAttempts != want.MaxAttempts ||
+		client.Retry.InitialBackoff != want.InitialBackoff || client.Retry.MaxBackoff != want.MaxBackoff ||
+		client.Retry.Jitter != want.Jitter || len(client.Retry.RetryableStatusCodes) != 1 ||
+		client.Retry.RetryableStatusCodes[0] != 503 {
+		t.Fatalf("expected retry policy %+v, got %+v", want, client.Retry)
+	}
+
+	if client := configure(tftypes.NewValue(retryType, nil)); client.Retry != nil {
+		t.Fatalf("expected no retry policy without a retry block, got %+v", client.Retry)
+	}
+
+	for name, retry := range map[string]tftypes.Value{
+		"max_backoff shorter than initial_backoff": retryBlock("10s", "1s", 0),
+		"jitter out of range":                      retryBlock("1s", "2s", 1.5),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := testProviderConfigure(t, map[string]tftypes.Value{
+				"host":     tftypes.NewValue(tftypes.String, backend.URL),
+				"username": tftypes.NewValue(tftypes.String, "admin"),
+				"password": tftypes.NewValue(tftypes.String, "admin"),
+				"retry":    retry,
+			})
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid retry configuration" {
+				t.Fatalf("expected an invalid retry configuration diagnostic, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,487 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// logoutBackend is a fake F5OS endpoint that issues a new token per
+// basic-auth login and records which tokens were logged out. It reports
+// a token lifetime of lifetime minutes when that is set.
+type logoutBackend struct {
+	*httptest.Server
+	password string
+	mu       sync.Mutex
+	logins   int
+	valid    map[string]bool
+	logouts  []string
+	lifetime int
+}
+
+func newLogoutBackend(password string) *logoutBackend {
+	b := &logoutBackend{password: password, valid: map[string]bool{}}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/openconfig-system:system/aaa"):
+			if _, pw, ok := r.BasicAuth(); ok {
+				if pw != b.password {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				b.logins++
+				token := fmt.Sprintf("token-%d", b.logins)
+				b.valid[token] = true
+				w.Header().Set("X-Auth-Token", token)
+			} else if !b.valid[r.Header.Get("X-Auth-Token")] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case strings.Contains(r.URL.Path, "openconfig-platform:components"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, ":logout"):
+			token := r.Header.Get("X-Auth-Token")
+			b.logouts = append(b.logouts, token)
+			delete(b.valid, token)
+			w.WriteHeader(http.StatusNoContent)
+		case !b.valid[r.Header.Get("X-Auth-Token")]:
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, ":restconf-token/state/lifetime") && b.lifetime > 0:
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"f5-aaa-confd-restconf-token:lifetime":%d}`, b.lifetime)
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	return b
+}
+
+func (b *logoutBackend) revokeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.valid = map[string]bool{}
+}
+
+// TestClose_LogsOutAndScrubs verifies that Close invalidates the token
+// on the device, clears the password and rejects further requests.
+func TestClose_LogsOutAndScrubs(t *testing.T) {
+	backend := newLogoutBackend("admin")
+	defer backend.Close()
+	session := newRetrySession(t, backend.URL, nil)
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(backend.logouts) != 1 || backend.logouts[0] != "token-1" {
+		t.Fatalf("expected token-1 to be logged out, got %v", backend.logouts)
+	}
+	if session.Password != "" || session.Token != "" {
+		t.Fatal("expected Close to clear the password and token")
+	}
+	if _, err := session.WithContext(context.Background()).GetRequest("/openconfig-vlan:vlans"); !errors.Is(err, f5os.ErrSessionClosed) {
+		t.Fatalf("expected ErrSessionClosed, got %v", err)
+	}
+	if err := session.Close(); err != nil || len(backend.logouts) != 1 {
+		t.Fatalf("expected a second Close to be a no-op, got err=%v logouts=%v", err, backend.logouts)
+	}
+}
+
+// TestClose_KeepsCallerToken verifies that closing a session created from
+// a caller-supplied token does not revoke that token on the device.
+func TestClose_KeepsCallerToken(t *testing.T) {
+	backend := newTokenBackend("ci-token")
+	defer backend.Close()
+	session, err := f5os.NewSession(&f5os.F5osConfig{Host: backend.URL, Token: "ci-token"})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if session.Token != "" {
+		t.Fatal("expected Close to clear the token from the session")
+	}
+	for _, r := range backend.requests {
+		if strings.HasSuffix(r, ":logout") {
+			t.Fatalf("expected the caller's token not to be logged out, got %s", r)
+		}
+	}
+}
+
+// TestCredentials_CallbackOnEveryLogin verifies that a session built with
+// a credentials callback holds no password and calls the callback again
+// to refresh an expired token.
+func TestCredentials_CallbackOnEveryLogin(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	backend := newLogoutBackend("from-vault")
+	defer backend.Close()
+
+	calls := 0
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host: backend.URL,
+		User: "admin",
+		Credentials: func(context.Context) (string, string, error) {
+			calls++
+			return "admin", "from-vault", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	if session.Password != "" {
+		t.Fatal("expected a callback session not to hold the password")
+	}
+
+	backend.revokeAll()
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("GetRequest after token expiry failed: %v", err)
+	}
+	if calls != 2 || backend.logins != 2 {
+		t.Fatalf("expected the callback to supply both logins, got %d calls and %d logins", calls, backend.logins)
+	}
+
+	_, err = f5os.NewSession(&f5os.F5osConfig{
+		Host: backend.URL,
+		Credentials: func(context.Context) (string, string, error) {
+			return "", "", errors.New("vault sealed")
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "vault sealed") {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+}
+
+// TestUnitCloseSessions verifies that CloseSessions logs out and evicts
+// every cached session and every session created with the cache
+// disabled, and logs the sessions it fails to log out to stderr.
+func TestUnitCloseSessions(t *testing.T) {
+	backend := newLogoutBackend("admin")
+	defer backend.Close()
+	dead := newLogoutBackend("admin")
+	unreachable := newRetrySession(t, dead.URL, nil)
+	dead.Close()
+
+	sessionCacheMu.Lock()
+	saved, savedUncached := sessionCache, uncachedSessions
+	sessionCache = map[string]*f5os.F5os{
+		"a": newRetrySession(t, backend.URL, nil),
+		"b": newRetrySession(t, backend.URL, nil),
+	}
+	uncachedSessions = []*f5os.F5os{unreachable}
+	sessionCacheMu.Unlock()
+	defer func() {
+		sessionCacheMu.Lock()
+		sessionCache, uncachedSessions = saved, savedUncached
+		sessionCacheMu.Unlock()
+	}()
+
+	// The scheme-prefixed host keeps the session out of the cache.
+	resp := testProviderConfigure(t, map[string]tftypes.Value{
+		"host":     tftypes.NewValue(tftypes.String, backend.URL),
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"password": tftypes.NewValue(tftypes.String, "admin"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if err := resp.ResourceData.(*f5os.F5os).WithContext(context.Background()).Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	log.SetOutput(&stderr)
+	defer log.SetOutput(os.Stderr)
+	CloseSessions()
+	if len(sessionCache) != 0 || len(uncachedSessions) != 0 {
+		t.Fatalf("expected no sessions left, got %d cached and %d uncached", len(sessionCache), len(uncachedSessions))
+	}
+	if len(backend.logouts) != 3 {
+		t.Fatalf("expected the cached and uncached sessions to log out, got %v", backend.logouts)
+	}
+	if !strings.Contains(stderr.String(), "[WARN] Failed to log out of F5OS session on "+dead.URL) {
+		t.Fatalf("expected the failed logout to be logged, got %q", stderr.String())
+	}
+}
+
+// TestUnitProviderConfigurePasswordCommand verifies that password_command
+// supplies the login password without the client retaining it.
+func TestUnitProviderConfigurePasswordCommand(t *testing.T) {
+	t.Setenv("F5OS_PASSWORD", "stale-env-password")
+	t.Setenv("F5OS_PASSWORD_COMMAND", "")
+	backend := newLogoutBackend("from-command")
+	defer backend.Close()
+
+	command := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "echo"),
+		tftypes.NewValue(tftypes.String, "from-command"),
+	})
+	resp := testProviderConnect(t, map[string]tftypes.Value{
+		"host":             tftypes.NewValue(tftypes.String, backend.URL),
+		"username":         tftypes.NewValue(tftypes.String, "admin"),
+		"password_command": command,
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	if client.Password != "" || client.Credentials == nil {
+		t.Fatal("expected the client to log in through the command without keeping the password")
+	}
+	if backend.logins != 1 {
+		t.Fatalf("expected one login with the command's password, got %d", backend.logins)
+	}
+}
+
+// TestUnitProviderConfigureUnknownHost verifies that a host not known
+// until apply defers the session instead of failing Configure, and that
+// a request made before then says why it cannot reach the device.
+func TestUnitProviderConfigureUnknownHost(t *testing.T) {
+	resp := testProviderConfigure(t, map[string]tftypes.Value{
+		"host":     tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"password": tftypes.NewValue(tftypes.String, "admin"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	_, err := client.WithContext(context.Background()).GetRequest("/openconfig-vlan:vlans")
+	if err == nil || !strings.Contains(err.Error(), "unknown: host") {
+		t.Fatalf("expected a deferred configuration error naming host, got %v", err)
+	}
+	if client.Platform() != "" {
+		t.Fatalf("expected no platform for a deferred session, got %q", client.Platform())
+	}
+}
+
+// TestConnect_SingleLogin verifies that a lazy session logs in on its
+// first request only, once across concurrent first requests made through
+// WithContext copies.
+func TestConnect_SingleLogin(t *testing.T) {
+	backend := newLogoutBackend("admin")
+	defer backend.Close()
+	session, err := f5os.NewLazySession(&f5os.F5osConfig{Host: backend.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewLazySession failed: %v", err)
+	}
+	if backend.logins != 0 {
+		t.Fatalf("expected no login before the first request, got %d", backend.logins)
+	}
+
+	parallel(8, func(i int) {
+		if _, err := session.WithContext(context.Background()).GetRequest("/openconfig-vlan:vlans"); err != nil {
+			t.Errorf("request %d failed: %v", i, err)
+		}
+	})
+	if backend.logins != 1 {
+		t.Fatalf("expected one login, got %d", backend.logins)
+	}
+}
+
+// TestConnect_FailureRemembered verifies that a rejected login is
+// reported by every later request without logging in again.
+func TestConnect_FailureRemembered(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	var logins int
+	var mu sync.Mutex
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		logins++
+		mu.Unlock()
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer backend.Close()
+	session, err := f5os.NewLazySession(&f5os.F5osConfig{Host: backend.URL, User: "admin", Password: "admin"})
+	if err != nil {
+		t.Fatalf("NewLazySession failed: %v", err)
+	}
+
+	first := session.Connect()
+	var apiErr *f5os.APIError
+	if !errors.As(first, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected a 403 login failure, got %v", first)
+	}
+	if _, err := session.GetRequest("/openconfig-vlan:vlans"); err != first {
+		t.Fatalf("expected the login failure again, got %v", err)
+	}
+	if logins != 1 {
+		t.Fatalf("expected a single login attempt, got %d", logins)
+	}
+}
+
+// newCachingSession connects to url as admin with a FileTokenCache at
+// path, the way a new Terraform run configured with session_cache_dir
+// does.
+func newCachingSession(t *testing.T, url, password, path string) *f5os.F5os {
+	t.Helper()
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:       url,
+		User:       "admin",
+		Password:   password,
+		TokenCache: &f5os.FileTokenCache{Path: path},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	return session
+}
+
+// TestTokenCache_ReusedAcrossRuns verifies that a session with a token
+// cache leaves its token valid on Close and that the next session reuses
+// it without logging in.
+func TestTokenCache_ReusedAcrossRuns(t *testing.T) {
+	backend := newLogoutBackend("admin")
+	defer backend.Close()
+	path := filepath.Join(t.TempDir(), "sessions", "device.token")
+
+	first := newCachingSession(t, backend.URL, "admin", path)
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(backend.logouts) != 0 {
+		t.Fatalf("expected the cached token not to be logged out, got %v", backend.logouts)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the token to be stored: %v", err)
+	}
+	if strings.Contains(string(data), "token-1") || strings.Contains(string(data), `"admin"`) {
+		t.Fatalf("expected the token file to be encrypted, got %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected the token file to be private, got %v", info.Mode().Perm())
+	}
+
+	second := newCachingSession(t, backend.URL, "admin", path)
+	if _, err := second.GetRequest("/openconfig-vlan:vlans"); err != nil {
+		t.Fatalf("GetRequest with the cached token failed: %v", err)
+	}
+	if backend.logins != 1 || second.Token != "token-1" {
+		t.Fatalf("expected the cached token-1 to be reused, got %d logins and token %q", backend.logins, second.Token)
+	}
+}
+
+// TestTokenCache_RejectedOrExpired verifies that a stored token the device
+// no longer accepts, or whose device lifetime has run out, is replaced by
+// a new login.
+func TestTokenCache_RejectedOrExpired(t *testing.T) {
+	backend := newLogoutBackend("admin")
+	defer backend.Close()
+	path := filepath.Join(t.TempDir(), "device.token")
+
+	newCachingSession(t, backend.URL, "admin", path)
+	backend.revokeAll()
+	if session := newCachingSession(t, backend.URL, "admin", path); session.Token != "token-2" || backend.logins != 2 {
+		t.Fatalf("expected a login after the cached token was rejected, got %d logins and token %q", backend.logins, session.Token)
+	}
+
+	// A one-minute lifetime is used up by the expiry margin, so the token
+	// stored by the next login is never handed out.
+	backend.mu.Lock()
+	backend.lifetime = 1
+	backend.mu.Unlock()
+	backend.revokeAll()
+	newCachingSession(t, backend.URL, "admin", path)
+	if session := newCachingSession(t, backend.URL, "admin", path); session.Token != "token-4" || backend.logins != 4 {
+		t.Fatalf("expected a login after the cached token expired, got %d logins and token %q", backend.logins, session.Token)
+	}
+}
+
+// TestFileTokenCache_BoundToCredentials verifies that a stored token is
+// only returned for the credentials it was stored with and before it
+// expires.
+func TestFileTokenCache_BoundToCredentials(t *testing.T) {
+	cache := &f5os.FileTokenCache{Path: filepath.Join(t.TempDir(), "device.token")}
+	if token, err := cache.Load("admin", "admin"); token != "" || err != nil {
+		t.Fatalf("expected no token before one is stored, got %q, %v", token, err)
+	}
+	if err := cache.Store("admin", "admin", "token-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	for _, tc := range []struct {
+		user, password, want string
+	}{
+		{"admin", "admin", "token-1"},
+		{"admin", "changed", ""},
+		{"operator", "admin", ""},
+	} {
+		if token, err := cache.Load(tc.user, tc.password); token != tc.want || err != nil {
+			t.Fatalf("%s/%s: expected %q, got %q, %v", tc.user, tc.password, tc.want, token, err)
+		}
+	}
+
+	if err := cache.Store("admin", "admin", "token-2", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if token, _ := cache.Load("admin", "admin"); token != "" {
+		t.Fatalf("expected an expired token not to be returned, got %q", token)
+	}
+	if err := cache.Remove(); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := cache.Remove(); err != nil {
+		t.Fatalf("Remove of a missing file failed: %v", err)
+	}
+}
+
+// TestUnitProviderConfigureSessionCacheDir verifies that session_cache_dir
+// gives the client a token cache in that directory, named without the
+// password, and that it is ignored for a token session.
+func TestUnitProviderConfigureSessionCacheDir(t *testing.T) {
+	t.Setenv("F5OS_SESSION_CACHE_DIR", "")
+	t.Setenv("F5OS_TOKEN", "")
+	dir := t.TempDir()
+	attrs := map[string]tftypes.Value{
+		"host":              tftypes.NewValue(tftypes.String, "https://f5os.example.com"),
+		"username":          tftypes.NewValue(tftypes.String, "admin"),
+		"password":          tftypes.NewValue(tftypes.String, "admin"),
+		"session_cache_dir": tftypes.NewValue(tftypes.String, dir),
+	}
+	cachePath := func(attrs map[string]tftypes.Value) string {
+		t.Helper()
+		resp := testProviderConfigure(t, attrs)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		cache, ok := resp.ResourceData.(*f5os.F5os).TokenCache.(*f5os.FileTokenCache)
+		if !ok {
+			t.Fatal("expected the client to have a file token cache")
+		}
+		return cache.Path
+	}
+
+	path := cachePath(attrs)
+	if filepath.Dir(path) != dir {
+		t.Fatalf("expected the token file in %s, got %s", dir, path)
+	}
+	attrs["password"] = tftypes.NewValue(tftypes.String, "changed")
+	if other := cachePath(attrs); other != path {
+		t.Fatalf("expected the token file not to depend on the password, got %s and %s", path, other)
+	}
+
+	attrs["auth_token"] = tftypes.NewValue(tftypes.String, "caller-token")
+	resp := testProviderConfigure(t, attrs)
+	if resp.ResourceData.(*f5os.F5os).TokenCache != nil {
+		t.Fatal("expected a token session not to use the token cache")
+	}
+	if w := resp.Diagnostics.Warnings(); len(w) != 1 || w[0].Summary() != "Session cache directory is ignored" {
+		t.Fatalf("expected an ignored warning, got %v", resp.Diagnostics)
+	}
+}
@@ -239,8 +239,8 @@ func TestUnitSetupMock2_0_0Helpers(t *testing.T) {
 		t.Fatalf("failed to create test client against mock: %s", err)
 	}
 	// setupMockPlatformVersion2_0_0 puts a 2.0.0 build string on the client.
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Fatalf("expected mock client to report >= v2.0, got %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Fatalf("expected mock client to report >= v2.0, got %q", client.Version())
 	}
 
 	for _, tc := range cases {
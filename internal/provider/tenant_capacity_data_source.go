@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+var _ datasource.DataSource = &TenantCapacityDataSource{}
+
+func NewTenantCapacityDataSource() datasource.DataSource {
+	return &TenantCapacityDataSource{}
+}
+
+// TenantCapacityDataSource reports the vCPUs, memory and disk of an
+// rSeries appliance, and how much of it deployed tenants use.
+type TenantCapacityDataSource struct {
+	client   *f5ossdk.F5os
+	teemData *TeemData
+}
+
+type TenantNodeCapacityInfo struct {
+	Node        types.Int64 `tfsdk:"node"`
+	TotalVcpus  types.Int64 `tfsdk:"total_vcpus"`
+	UsedVcpus   types.Int64 `tfsdk:"used_vcpus"`
+	FreeVcpus   types.Int64 `tfsdk:"free_vcpus"`
+	TotalMemory types.Int64 `tfsdk:"total_memory"`
+	UsedMemory  types.Int64 `tfsdk:"used_memory"`
+	FreeMemory  types.Int64 `tfsdk:"free_memory"`
+	TotalDisk   types.Int64 `tfsdk:"total_disk"`
+	UsedDisk    types.Int64 `tfsdk:"used_disk"`
+	FreeDisk    types.Int64 `tfsdk:"free_disk"`
+	Tenants     []string    `tfsdk:"tenants"`
+}
+
+type TenantCapacityDataSourceModel struct {
+	Id      types.String             `tfsdk:"id"`
+	Exclude []string                 `tfsdk:"exclude"`
+	Nodes   []TenantNodeCapacityInfo `tfsdk:"nodes"`
+}
+
+func (d *TenantCapacityDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenant_capacity"
+	teemData := &TeemData{}
+	teemData.ProviderName = req.ProviderTypeName
+	teemData.ResourceName = resp.TypeName
+	d.teemData = teemData
+}
+
+func (d *TenantCapacityDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	count := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{Computed: true, MarkdownDescription: description}
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Get the tenant capacity of an rSeries appliance: the vCPUs, memory and disk of the appliance, " +
+			"and how much of it the tenants in the `deployed` running state use.\n\n" +
+			"The totals are the processor threads, memory and disk the appliance reports for its platform. F5OS keeps part of them for itself, " +
+			"so they bound what tenants can use rather than give it exactly. VELOS partitions do not report their capacity, and `nodes` is empty there.\n\n" +
+			"The `f5os_tenant` resource runs the same check at plan time before deploying a tenant.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Unique identifier of this data source",
+			},
+			"exclude": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "Names of tenants whose resources are not counted as used, e.g. a tenant about to be resized.",
+			},
+			"nodes": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Capacity of each node, in order of node number. Empty when the device does not report its capacity.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node":         count("Node number, as used in the tenant `nodes` attribute."),
+						"total_vcpus":  count("Processor threads of the node."),
+						"used_vcpus":   count("vCPUs used by deployed tenants."),
+						"free_vcpus":   count("vCPUs left for new tenants."),
+						"total_memory": count("Memory of the node, in MB."),
+						"used_memory":  count("Memory used by deployed tenants, in MB."),
+						"free_memory":  count("Memory left for new tenants, in MB."),
+						"total_disk":   count("Disk of the node, in GB."),
+						"used_disk":    count("Disk used by deployed tenants, in GB."),
+						"free_disk":    count("Disk left for new tenants, in GB."),
+						"tenants": schema.ListAttribute{
+							ElementType:         types.StringType,
+							Computed:            true,
+							MarkdownDescription: "Names of the deployed tenants on the node.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TenantCapacityDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client, resp.Diagnostics = toF5osProvider(req.ProviderData)
+}
+
+func (d *TenantCapacityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TenantCapacityDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if d.client.WithContext(ctx).Platform() == "Velos Controller" {
+		resp.Diagnostics.AddError("Unsupported platform for data source", "`f5os_tenant_capacity` data source is supported with Velos Partition level (or) rSeries appliance")
+		return
+	}
+	capacity, err := d.client.WithContext(ctx).GetTenantCapacity(data.Exclude...)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Get Tenant Capacity", fmt.Sprintf("Error:%s", err))
+		return
+	}
+	if len(capacity) == 0 {
+		resp.Diagnostics.AddWarning("Tenant Capacity Not Reported", "The device does not report its tenant capacity, as a VELOS partition does not, so nodes is empty.")
+	}
+	data.Nodes = []TenantNodeCapacityInfo{}
+	for _, node := range capacity {
+		tenants := node.Tenants
+		if tenants == nil {
+			tenants = []string{}
+		}
+		data.Nodes = append(data.Nodes, TenantNodeCapacityInfo{
+			Node:        types.Int64Value(int64(node.Node)),
+			TotalVcpus:  types.Int64Value(int64(node.TotalVcpus)),
+			UsedVcpus:   types.Int64Value(int64(node.UsedVcpus)),
+			FreeVcpus:   types.Int64Value(int64(node.FreeVcpus())),
+			TotalMemory: types.Int64Value(int64(node.TotalMemory)),
+			UsedMemory:  types.Int64Value(int64(node.UsedMemory)),
+			FreeMemory:  types.Int64Value(int64(node.FreeMemory())),
+			TotalDisk:   types.Int64Value(int64(node.TotalDisk)),
+			UsedDisk:    types.Int64Value(int64(node.UsedDisk)),
+			FreeDisk:    types.Int64Value(int64(node.FreeDisk())),
+			Tenants:     tenants,
+		})
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/tenant_capacity", d.client.Host))
+	teemData.ResourceName = "f5os_tenant_capacity"
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+// tenantCapacityRead runs Read of f5os_tenant_capacity against an emulated
+// platform with tenant t1 deployed on node 1 and t2 only configured.
+func tenantCapacityRead(t *testing.T, platform f5osemu.Platform, exclude []string) (TenantCapacityDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: platform})
+	if err := server.Load("f5-tenants:tenants", []byte(`{"f5-tenants:tenants":{"tenant":[`+
+		`{"name":"t1","config":{"name":"t1","nodes":[1],"vcpu-cores-per-node":8,"memory":29184,"storage":{"size":76},"running-state":"deployed"}},`+
+		`{"name":"t2","config":{"name":"t2","nodes":[1],"vcpu-cores-per-node":4,"memory":14848,"storage":{"size":76},"running-state":"configured"}}]}}`)); err != nil {
+		t.Fatalf("Load tenants failed: %v", err)
+	}
+	var got TenantCapacityDataSourceModel
+	resp := testDataSourceRead(t, &TenantCapacityDataSource{client: client}, &TenantCapacityDataSourceModel{Id: types.StringNull(), Exclude: exclude}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	return got, resp.Diagnostics
+}
+
+func TestUnitTenantCapacityDataSourceRead(t *testing.T) {
+	got, _ := tenantCapacityRead(t, f5osemu.RSeries, nil)
+	if len(got.Nodes) != 1 {
+		t.Fatalf("expected the appliance as the only node, got %+v", got.Nodes)
+	}
+	node := got.Nodes[0]
+	if node.Node.ValueInt64() != 1 || node.TotalVcpus.ValueInt64() != 26 || node.UsedVcpus.ValueInt64() != 8 || node.FreeVcpus.ValueInt64() != 18 {
+		t.Fatalf("unexpected vCPUs: %+v", node)
+	}
+	if node.TotalMemory.ValueInt64() != 93696 || node.FreeMemory.ValueInt64() != 93696-29184 || node.UsedDisk.ValueInt64() != 76 || node.FreeDisk.ValueInt64() != 824 {
+		t.Fatalf("unexpected memory or disk: %+v", node)
+	}
+	if len(node.Tenants) != 1 || node.Tenants[0] != "t1" {
+		t.Fatalf("expected only the deployed t1, got %v", node.Tenants)
+	}
+}
+
+func TestUnitTenantCapacityDataSourceExclude(t *testing.T) {
+	got, _ := tenantCapacityRead(t, f5osemu.RSeries, []string{"t1"})
+	for _, node := range got.Nodes {
+		if node.UsedVcpus.ValueInt64() != 0 || node.FreeVcpus.ValueInt64() != 26 || len(node.Tenants) != 0 {
+			t.Fatalf("expected t1 not to be counted, got %+v", node)
+		}
+	}
+}
+
+// TestUnitTenantCapacityDataSourceNotReported verifies that a VELOS
+// partition, which does not report its capacity, gives no nodes and a
+// warning.
+func TestUnitTenantCapacityDataSourceNotReported(t *testing.T) {
+	got, diags := tenantCapacityRead(t, f5osemu.VelosPartition, nil)
+	if len(got.Nodes) != 0 || diags.WarningsCount() != 1 || diags.Warnings()[0].Summary() != "Tenant Capacity Not Reported" {
+		t.Fatalf("expected no nodes and a warning, got %+v and %v", got.Nodes, diags)
+	}
+}
@@ -99,10 +99,11 @@ func (d *ImageInfoDataSource) Read(ctx context.Context, req datasource.ReadReque
 	if overallTimeout < 1*time.Second {
 		overallTimeout = 1 * time.Second
 	}
-	timeBefore := time.Now().Add(overallTimeout)
+	start := time.Now()
+	timeBefore := start.Add(overallTimeout)
 	for time.Now().Before(timeBefore) {
 		availableFlag = false
-		imageObj, err := d.client.GetImage(data.ImageName.ValueString())
+		imageObj, err := d.client.WithContext(ctx).GetImage(data.ImageName.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("Unable to Get Image Details", fmt.Sprintf("Error:%s", err))
 			return
@@ -124,7 +125,10 @@ func (d *ImageInfoDataSource) Read(ctx context.Context, req datasource.ReadReque
 		if availableFlag {
 			break
 		}
-		time.Sleep(pollSleep)
+		if err := pauseContext(ctx, pollSleep, fmt.Sprintf("image %q availability", data.ImageName.ValueString()), start, overallTimeout); err != nil {
+			resp.Diagnostics.AddError("Unable to Get Image Details", err.Error())
+			return
+		}
 	}
 	if !availableFlag {
 		resp.Diagnostics.AddError("Unable to Get Image Details", fmt.Sprintf("Get Image: %s failed with error:%s", data.ImageName.ValueString(), "not-present"))
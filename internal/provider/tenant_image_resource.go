@@ -14,7 +14,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -28,6 +27,11 @@ import (
 var _ resource.Resource = &TenantImageResource{}
 var _ resource.ResourceWithImportState = &TenantImageResource{}
 var _ resource.ResourceWithValidateConfig = &TenantImageResource{}
+var _ resource.ResourceWithUpgradeState = &TenantImageResource{}
+
+// tenantImageDefaultWait is how long create waits for the image import
+// when neither the resource nor the provider set a limit.
+const tenantImageDefaultWait = 360 * time.Second
 
 func NewTenantImageResource() resource.Resource {
 	return &TenantImageResource{}
@@ -50,7 +54,7 @@ type TenantImageResourceModel struct {
 	RemotePath     types.String `tfsdk:"remote_path"`
 	RemotePort     types.Int64  `tfsdk:"remote_port"`
 	Insecure       types.Bool   `tfsdk:"insecure"`
-	Timeout        types.Int64  `tfsdk:"timeout"`
+	Timeouts       types.Object `tfsdk:"timeouts"`
 	Id             types.String `tfsdk:"id"`
 	Status         types.String `tfsdk:"status"`
 }
@@ -150,12 +154,6 @@ func (r *TenantImageResource) Schema(ctx context.Context, req resource.SchemaReq
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
-			"timeout": schema.Int64Attribute{
-				MarkdownDescription: "The number of seconds to wait for image import to finish.",
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(360),
-			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Example identifier",
@@ -168,6 +166,10 @@ func (r *TenantImageResource) Schema(ctx context.Context, req resource.SchemaReq
 				MarkdownDescription: "Status of Imported Image",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock("the image import to finish", tenantImageDefaultWait),
+		},
+		Version: 1,
 	}
 }
 
@@ -217,19 +219,24 @@ func (r *TenantImageResource) Create(ctx context.Context, req resource.CreateReq
 
 	// If applicable, this is a great opportunity to initialize any necessary
 	// provider client data and make a call using it.
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_tenant_image` resource is supported with Velos Partition level (or) rSeries appliance")
 		return
 	}
 
-	resp1Byte, getErr := r.client.GetImage(data.ImageName.ValueString())
+	wait, diags := operationTimeout(ctx, data.Timeouts, "create", r.client, tenantImageDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp1Byte, getErr := r.client.WithContext(ctx).GetImage(data.ImageName.ValueString())
 	if getErr != nil {
 		resp.Diagnostics.AddWarning("Client Warning", fmt.Sprintf("Unable to check if image already exists, will attempt import: %s", getErr))
 	}
 
 	if getErr != nil || resp1Byte == nil || len(resp1Byte.TenantImages) == 0 {
 		if data.UploadFromPath.IsNull() {
-			respByte, err := r.importImage(ctx, data)
+			respByte, err := r.importImage(ctx, data, wait)
 			if err != nil {
 				resp.Diagnostics.AddError("[F5OS]Unable to Import Image:", fmt.Sprintf("%s", err))
 				return
@@ -240,7 +247,7 @@ func (r *TenantImageResource) Create(ctx context.Context, req resource.CreateReq
 			}
 
 		} else {
-			respByte, err := r.uploadImage(ctx, data)
+			respByte, err := r.uploadImage(ctx, data, wait)
 			if err != nil {
 				resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("unable to upload image, got error: %s", err))
 				return
@@ -264,7 +271,7 @@ func (r *TenantImageResource) Create(ctx context.Context, req resource.CreateReq
 
 	// For the purposes of this example code, hardcoding a response value to
 	// save into the Terraform state.
-	respByte, err := r.client.GetImage(data.ImageName.ValueString())
+	respByte, err := r.client.WithContext(ctx).GetImage(data.ImageName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Read/Get Imported Image, got error: %s", err))
 		return
@@ -281,8 +288,8 @@ func (r *TenantImageResource) Create(ctx context.Context, req resource.CreateReq
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *TenantImageResource) importImage(ctx context.Context, data *TenantImageResourceModel) ([]byte, error) {
-	timeout := int(data.Timeout.ValueInt64())
+func (r *TenantImageResource) importImage(ctx context.Context, data *TenantImageResourceModel, wait time.Duration) ([]byte, error) {
+	timeout := timeoutSeconds(wait)
 	tflog.Info(ctx, fmt.Sprintf("timeout data :%+v", timeout))
 	importConfig := &f5ossdk.F5ReqTenantImage{}
 	if data.Insecure.ValueBool() {
@@ -308,18 +315,21 @@ func (r *TenantImageResource) importImage(ctx context.Context, data *TenantImage
 	}
 	tflog.Info(ctx, fmt.Sprintf("Create Data: RemoteHost=%s, RemoteFile=%s, LocalFile=%s, Protocol=%s, Username=%s, RemotePort=%d",
 		importConfig.RemoteHost, importConfig.RemoteFile, importConfig.LocalFile, importConfig.Protocol, importConfig.Username, importConfig.RemotePort))
-	return r.client.ImportImage(importConfig, timeout)
+	return r.client.WithContext(ctx).ImportImage(importConfig, timeout)
 }
 
-func (r *TenantImageResource) uploadImage(ctx context.Context, data *TenantImageResourceModel) ([]byte, error) {
-	timeout := int(data.Timeout.ValueInt64())
-	tflog.Info(ctx, fmt.Sprintf("timeout data :%+v", timeout))
+func (r *TenantImageResource) uploadImage(ctx context.Context, data *TenantImageResourceModel, wait time.Duration) ([]byte, error) {
+	tflog.Info(ctx, fmt.Sprintf("timeout data :%+v", wait))
 	imageDir := data.UploadFromPath.ValueString()
 	imageName := data.ImageName.ValueString()
 	filePath := go_path.Join(imageDir, imageName)
 	tflog.Info(ctx, "Uploading image")
-	r.client.ConfigOptions.APICallTimeout = time.Duration(timeout) * time.Second
-	return r.client.UploadImage(filePath)
+	// The upload is a single request, so the limit applies to it rather
+	// than to the provider's api_timeout. Set it on a copy of the session;
+	// the options are shared with every other resource.
+	client := r.client.WithContext(ctx)
+	client.ConfigOptions = &f5ossdk.ConfigOptions{APICallTimeout: wait}
+	return client.UploadImage(filePath)
 }
 
 func (r *TenantImageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -331,10 +341,15 @@ func (r *TenantImageResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "read", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	// If applicable, this is a great opportunity to initialize any necessary
 	// provider client data and make a call using it.
-	respByte, err := r.client.GetImage(data.Id.ValueString())
+	respByte, err := r.client.WithContext(ctx).GetImage(data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Read/Get Imported Image, got error: %s", err))
 		return
@@ -356,7 +371,7 @@ func (r *TenantImageResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	respByte, err := r.client.GetImage(data.Id.ValueString())
+	respByte, err := r.client.WithContext(ctx).GetImage(data.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Read/Get Imported Image, got error: %s", err))
 		return
@@ -378,16 +393,51 @@ func (r *TenantImageResource) Delete(ctx context.Context, req resource.DeleteReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "delete", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 
 	// If applicable, this is a great opportunity to initialize any necessary
 	// provider client data and make a call using it.
-	err := r.client.DeleteTenantImage(data.ImageName.ValueString())
+	err := r.client.WithContext(ctx).DeleteTenantImage(data.ImageName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Delete Imported Image, got error: %s", err))
 		return
 	}
 }
 
+// UpgradeState moves the integer `timeout` of schema version 0 into the
+// `timeouts` block.
+func (r *TenantImageResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	return map[int64]resource.StateUpgrader{
+		0: timeoutsStateUpgrader(tenantImageSchemaV0, schemaResp.Schema, int64(tenantImageDefaultWait/time.Second), "create"),
+	}
+}
+
+// tenantImageSchemaV0 is the shape of schema version 0, kept to read
+// state written by provider releases before 1.14.0.
+var tenantImageSchemaV0 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"image_name":       schema.StringAttribute{Required: true},
+		"local_path":       schema.StringAttribute{Optional: true},
+		"upload_from_path": schema.StringAttribute{Optional: true},
+		"protocol":         schema.StringAttribute{Optional: true},
+		"remote_host":      schema.StringAttribute{Optional: true},
+		"remote_user":      schema.StringAttribute{Optional: true},
+		"remote_password":  schema.StringAttribute{Optional: true, Sensitive: true},
+		"remote_path":      schema.StringAttribute{Optional: true},
+		"remote_port":      schema.Int64Attribute{Optional: true},
+		"insecure":         schema.BoolAttribute{Optional: true, Computed: true},
+		"timeout":          schema.Int64Attribute{Optional: true, Computed: true},
+		"id":               schema.StringAttribute{Computed: true},
+		"status":           schema.StringAttribute{Computed: true},
+	},
+}
+
 func (r *TenantImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// The import ID is the image name, which maps to both "id" and "image_name"
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
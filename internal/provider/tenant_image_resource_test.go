@@ -219,7 +219,7 @@ func TestAccTenantImageCreateTC1Resource(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 			},
 		},
@@ -389,7 +389,7 @@ func TestUnitTenantImageCreateTC4Resource(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 			},
 		},
@@ -712,7 +712,7 @@ func TestAccTenantImageTimeoutNoReplace(t *testing.T) {
 				Config: testAccTenantImageAccTimeoutConfig(360),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "id", testAccImageName),
-					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "timeout", "360"),
+					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "timeouts.create", "360s"),
 					testAccCheckTenantImageExistsOnDevice(testAccImageName),
 				),
 			},
@@ -722,7 +722,7 @@ func TestAccTenantImageTimeoutNoReplace(t *testing.T) {
 				Config: testAccTenantImageAccTimeoutConfig(600),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "id", testAccImageName),
-					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "timeout", "600"),
+					resource.TestCheckResourceAttr("f5os_tenant_image.acc_test", "timeouts.create", "600s"),
 					testAccCheckTenantImageExistsOnDevice(testAccImageName),
 				),
 			},
@@ -835,7 +835,9 @@ resource "f5os_tenant_image" "replace_test" {
   remote_path = %q
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, remotePath)
 }
@@ -848,7 +850,9 @@ resource "f5os_tenant_image" "acc_test" {
   remote_path = %q
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = %d
+  timeouts {
+    create = "%ds"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath, timeout)
 }
@@ -872,7 +876,9 @@ resource "f5os_tenant_image" "field_test" {
   remote_path = %q
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 %s}
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath, extra)
 }
@@ -891,7 +897,9 @@ resource "f5os_tenant_image" "all_fields_test" {
   remote_password = "imagepass"
   remote_port     = 8443
   insecure        = true
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath)
 
@@ -991,7 +999,9 @@ resource "f5os_tenant_image" "test" {
   remote_path = %q
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath)
 
@@ -1004,7 +1014,9 @@ resource "f5os_tenant_image" "test" {
   remote_path = %q
   local_path  = "images"
   insecure    = true
-  timeout     = 900
+  timeouts {
+    create = "900s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath)
 
@@ -1016,7 +1028,9 @@ resource "f5os_tenant_image" "test" {
   remote_path = %q
   local_path  = "images"
   insecure    = true
-  timeout     = 380
+  timeouts {
+    create = "380s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath)
 
@@ -1029,7 +1043,9 @@ resource "f5os_tenant_image" "test" {
   remote_host = %q
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images"
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -1138,7 +1154,9 @@ resource "f5os_tenant_image" "test" {
   remote_user     = "admin"
   remote_password = "secret123"
   remote_port     = 2222
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -1469,7 +1487,7 @@ func TestUnitTenantImageImportSetsImageName(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 			},
 		},
@@ -1646,7 +1664,7 @@ func TestUnitTenantImageReadAfterImportHasCorrectState(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 				// After import + read, verify all computed fields
 				Check: resource.ComposeAggregateTestCheckFunc(
@@ -1668,7 +1686,9 @@ resource "f5os_tenant_image" "test" {
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -1728,7 +1748,9 @@ resource "f5os_tenant_image" "test" {
   local_path  = "images"
   protocol    = "https"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -1748,7 +1770,9 @@ resource "f5os_tenant_image" "http_test" {
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images"
   protocol    = "http"
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `,
 				ExpectError: regexp.MustCompile(`Protocol must be one of`),
@@ -1920,7 +1944,7 @@ func TestAccTenantImageImportStateSetsImageName(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// After import + read, both id and image_name must be
@@ -1961,7 +1985,7 @@ func TestAccTenantImageUpdatePreservesState(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "id", imageName),
 					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "image_name", imageName),
-					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "timeout", "360"),
+					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "timeouts.create", "360s"),
 					resource.TestCheckResourceAttrSet("f5os_tenant_image.import_fix_test", "status"),
 					testAccCheckTenantImageExistsOnDevice(imageName),
 				),
@@ -1973,7 +1997,7 @@ func TestAccTenantImageUpdatePreservesState(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "id", imageName),
 					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "image_name", imageName),
-					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "timeout", "600"),
+					resource.TestCheckResourceAttr("f5os_tenant_image.import_fix_test", "timeouts.create", "600s"),
 					resource.TestCheckResourceAttrSet("f5os_tenant_image.import_fix_test", "status"),
 					testAccCheckTenantImageExistsOnDevice(imageName),
 				),
@@ -2035,7 +2059,9 @@ resource "f5os_tenant_image" "import_fix_test" {
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = %d
+  timeouts {
+    create = "%ds"
+  }
 }
 `, imageName, testAccImageRemoteHost, timeout)
 }
@@ -2054,7 +2080,9 @@ resource "f5os_tenant_image" "test" {
   remote_user     = "admin"
   remote_password = "secret123"
   remote_port     = 2222
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -2068,7 +2096,9 @@ resource "f5os_tenant_image" "test" {
   remote_user     = "admin"
   remote_password = "secret123"
   remote_port     = 3333
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -2082,7 +2112,9 @@ resource "f5os_tenant_image" "test" {
   remote_user     = "operator"
   remote_password = "secret123"
   remote_port     = 2222
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -2096,7 +2128,9 @@ resource "f5os_tenant_image" "test" {
   remote_user     = "admin"
   remote_password = "secret123"
   remote_port     = 2222
-  timeout         = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `
 
@@ -2255,7 +2289,7 @@ func TestAccTenantImageCreateExistingImageSkipsImport(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 			},
 		},
@@ -2270,7 +2304,9 @@ resource "f5os_tenant_image" "existing_test" {
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, imageName, testAccImageRemoteHost)
 }
@@ -3128,7 +3164,9 @@ resource "f5os_tenant_image" "test" {
   remote_path = "v17.1.0.1/daily/previous/VM"
   local_path  = "images"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -3175,7 +3213,9 @@ resource "f5os_tenant_image" "bad_host_test" {
   remote_path = "v17/daily/current/VM"
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 60
+  timeouts {
+    create = "60s"
+  }
 }
 `
 
@@ -3206,7 +3246,9 @@ resource "f5os_tenant_image" "cert_error_test" {
   remote_path = "v17.1.0.1/daily/current/VM"
   local_path  = "images/tenant"
   insecure    = false
-  timeout     = 90
+  timeouts {
+    create = "90s"
+  }
 }
 `, testAccImageRemoteHost)
 
@@ -3273,7 +3315,9 @@ resource "f5os_tenant_image" "import_success_test" {
   remote_path = "v17/dist/release/VM"
   local_path  = "images/tenant"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, imageName, testAccImageRemoteHost)
 }
@@ -3323,7 +3367,7 @@ func TestAccTenantImageInsecureHTTPSImport(t *testing.T) {
 				ImportStateVerifyIgnore: []string{
 					"local_path", "remote_host", "remote_path", "remote_user",
 					"remote_password", "remote_port", "protocol", "insecure",
-					"upload_from_path", "timeout",
+					"upload_from_path", "timeouts",
 				},
 			},
 		},
@@ -3338,7 +3382,9 @@ resource "f5os_tenant_image" "insecure_https_test" {
   local_path  = "images/tenant"
   protocol    = "https"
   insecure    = true
-  timeout     = 360
+  timeouts {
+    create = "360s"
+  }
 }
 `, testAccImageName, testAccImageRemoteHost, testAccImageRemotePath)
 
@@ -3419,7 +3465,9 @@ resource "f5os_tenant_image" "cert_test" {
   local_path  = "images/tenant"
   protocol    = "https"
   insecure    = false
-  timeout     = 90
+  timeouts {
+    create = "90s"
+  }
 }
 `, testAccImageRemoteHost)
 
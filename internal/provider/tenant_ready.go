@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tenantProbeTimeout bounds a single wait_for_ready probe.
+const tenantProbeTimeout = 10 * time.Second
+
+// tenantReadyModel describes the wait_for_ready attribute of f5os_tenant.
+type tenantReadyModel struct {
+	URL            types.String `tfsdk:"url"`
+	TCPPort        types.Int64  `tfsdk:"tcp_port"`
+	ExpectedStatus types.Int64  `tfsdk:"expected_status"`
+	Interval       types.String `tfsdk:"interval"`
+	Timeout        types.String `tfsdk:"timeout"`
+}
+
+// tenantReadyAttrTypes returns the attr.Type map of wait_for_ready.
+func tenantReadyAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"url":             types.StringType,
+		"tcp_port":        types.Int64Type,
+		"expected_status": types.Int64Type,
+		"interval":        types.StringType,
+		"timeout":         types.StringType,
+	}
+}
+
+// tenantReadyAttribute returns the wait_for_ready attribute.
+func tenantReadyAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Wait after create and update until the workload inside a `deployed` tenant answers on its management address, " +
+			"e.g. until the BIG-IP REST API is up. F5OS reports a tenant as running several minutes before that.\n" +
+			"Exactly one of `url` and `tcp_port` must be set. The wait is skipped when `running_state` is `configured`.\n" +
+			"If the tenant does not answer within `timeout`, the apply fails with the result of the last probe; a tenant that was just created is then marked tainted.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL requested with `GET` until it returns `expected_status`, e.g. `https://10.1.1.10/mgmt/tm/sys/ready`. " +
+					"A path such as `/mgmt/tm/sys/ready` is requested over HTTPS on `mgmt_ip`.\n" +
+					"The request carries no credentials and the server certificate is not verified.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("tcp_port")),
+				},
+			},
+			"tcp_port": schema.Int64Attribute{
+				MarkdownDescription: "TCP port on `mgmt_ip` that must accept a connection, e.g. `443`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"expected_status": schema.Int64Attribute{
+				MarkdownDescription: "HTTP status `url` must return. Default is `200`.\n" +
+					"The BIG-IP REST API answers requests without credentials with `401` once it is up.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(http.StatusOK),
+				Validators: []validator.Int64{
+					int64validator.Between(100, 599),
+					int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("url")),
+				},
+			},
+			"interval": schema.StringAttribute{
+				MarkdownDescription: "Time between probes. When unset, the provider's `poll_interval` is used, and `10s` if that is unset too.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for the tenant to answer, in addition to the `timeouts` for the tenant to be deployed. Default is `10m`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("10m"),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+		},
+	}
+}
+
+// waitForReady probes the tenant as wait_for_ready describes until it
+// answers. It returns nil when wait_for_ready is not set or the tenant is
+// not deployed, and otherwise an error with the result of the last probe.
+func (r *TenantResource) waitForReady(ctx context.Context, data *TenantResourceModel) error {
+	if data.WaitForReady.IsNull() || data.WaitForReady.IsUnknown() || data.RunningState.ValueString() != "deployed" {
+		return nil
+	}
+	var ready tenantReadyModel
+	if diags := data.WaitForReady.As(ctx, &ready, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("failed to read wait_for_ready: %v", diags)
+	}
+	// The values are checked by durationValidator.
+	timeout, _ := time.ParseDuration(ready.Timeout.ValueString())
+	interval := resourcePollInterval(ready.Interval, r.client, 10*time.Second)
+	target := tenantProbeTarget(ready, data.MgmtIP.ValueString())
+
+	start := time.Now()
+	last := ""
+	for {
+		ok, result := ready.probe(ctx, target)
+		tflog.Debug(ctx, "Tenant readiness probe", map[string]interface{}{"tenant": data.Name.ValueString(), "target": target, "result": result})
+		if ok {
+			return nil
+		}
+		last = result
+		if time.Since(start)+interval > timeout {
+			return fmt.Errorf("tenant %q did not answer on %s within %s. Last probe: %s", data.Name.ValueString(), target, timeout, last)
+		}
+		if err := pauseContext(ctx, interval, fmt.Sprintf("readiness probe of tenant %q on %s", data.Name.ValueString(), target), start, timeout); err != nil {
+			return fmt.Errorf("%w. Last probe: %s", err, last)
+		}
+	}
+}
+
+// tenantProbeTarget returns the URL or host:port that ready probes on a
+// tenant with management address mgmtIP.
+func tenantProbeTarget(ready tenantReadyModel, mgmtIP string) string {
+	if ready.URL.IsNull() {
+		return net.JoinHostPort(mgmtIP, strconv.FormatInt(ready.TCPPort.ValueInt64(), 10))
+	}
+	target := ready.URL.ValueString()
+	if strings.HasPrefix(target, "/") {
+		host := mgmtIP
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		target = "https://" + host + target
+	}
+	return target
+}
+
+// probe makes one attempt to reach target and reports whether the tenant
+// answered as expected, with a description of the result.
+func (m tenantReadyModel) probe(ctx context.Context, target string) (bool, string) {
+	ctx, cancel := context.WithTimeout(ctx, tenantProbeTimeout)
+	defer cancel()
+	if m.URL.IsNull() {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", target)
+		if err != nil {
+			return false, err.Error()
+		}
+		_ = conn.Close()
+		return true, "connected"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			// The probe sends no credentials, and tenants start with a
+			// self-signed certificate.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	defer client.CloseIdleConnections()
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err.Error()
+	}
+	defer resp.Body.Close()
+	if int64(resp.StatusCode) == m.ExpectedStatus.ValueInt64() {
+		return true, fmt.Sprintf("HTTP %s", resp.Status)
+	}
+	result := fmt.Sprintf("HTTP %s, expected %d", resp.Status, m.ExpectedStatus.ValueInt64())
+	if body, _ := io.ReadAll(io.LimitReader(resp.Body, 256)); len(strings.TrimSpace(string(body))) > 0 {
+		result += ": " + strings.TrimSpace(string(body))
+	}
+	return false, result
+}
@@ -2,7 +2,10 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,9 +13,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -22,6 +27,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/provider/attribute_plan_modifier"
 )
 
 // var (
@@ -31,6 +37,31 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TenantResource{}
 var _ resource.ResourceWithImportState = &TenantResource{}
+var _ resource.ResourceWithUpgradeState = &TenantResource{}
+var _ resource.ResourceWithValidateConfig = &TenantResource{}
+var _ resource.ResourceWithModifyPlan = &TenantResource{}
+
+// tenantDefaultWait is how long create and update wait for the tenant to
+// be deployed when neither the resource nor the provider set a limit.
+const tenantDefaultWait = 360 * time.Second
+
+// tenantErrorAttributes maps tenant RESTCONF nodes to the attributes that
+// set them, so device errors are reported against the offending field.
+var tenantErrorAttributes = restconfAttributes(map[string]string{
+	"image":                  "image_name",
+	"target-image":           "image_name",
+	"vcpu-cores-per-node":    "cpu_cores",
+	"prefix-length":          "mgmt_prefix",
+	"gateway":                "mgmt_gateway",
+	"storage/size":           "virtual_disk_size",
+	"storage/location":       "storage_location",
+	"appliance-mode/enabled": "appliance_mode",
+}, "name", "deployment_file", "type", "mac_block_size", "dag_ipv6_prefix_length", "running_state",
+	"mgmt_ip", "cryptos", "nodes", "max_nodes", "vlans", "memory", "virtual_wires", "mac_ndi_set",
+	"reserved_cpus", "trust_mode", "ha_state", "floating_address")
+
+// tenantNextAttributes are the attributes only BIG-IP Next tenants take.
+var tenantNextAttributes = []string{"mac_ndi_set", "ha_state", "floating_address"}
 
 func NewTenantResource() resource.Resource {
 	return &TenantResource{}
@@ -47,6 +78,7 @@ type TenantResourceModel struct {
 	Name                types.String `tfsdk:"name"`
 	DeploymentFile      types.String `tfsdk:"deployment_file"`
 	ImageName           types.String `tfsdk:"image_name"`
+	RollbackOnUpgrade   types.Bool   `tfsdk:"rollback_on_upgrade_failure"`
 	Cryptos             types.String `tfsdk:"cryptos"`
 	Type                types.String `tfsdk:"type"`
 	RunningState        types.String `tfsdk:"running_state"`
@@ -63,12 +95,35 @@ type TenantResourceModel struct {
 	ClusteringAsService types.Bool   `tfsdk:"clustering_as_service"`
 	MacBlockSize        types.String `tfsdk:"mac_block_size"`
 	DagIpv6prefixLength types.Int64  `tfsdk:"dag_ipv6_prefix_length"`
-	Timeout             types.Int64  `tfsdk:"timeout"`
+	Timeouts            types.Object `tfsdk:"timeouts"`
 	VirtualdiskSize     types.Int64  `tfsdk:"virtual_disk_size"`
 	Memory              types.Int64  `tfsdk:"memory"`
+	ApplianceMode       types.Bool   `tfsdk:"appliance_mode"`
+	TrustMode           types.Bool   `tfsdk:"trust_mode"`
+	VirtualWires        types.Set    `tfsdk:"virtual_wires"`
+	MacNdiSet           types.Set    `tfsdk:"mac_ndi_set"`
+	ReservedCpus        types.String `tfsdk:"reserved_cpus"`
+	StorageLocation     types.String `tfsdk:"storage_location"`
+	HaState             types.String `tfsdk:"ha_state"`
+	FloatingAddress     types.String `tfsdk:"floating_address"`
+	WaitForReady        types.Object `tfsdk:"wait_for_ready"`
 	Id                  types.String `tfsdk:"id"`
 }
 
+// TenantMacNdiModel describes an entry of mac_ndi_set.
+type TenantMacNdiModel struct {
+	Ndi types.String `tfsdk:"ndi"`
+	Mac types.String `tfsdk:"mac"`
+}
+
+// tenantMacNdiAttrTypes returns the attr.Type map of a mac_ndi_set entry.
+func tenantMacNdiAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"ndi": types.StringType,
+		"mac": types.StringType,
+	}
+}
+
 func (r *TenantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_tenant"
 }
@@ -78,7 +133,8 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Resource used for Manage F5OS tenant on chassis partition/rSeries Appliance\n\n" +
 			"~> **NOTE** `f5os_tenant` resource is used with chassis partition/rSeries appliance, More info on [Tenant](https://techdocs.f5.com/en-us/velos-1-5-0/velos-systems-administration-configuration/title-tenant-management.html#title-tenant-management)." +
-			"\nProvider `f5os` credentials will be chassis partition/rSeries appliance `host`,`username` and `password`",
+			"\nProvider `f5os` credentials will be chassis partition/rSeries appliance `host`,`username` and `password`\n\n" +
+			"On rSeries, a tenant with `running_state` `deployed` is checked at plan time against the vCPUs, memory and disk the appliance has left, as reported by the `f5os_tenant_capacity` data source. The check is skipped with a warning when the device does not report its capacity, as VELOS partitions do not.",
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
@@ -89,11 +145,12 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"image_name": schema.StringAttribute{
-				MarkdownDescription: "Name of the tenant image to be used.\nRequired for create operations",
+				MarkdownDescription: "Name of the tenant image to be used.\nRequired for create operations.\nChanging it upgrades the tenant in place: a deployed tenant is moved to `configured`, upgraded to the new image and deployed again.",
 				Required:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+			},
+			"rollback_on_upgrade_failure": schema.BoolAttribute{
+				MarkdownDescription: "Whether a failed in-place upgrade started by changing `image_name` returns the tenant to its previous image and running state.\nDefault is `false`.",
+				Optional:            true,
 			},
 			"deployment_file": schema.StringAttribute{
 				MarkdownDescription: "Deployment file used for BIG-IP-Next .\nRequired for if `type` is `BIG-IP-Next`.",
@@ -185,12 +242,6 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:            true,
 				ElementType:         types.Int64Type,
 			},
-			"timeout": schema.Int64Attribute{
-				MarkdownDescription: "The number of seconds to wait for image import to finish.",
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(360),
-			},
 			"virtual_disk_size": schema.Int64Attribute{
 				MarkdownDescription: "Minimum virtual disk size required for Tenant deployment",
 				Required:            true,
@@ -200,6 +251,84 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:            true,
 				Computed:            true,
 			},
+			"appliance_mode": schema.BoolAttribute{
+				MarkdownDescription: "Whether appliance mode is enabled on the tenant, which disables root and bash access.\nThe device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"trust_mode": schema.BoolAttribute{
+				MarkdownDescription: "Whether the tenant runs in trust mode.\nSupported on F5OS 1.7.0 and later. The device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"virtual_wires": schema.SetAttribute{
+				MarkdownDescription: "Names of the virtual wires the tenant is attached to.\nSupported on rSeries appliances whose F5OS implements the `f5-tenant-vwire` module. The device's setting is kept when not configured; set `[]` to detach the tenant from every virtual wire.",
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					attribute_plan_modifier.SetUseStateForUnknown(),
+				},
+			},
+			"mac_ndi_set": schema.SetNestedAttribute{
+				MarkdownDescription: "MAC addresses assigned to the network data interfaces (NDIs) of a BIG-IP Next tenant.\nSupported on F5OS 1.7.0 and later with `type` `BIG-IP-Next`. The device's setting is kept when not configured; set `[]` to remove every assignment.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Set{
+					attribute_plan_modifier.SetUseStateForUnknown(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ndi": schema.StringAttribute{
+							MarkdownDescription: "Name of the network data interface.",
+							Required:            true,
+						},
+						"mac": schema.StringAttribute{
+							MarkdownDescription: "MAC address assigned to the interface.",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"reserved_cpus": schema.StringAttribute{
+				MarkdownDescription: "CPUs reserved for the tenant, in the format of the device's `reserved-cpus` setting.\nSupported on F5OS 1.7.0 and later. The device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"storage_location": schema.StringAttribute{
+				MarkdownDescription: "Location of the tenant's virtual disk.\nThe device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ha_state": schema.StringAttribute{
+				MarkdownDescription: "High availability state of a BIG-IP Next tenant.\nSupported on F5OS 1.7.0 and later with `type` `BIG-IP-Next`. The device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"floating_address": schema.StringAttribute{
+				MarkdownDescription: "Floating management address shared by a BIG-IP Next tenant's high availability pair.\nSupported on F5OS 1.7.0 and later with `type` `BIG-IP-Next`. The device's setting is kept when not configured.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"wait_for_ready": tenantReadyAttribute(),
 			"status": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Tenant status",
@@ -224,6 +353,10 @@ func (r *TenantResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock("the tenant to be deployed", tenantDefaultWait),
+		},
+		Version: 1,
 	}
 }
 
@@ -234,6 +367,147 @@ func (r *TenantResource) Configure(ctx context.Context, req resource.ConfigureRe
 	r.teemData = teemData
 }
 
+// ValidateConfig rejects attributes the device does not support, once
+// the provider is configured; see attributeRequirements. It also rejects
+// BIG-IP Next attributes on other tenant types.
+func (r *TenantResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var tenantType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &tenantType)...)
+	if !tenantType.IsUnknown() && tenantType.ValueString() != "BIG-IP-Next" {
+		for _, name := range tenantNextAttributes {
+			if attributeSet(req.Config, name) {
+				resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid Attribute Combination",
+					fmt.Sprintf("%s is only supported for tenants of type BIG-IP-Next. Set type to BIG-IP-Next or remove %s.", name, name))
+			}
+		}
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_tenant", req.Config, &resp.Diagnostics)
+}
+
+// ModifyPlan rejects attributes the device does not support at plan
+// time, see attributeRequirements, and tenants that do not fit the
+// device, see checkTenantCapacity.
+func (r *TenantResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	validateAttributeSupport(ctx, r.client, "f5os_tenant", req.Config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.checkTenantCapacity(ctx, req, resp)
+}
+
+// checkTenantCapacity fails the plan when a tenant to be deployed does not
+// fit on its nodes next to the tenants already deployed there, with the
+// free and total vCPUs, memory and disk of each node. The tenant itself,
+// and the one it replaces, are not counted as deployed.
+//
+// The check is skipped when the sizing is not known yet and when an
+// update does not change it. It is skipped with a warning when the device
+// cannot be reached or does not report its capacity; the device still
+// rejects the deployment then. The capacity is an upper bound, see
+// GetTenantCapacity, so a tenant that passes may still not fit.
+func (r *TenantResource) checkTenantCapacity(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+	var plan TenantResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.RunningState.ValueString() != "deployed" ||
+		plan.Name.IsUnknown() || plan.CpuCores.IsUnknown() || plan.Nodes.IsUnknown() || plan.VirtualdiskSize.IsUnknown() {
+		return
+	}
+	exclude := []string{plan.Name.ValueString()}
+	if !req.State.Raw.IsNull() {
+		var state TenantResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if plan.Name.Equal(state.Name) && plan.RunningState.Equal(state.RunningState) && plan.CpuCores.Equal(state.CpuCores) &&
+			plan.Memory.Equal(state.Memory) && plan.Nodes.Equal(state.Nodes) && plan.VirtualdiskSize.Equal(state.VirtualdiskSize) {
+			return
+		}
+		exclude = append(exclude, state.Name.ValueString())
+	}
+
+	notChecked := func(reason string) {
+		resp.Diagnostics.AddWarning("Tenant Capacity Not Checked",
+			fmt.Sprintf("Whether tenant %s fits on its nodes is only known once it is deployed: %s.", plan.Name.ValueString(), reason))
+	}
+	client := r.client.WithContext(ctx)
+	if err := client.Connect(); err != nil {
+		notChecked(fmt.Sprintf("reading the device capacity failed: %s", err))
+		return
+	}
+	capacity, err := client.GetTenantCapacity(exclude...)
+	if err != nil {
+		notChecked(fmt.Sprintf("reading the device capacity failed: %s", err))
+		return
+	}
+	if len(capacity) == 0 {
+		notChecked("the device does not report its tenant capacity, as a VELOS partition does not")
+		return
+	}
+	var nodes []int64
+	resp.Diagnostics.Append(plan.Nodes.ElementsAs(ctx, &nodes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	vcpus := int(plan.CpuCores.ValueInt64())
+	memory := calculateMemory(&plan, client.Platform())
+	disk := int(plan.VirtualdiskSize.ValueInt64())
+
+	byNode := make(map[int64]f5ossdk.TenantNodeCapacity, len(capacity))
+	for _, node := range capacity {
+		byNode[int64(node.Node)] = node
+	}
+	var lines []string
+	var short path.Path
+	fits := true
+	for _, n := range nodes {
+		node, ok := byNode[n]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("node %d: does not host tenants", n))
+			if fits {
+				short = path.Root("nodes")
+			}
+			fits = false
+			continue
+		}
+		var lacking []string
+		if vcpus > node.FreeVcpus() {
+			lacking = append(lacking, "vCPUs")
+		}
+		if memory > node.FreeMemory() {
+			lacking = append(lacking, "memory")
+		}
+		if disk > node.FreeDisk() {
+			lacking = append(lacking, "disk")
+		}
+		line := fmt.Sprintf("node %d: %d of %d vCPUs, %d of %d MB memory and %d of %d GB disk free",
+			n, node.FreeVcpus(), node.TotalVcpus, node.FreeMemory(), node.TotalMemory, node.FreeDisk(), node.TotalDisk)
+		if len(node.Tenants) > 0 {
+			line += fmt.Sprintf(" (deployed: %s)", strings.Join(node.Tenants, ", "))
+		}
+		if len(lacking) > 0 {
+			line += "; not enough " + strings.Join(lacking, ", ")
+			if fits {
+				short = path.Root(map[string]string{"vCPUs": "cpu_cores", "memory": "memory", "disk": "virtual_disk_size"}[lacking[0]])
+			}
+			fits = false
+		}
+		lines = append(lines, line)
+	}
+	if fits {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(short, "Insufficient Tenant Capacity",
+		fmt.Sprintf("Tenant %q needs %d vCPUs, %d MB of memory and %d GB of disk on each of its nodes, which the device cannot provide:\n\n%s",
+			plan.Name.ValueString(), vcpus, memory, disk, strings.Join(lines, "\n")))
+}
+
 func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *TenantResourceModel
 
@@ -244,7 +518,7 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("[CREATE] Tenant:%+v", data.Name.ValueString()))
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Unsupported platform for resource", "`f5os_tenant` resource is supported with Velos Partition level (or) rSeries appliance")
 		return
 	}
@@ -255,7 +529,7 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 	}
 	stop := r.client.F5OsKeepAlive(15 * time.Second)
-	imageObj, err := r.client.GetImage(data.ImageName.ValueString())
+	imageObj, err := r.client.WithContext(ctx).GetImage(data.ImageName.ValueString())
 	if err != nil {
 		stop <- true
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err), "")
@@ -274,6 +548,10 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	tenantConfig := r.getTenantCreateConfig(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		stop <- true
+		return
+	}
 
 	if data.Type.ValueString() == "BIG-IP-Next" {
 		tenantConfig.F5TenantsTenant[0].Config.DeploymentFile = data.DeploymentFile.ValueString()
@@ -284,19 +562,24 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 	teemInfo := make(map[string]any)
 	teemInfo["teemData"] = r.teemData
 	r.client.Metadata = teemInfo
-	tflog.Info(ctx, fmt.Sprintf("Timeout :%+v", int(data.Timeout.ValueInt64())))
-	respByte, err := r.client.CreateTenant(tenantConfig, int(data.Timeout.ValueInt64()))
+	wait, diags := operationTimeout(ctx, data.Timeouts, "create", r.client, tenantDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		stop <- true
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("Timeout :%s", wait))
+	respByte, err := r.client.WithContext(ctx).CreateTenant(tenantConfig, timeoutSeconds(wait))
 	if err != nil {
-		resp.Diagnostics.AddError(fmt.Sprintf("%v", err.Error()), "")
-		if strings.Contains(err.Error(), "400 Bad Request") {
+		addClientErrorDiagnostic(&resp.Diagnostics, tenantErrorAttributes, err, fmt.Sprintf("%v", err.Error()), "")
+		// A rejected request, or a tenant that already exists, left
+		// nothing behind to roll back.
+		var apiErr *f5ossdk.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadRequest || apiErr.HasTag("data-exists")) {
 			stop <- true
 			return
 		}
-		if strings.Contains(err.Error(), "object already exists") {
-			stop <- true
-			return
-		}
-		_ = r.client.DeleteTenant(data.Name.ValueString())
+		// Roll back even if the failure was a cancellation.
+		_ = r.client.WithContext(context.WithoutCancel(ctx)).DeleteTenant(data.Name.ValueString())
 		stop <- true
 		return
 	}
@@ -305,7 +588,7 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 	// save into the Terraform state.
 	data.Id = types.StringValue(data.Name.ValueString())
 
-	respByte2, err := r.client.GetTenant(data.Name.ValueString())
+	respByte2, err := r.client.WithContext(ctx).GetTenant(data.Name.ValueString())
 	if err != nil {
 		stop <- true
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err.Error()), "")
@@ -315,6 +598,10 @@ func (r *TenantResource) Create(ctx context.Context, req resource.CreateRequest,
 	tflog.Info(ctx, fmt.Sprintf("get tenantConfig :%+v", respByte2))
 	r.tenantResourceModeltoState(ctx, respByte2, data)
 	// mutex.Unlock()
+	// A tenant that does not answer is still saved, and tainted.
+	if err := r.waitForReady(ctx, data); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_ready"), "Tenant Not Ready", err.Error())
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -329,9 +616,14 @@ func (r *TenantResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "read", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 	// respByte, err := r.client.GetTenant(data.Name.ValueString())
 	stop := r.client.F5OsKeepAlive(15 * time.Second)
-	respByte, err := r.client.GetTenant(data.Id.ValueString())
+	respByte, err := r.client.WithContext(ctx).GetTenant(data.Id.ValueString())
 	if err != nil {
 		stop <- true
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err.Error()), "")
@@ -350,26 +642,54 @@ func (r *TenantResource) Update(ctx context.Context, req resource.UpdateRequest,
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state *TenantResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	tenantConfig := r.getTenantUpdateConfig(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	if data.Type.ValueString() == "BIG-IP-Next" {
 		tenantConfig.F5TenantsTenants.Tenant[0].Config.DeploymentFile = data.DeploymentFile.ValueString()
 	}
 	tflog.Info(ctx, fmt.Sprintf("[Update] tenantConfig :%+v", tenantConfig))
 	// mutex.Lock()
+	wait, diags := operationTimeout(ctx, data.Timeouts, "update", r.client, tenantDefaultWait)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 	stop := r.client.F5OsKeepAlive(15 * time.Second)
-	respByte, err := r.client.UpdateTenant(tenantConfig, int(data.Timeout.ValueInt64()))
+	if !data.ImageName.Equal(state.ImageName) {
+		upgrade := &f5ossdk.TenantUpgrade{
+			Name:     data.Name.ValueString(),
+			Image:    data.ImageName.ValueString(),
+			Rollback: data.RollbackOnUpgrade.ValueBool(),
+		}
+		if data.Type.ValueString() == "BIG-IP-Next" {
+			upgrade.DeploymentFile = data.DeploymentFile.ValueString()
+		}
+		tflog.Info(ctx, fmt.Sprintf("[Update] upgrading tenant %s from %s to %s", upgrade.Name, state.ImageName.ValueString(), upgrade.Image))
+		if err := r.client.WithContext(ctx).UpgradeTenant(upgrade, timeoutSeconds(wait)); err != nil {
+			stop <- true
+			r.tenantUpgradeFailed(ctx, err, state, resp)
+			return
+		}
+	}
+	respByte, err := r.client.WithContext(ctx).UpdateTenant(tenantConfig, timeoutSeconds(wait))
 	if err != nil {
 		stop <- true
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Tenant Deploy failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, tenantErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Tenant Deploy failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("[Update] tenantConfig resp :%+v", string(respByte)))
 
-	respByte2, err := r.client.GetTenant(data.Name.ValueString())
+	respByte2, err := r.client.WithContext(ctx).GetTenant(data.Name.ValueString())
 	if err != nil {
 		stop <- true
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err.Error()), "")
@@ -378,11 +698,34 @@ func (r *TenantResource) Update(ctx context.Context, req resource.UpdateRequest,
 	stop <- true
 	r.tenantResourceModeltoState(ctx, respByte2, data)
 	tflog.Info(ctx, fmt.Sprintf("Updated State:%+v", data))
+	if err := r.waitForReady(ctx, data); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_ready"), "Tenant Not Ready", err.Error())
+	}
 	// mutex.Unlock()
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// tenantUpgradeFailed reports a failed in-place upgrade against
+// image_name, and records the image the tenant was left on so the next
+// plan retries the upgrade.
+func (r *TenantResource) tenantUpgradeFailed(ctx context.Context, err error, state *TenantResourceModel, resp *resource.UpdateResponse) {
+	var upgradeErr *f5ossdk.TenantUpgradeError
+	switch {
+	case !errors.As(err, &upgradeErr):
+		addClientErrorDiagnostic(&resp.Diagnostics, tenantErrorAttributes, err, "Tenant Upgrade Failed", err.Error())
+	case upgradeErr.RolledBack:
+		resp.Diagnostics.AddAttributeError(path.Root("image_name"), "Tenant Upgrade Failed", err.Error())
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root("image_name"), "Tenant Upgrade Failed",
+			err.Error()+"\n\nThe tenant may have been left in the configured running state. Fix the cause and apply again, or set `image_name` back to "+upgradeErr.PreviousImage+".")
+	}
+	if tenant, err := r.client.WithContext(context.WithoutCancel(ctx)).GetTenant(state.Name.ValueString()); err == nil {
+		r.tenantResourceModeltoState(ctx, tenant, state)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
 func (r *TenantResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data *TenantResourceModel
 
@@ -392,8 +735,13 @@ func (r *TenantResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel, diags := withOperationTimeout(ctx, data.Timeouts, "delete", r.client, 0)
+	defer cancel()
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
 	stop := r.client.F5OsKeepAlive(15 * time.Second)
-	err := r.client.DeleteTenant(data.Name.ValueString())
+	err := r.client.WithContext(ctx).DeleteTenant(data.Name.ValueString())
 	stop <- true
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err.Error()), "")
@@ -401,6 +749,46 @@ func (r *TenantResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 }
 
+// UpgradeState moves the integer `timeout` of schema version 0 into the
+// `timeouts` block.
+func (r *TenantResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	return map[int64]resource.StateUpgrader{
+		0: timeoutsStateUpgrader(tenantSchemaV0, schemaResp.Schema, int64(tenantDefaultWait/time.Second), "create", "update"),
+	}
+}
+
+// tenantSchemaV0 is the shape of schema version 0, kept to read state
+// written by provider releases before 1.14.0.
+var tenantSchemaV0 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"name":                   schema.StringAttribute{Required: true},
+		"image_name":             schema.StringAttribute{Required: true},
+		"deployment_file":        schema.StringAttribute{Optional: true, Computed: true},
+		"type":                   schema.StringAttribute{Optional: true, Computed: true},
+		"mac_block_size":         schema.StringAttribute{Optional: true, Computed: true},
+		"dag_ipv6_prefix_length": schema.Int64Attribute{Optional: true, Computed: true},
+		"cpu_cores":              schema.Int64Attribute{Required: true},
+		"running_state":          schema.StringAttribute{Optional: true, Computed: true},
+		"mgmt_ip":                schema.StringAttribute{Required: true},
+		"mgmt_gateway":           schema.StringAttribute{Required: true},
+		"mgmt_prefix":            schema.Int64Attribute{Required: true},
+		"cryptos":                schema.StringAttribute{Optional: true, Computed: true},
+		"nodes":                  schema.ListAttribute{Optional: true, Computed: true, ElementType: types.Int64Type},
+		"max_nodes":              schema.Int64Attribute{Optional: true, Computed: true},
+		"vlans":                  schema.ListAttribute{Optional: true, ElementType: types.Int64Type},
+		"timeout":                schema.Int64Attribute{Optional: true, Computed: true},
+		"virtual_disk_size":      schema.Int64Attribute{Required: true},
+		"memory":                 schema.Int64Attribute{Optional: true, Computed: true},
+		"status":                 schema.StringAttribute{Computed: true},
+		"mgmt_vlan":              schema.Int64Attribute{Computed: true},
+		"mgmt_vlan_accessible":   schema.BoolAttribute{Computed: true},
+		"clustering_as_service":  schema.BoolAttribute{Computed: true},
+		"id":                     schema.StringAttribute{Computed: true},
+	},
+}
+
 func (r *TenantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -493,6 +881,7 @@ func (r *TenantResource) tenantResourceModeltoState(ctx context.Context, respDat
 	default:
 		data.MaxNodes = types.Int64Null()
 	}
+	r.tenantAdvancedConfigToState(ctx, respData, data)
 	data.MgmtVlan = types.Int64Value(int64(respData.F5TenantsTenant[0].State.MgmtVlan))
 	data.MgmtVlanAccessible = types.BoolValue(respData.F5TenantsTenant[0].State.MgmtVlanAccessible)
 	data.ClusteringAsService = types.BoolValue(respData.F5TenantsTenant[0].State.FeatureFlags.ClusteringAsService)
@@ -521,18 +910,19 @@ func (r *TenantResource) getTenantCreateConfig(ctx context.Context, req resource
 	// 	tenantSubbj.Config.MacData.F5TenantL2InlineMacBlockSize = data.MacBlockSize.ValueString()
 	// }
 	// tenantSubbj.Config.MacData.F5TenantL2InlineMacBlockSize = data.MacBlockSize.ValueString()
-	tenantSubbj.Config.Memory = calculateMemory(data, r.client.PlatformType)
+	tenantSubbj.Config.Memory = calculateMemory(data, r.client.WithContext(ctx).Platform())
 	data.Vlans.ElementsAs(ctx, &tenantSubbj.Config.Vlans, false)
 	tenantSubbj.Config.RunningState = data.RunningState.ValueString()
 	tenantSubbj.Config.Cryptos = data.Cryptos.ValueString()
 	data.Nodes.ElementsAs(ctx, &tenantSubbj.Config.Nodes, false)
 	tenantSubbj.Config.Storage.Size = int(data.VirtualdiskSize.ValueInt64())
-	// max-nodes was introduced in F5OS 2.0.0. Only send it when the device
-	// supports it and the user supplied a value, otherwise older devices
-	// would reject the unknown field.
-	if platformVersionAtLeast(r.client.PlatformVersion, "v2.0") && !data.MaxNodes.IsNull() && !data.MaxNodes.IsUnknown() {
+	// max-nodes was introduced in F5OS 2.0.0; older devices would reject
+	// the unknown field.
+	if !data.MaxNodes.IsNull() && !data.MaxNodes.IsUnknown() {
+		checkAttributeSupport(ctx, r.client, "f5os_tenant", &resp.Diagnostics, "max_nodes")
 		tenantSubbj.Config.MaxNodes = int(data.MaxNodes.ValueInt64())
 	}
+	resp.Diagnostics.Append(tenantAdvancedConfig(ctx, data, &tenantSubbj)...)
 
 	tenantConfig := new(f5ossdk.F5ReqTenants)
 	tenantConfig.F5TenantsTenant = append(tenantConfig.F5TenantsTenant, tenantSubbj)
@@ -555,18 +945,19 @@ func (r *TenantResource) getTenantUpdateConfig(ctx context.Context, req resource
 	tenantSubbj.Config.VcpuCoresPerNode = int(data.CpuCores.ValueInt64())
 	tenantSubbj.Config.DagIpv6PrefixLength = int(data.DagIpv6prefixLength.ValueInt64())
 	tenantSubbj.Config.MacData.F5TenantL2InlineMacBlockSize = data.MacBlockSize.ValueString()
-	tenantSubbj.Config.Memory = calculateMemory(data, r.client.PlatformType)
+	tenantSubbj.Config.Memory = calculateMemory(data, r.client.WithContext(ctx).Platform())
 	data.Nodes.ElementsAs(ctx, &tenantSubbj.Config.Nodes, false)
 	data.Vlans.ElementsAs(ctx, &tenantSubbj.Config.Vlans, false)
 	tenantSubbj.Config.RunningState = data.RunningState.ValueString()
 	tenantSubbj.Config.Cryptos = data.Cryptos.ValueString()
 	tenantSubbj.Config.Storage.Size = int(data.VirtualdiskSize.ValueInt64())
-	// max-nodes was introduced in F5OS 2.0.0. Only send it when the device
-	// supports it and the user supplied a value, otherwise older devices
-	// would reject the unknown field.
-	if platformVersionAtLeast(r.client.PlatformVersion, "v2.0") && !data.MaxNodes.IsNull() && !data.MaxNodes.IsUnknown() {
+	// max-nodes was introduced in F5OS 2.0.0; older devices would reject
+	// the unknown field.
+	if !data.MaxNodes.IsNull() && !data.MaxNodes.IsUnknown() {
+		checkAttributeSupport(ctx, r.client, "f5os_tenant", &resp.Diagnostics, "max_nodes")
 		tenantSubbj.Config.MaxNodes = int(data.MaxNodes.ValueInt64())
 	}
+	resp.Diagnostics.Append(tenantAdvancedConfig(ctx, data, &tenantSubbj)...)
 
 	tenantpatchConfig := new(f5ossdk.F5ReqTenantsPatch)
 	tenantpatchConfig.F5TenantsTenants.Tenant = append(tenantpatchConfig.F5TenantsTenants.Tenant, tenantSubbj)
@@ -574,6 +965,89 @@ func (r *TenantResource) getTenantUpdateConfig(ctx context.Context, req resource
 	return tenantpatchConfig
 }
 
+// tenantAdvancedConfig sets the placement and datapath settings of data
+// that are known in tenant. Unset ones are left out, so devices that do
+// not support them never see them; attributeRequirements rejects those
+// that are set at plan time.
+func tenantAdvancedConfig(ctx context.Context, data *TenantResourceModel, tenant *f5ossdk.F5ReqTenant) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.ApplianceMode.IsNull() && !data.ApplianceMode.IsUnknown() {
+		tenant.Config.ApplianceMode.Enabled = data.ApplianceMode.ValueBoolPointer()
+	}
+	if !data.TrustMode.IsNull() && !data.TrustMode.IsUnknown() {
+		tenant.Config.TrustMode = data.TrustMode.ValueBoolPointer()
+	}
+	if !data.VirtualWires.IsNull() && !data.VirtualWires.IsUnknown() {
+		diags.Append(data.VirtualWires.ElementsAs(ctx, &tenant.Config.F5TenantVwireVirtualWires, false)...)
+		sort.Strings(tenant.Config.F5TenantVwireVirtualWires)
+	}
+	if !data.MacNdiSet.IsNull() && !data.MacNdiSet.IsUnknown() {
+		var entries []TenantMacNdiModel
+		diags.Append(data.MacNdiSet.ElementsAs(ctx, &entries, false)...)
+		for _, entry := range entries {
+			tenant.Config.MacNdiSet = append(tenant.Config.MacNdiSet, f5ossdk.F5TenantMacNdi{Ndi: entry.Ndi.ValueString(), Mac: entry.Mac.ValueString()})
+		}
+	}
+	tenant.Config.ReservedCpus = data.ReservedCpus.ValueString()
+	tenant.Config.Storage.Location = data.StorageLocation.ValueString()
+	tenant.Config.HaState = data.HaState.ValueString()
+	tenant.Config.FloatingAddress = data.FloatingAddress.ValueString()
+	return diags
+}
+
+// tenantAdvancedConfigToState reads the placement and datapath settings
+// of the tenant configuration in respData into data. Settings the device
+// does not report, as on releases without them, are null, except that an
+// empty virtual_wires or mac_ndi_set in data stays empty.
+func (r *TenantResource) tenantAdvancedConfigToState(ctx context.Context, respData *f5ossdk.F5RespTenants, data *TenantResourceModel) {
+	config := respData.F5TenantsTenant[0].Config
+	data.ApplianceMode = types.BoolPointerValue(config.ApplianceMode.Enabled)
+	data.TrustMode = types.BoolPointerValue(config.TrustMode)
+	if !emptySet(data.VirtualWires) {
+		data.VirtualWires = types.SetNull(types.StringType)
+	}
+	if len(config.F5TenantVwireVirtualWires) > 0 {
+		wires, diags := types.SetValueFrom(ctx, types.StringType, config.F5TenantVwireVirtualWires)
+		if diags.HasError() {
+			tflog.Warn(ctx, "failed to convert virtual wires to set", map[string]interface{}{"virtual_wires": config.F5TenantVwireVirtualWires})
+		}
+		data.VirtualWires = wires
+	}
+	if !emptySet(data.MacNdiSet) {
+		data.MacNdiSet = types.SetNull(types.ObjectType{AttrTypes: tenantMacNdiAttrTypes()})
+	}
+	if len(config.MacNdiSet) > 0 {
+		entries := make([]TenantMacNdiModel, len(config.MacNdiSet))
+		for i, entry := range config.MacNdiSet {
+			entries[i] = TenantMacNdiModel{Ndi: types.StringValue(entry.Ndi), Mac: types.StringValue(entry.Mac)}
+		}
+		macs, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: tenantMacNdiAttrTypes()}, entries)
+		if diags.HasError() {
+			tflog.Warn(ctx, "failed to convert mac-ndi-set to set", map[string]interface{}{"mac_ndi_set": config.MacNdiSet})
+		}
+		data.MacNdiSet = macs
+	}
+	data.ReservedCpus = tenantStringValue(config.ReservedCpus)
+	data.StorageLocation = tenantStringValue(config.Storage.Location)
+	data.HaState = tenantStringValue(config.HaState)
+	data.FloatingAddress = tenantStringValue(config.FloatingAddress)
+}
+
+// emptySet reports whether set is known and has no elements, as for a
+// setting configured as [] that the device reports by leaving it out.
+func emptySet(set types.Set) bool {
+	return !set.IsNull() && !set.IsUnknown() && len(set.Elements()) == 0
+}
+
+// tenantStringValue returns value as a types.String, null when the device
+// did not report it.
+func tenantStringValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
 // Helper for platform type check
 func isRSeriesPlatform(platform string) bool {
 	return platform == "r2800" || platform == "r2000" || platform == "r4000" || platform == "r4800"
@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strconv"
@@ -11,10 +14,16 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/stretchr/testify/assert"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
 )
 
 func TestAccTenantDeployResource(t *testing.T) {
@@ -41,7 +50,7 @@ func TestAccTenantDeployResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -172,7 +181,7 @@ func TestUnitTenantDeployResourceUnitTC1(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",           // not returned by API
+					"timeouts",          // not returned by API
 					"virtual_disk_size", // state vs config size mismatch
 				},
 			},
@@ -268,7 +277,7 @@ func TestUnitTenantTypePopulatedOnImport(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 				ImportStateCheck: func(states []*terraform.InstanceState) error {
@@ -552,8 +561,8 @@ func TestUnitTenantDeployResourceUnitTC3(t *testing.T) {
 // 2.0.0 state response shape where status is "Pending" but the response no
 // longer includes state.instances. tenantWait must not panic on the absent
 // (nil) instances field and instead treats the deployment as still in
-// progress. With a zero timeout the Create loop surfaces the generic timeout
-// error immediately, without dereferencing the missing instances map.
+// progress. With a one-second timeout the Create loop surfaces the generic
+// timeout error quickly, without dereferencing the missing instances map.
 func TestUnitTenantDeployResourcePendingNoInstances2_0_0(t *testing.T) {
 	testAccPreUnitCheck(t)
 	mux.HandleFunc("/restconf/data/openconfig-system:system/aaa", func(w http.ResponseWriter, r *http.Request) {
@@ -736,8 +745,8 @@ func testAccPreCheckTenant2_0_0(t *testing.T) {
 	if err != nil {
 		t.Fatalf("testAccPreCheckTenant2_0_0: failed to create session: %s", err)
 	}
-	if !platformVersionAtLeast(client.PlatformVersion, "v2.0") {
-		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.PlatformVersion)
+	if !platformVersionAtLeast(client.Version(), "v2.0") {
+		t.Skipf("skipping: test requires F5OS 2.0.0+ but device reports %q", client.Version())
 	}
 	// Ensure the tenant image is present (the test creates a tenant that
 	// references it). Done after the version gate so we don't import on
@@ -812,7 +821,7 @@ func TestAccTenantMaxNodes2_0_0(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -863,7 +872,7 @@ func TestAccTenantDeployResourceTypeField(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",           // not returned by API
+					"timeouts",          // not returned by API
 					"virtual_disk_size", // state vs config size may differ
 				},
 			},
@@ -961,7 +970,7 @@ func TestAccTenantVlansPopulatedInState(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -1003,7 +1012,7 @@ func TestAccTenantNoVlansInState(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -1060,7 +1069,7 @@ func TestAccTenantDeploymentFileAbsentForBigIP(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -1297,10 +1306,10 @@ resource "f5os_tenant" "test-tenant22" {
 `, tenantUnitTestImage)
 }
 
-// testAccTenantDeployResourcePendingNoInstancesConfig uses a zero timeout so
-// the CreateTenant poll loop hits the timeout branch on the first iteration
-// (before any long production sleep), letting the 2.0.0 pending-without-
-// instances path be verified without waiting on real timers.
+// testAccTenantDeployResourcePendingNoInstancesConfig uses a one-second
+// create timeout so the CreateTenant poll loop hits the timeout branch
+// within a second of short unit-test polls, letting the 2.0.0 pending-
+// without-instances path be verified without waiting on real timers.
 func testAccTenantDeployResourcePendingNoInstancesConfig() string {
 	return fmt.Sprintf(`
 resource "f5os_tenant" "test-tenant22" {
@@ -1314,7 +1323,9 @@ resource "f5os_tenant" "test-tenant22" {
   cpu_cores         = 8
   running_state     = "configured"
   virtual_disk_size = 82
-  timeout           = 0
+  timeouts {
+    create = "1s"
+  }
 }
 `, tenantUnitTestImage)
 }
@@ -1942,7 +1953,8 @@ resource "f5os_tenant" "test2" {
 // setupTenant2_0_0MaxNodesMocks registers the shared mock handlers used by the
 // max_nodes / 2.0.0-state tests. The device version reported to the provider is
 // controlled by the caller via setupMockPlatformVersion so both the
-// version-gated (>= v2.0) and pre-2.0 code paths can be exercised. The create
+// supported (>= v2.0) and unsupported (pre-2.0) max_nodes paths can be
+// exercised. The create
 // PATCH/POST body is captured into capturedBody so the test can assert whether
 // the max-nodes field was sent.
 func setupTenant2_0_0MaxNodesMocks(t *testing.T, capturedBody *string, mu *sync.Mutex, statusFixture, configFixture string) {
@@ -2002,7 +2014,7 @@ func TestUnitTenant2_0_0MaxNodesAndState(t *testing.T) {
 	var mu sync.Mutex
 	var capturedBody string
 
-	// Report a 2.0.0 device so the version gate (>= v2.0) is satisfied.
+	// Report a 2.0.0 device, which supports max_nodes.
 	setupMockPlatformVersion(mux, "2.0.0-1")
 	setupTenant2_0_0MaxNodesMocks(t, &capturedBody, &mu,
 		"./fixtures/tenant_get_status_2_0_0_max_nodes.json",
@@ -2036,16 +2048,16 @@ func TestUnitTenant2_0_0MaxNodesAndState(t *testing.T) {
 	})
 }
 
-// TestUnitTenantMaxNodesOmittedPre2_0_0 verifies that on a pre-2.0.0 device the
-// provider does NOT send config.max-nodes in the create payload even when the
-// user supplies a max_nodes value, since the field is unknown to older devices.
-func TestUnitTenantMaxNodesOmittedPre2_0_0(t *testing.T) {
+// TestUnitTenantMaxNodesRejectedPre2_0_0 verifies that on a pre-2.0.0 device
+// a configured max_nodes is reported as an unsupported attribute instead of
+// being silently dropped, and that no create payload reaches the device.
+func TestUnitTenantMaxNodesRejectedPre2_0_0(t *testing.T) {
 	testAccPreUnitCheck(t)
 
 	var mu sync.Mutex
 	var capturedBody string
 
-	// Report a pre-2.0 device so the version gate (>= v2.0) is NOT satisfied.
+	// Report a pre-2.0 device, which does not support max_nodes.
 	setupMockPlatformVersion(mux, "1.8.0-1")
 	setupTenant2_0_0MaxNodesMocks(t, &capturedBody, &mu,
 		"./fixtures/tenant_get_status_2_0_0_max_nodes.json",
@@ -2058,20 +2070,16 @@ func TestUnitTenantMaxNodesOmittedPre2_0_0(t *testing.T) {
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccTenant2_0_0MaxNodes(),
-				Check: resource.ComposeAggregateTestCheckFunc(
-					func(s *terraform.State) error {
-						mu.Lock()
-						defer mu.Unlock()
-						if strings.Contains(capturedBody, "max-nodes") {
-							return fmt.Errorf("expected create payload to omit max-nodes on a pre-2.0.0 device, got: %s", capturedBody)
-						}
-						return nil
-					},
-				),
+				Config:      testAccTenant2_0_0MaxNodes(),
+				ExpectError: regexp.MustCompile(`(?s)Unsupported attribute.*max_nodes`),
 			},
 		},
 	})
+	mu.Lock()
+	defer mu.Unlock()
+	if capturedBody != "" {
+		t.Fatalf("expected no create payload on a pre-2.0.0 device, got: %s", capturedBody)
+	}
 }
 
 func testAccTenant2_0_0MaxNodes() string {
@@ -3067,7 +3075,7 @@ func TestAccTenantMacBlockSize(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -3116,7 +3124,7 @@ func TestAccTenantExplicitMemory(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateVerifyIgnore: []string{
-					"timeout",
+					"timeouts",
 					"virtual_disk_size",
 				},
 			},
@@ -3140,3 +3148,527 @@ resource "f5os_tenant" "mem_test" {
 }
 `, tenantTestImage(), tenantTestDiskSize())
 }
+
+const (
+	tenantUpgradeFrom = "BIGIP-17.1.0-0.0.16.ALL-F5OS.qcow2.zip.bundle"
+	tenantUpgradeTo   = "BIGIP-17.1.1-0.0.4.ALL-F5OS.qcow2.zip.bundle"
+)
+
+// tenantUpgradeTestResource returns a TenantResource connected to an
+// emulated rSeries that runs tenant "upgrade1" deployed on
+// tenantUpgradeFrom, with tenantUpgradeTo also available.
+func tenantUpgradeTestResource(t *testing.T) (*TenantResource, *f5osemu.Server) {
+	t.Helper()
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	if err := server.Load("f5-tenant-images:images", []byte(`{"f5-tenant-images:images":{"image":[`+
+		`{"name":"`+tenantUpgradeFrom+`","status":"verified"},{"name":"`+tenantUpgradeTo+`","status":"verified"}]}}`)); err != nil {
+		t.Fatalf("Load images failed: %v", err)
+	}
+	config := `{"name":"upgrade1","type":"BIG-IP","image":"` + tenantUpgradeFrom + `","mgmt-ip":"192.0.2.10","prefix-length":24,"gateway":"192.0.2.1",` +
+		`"nodes":[1],"vcpu-cores-per-node":4,"memory":12288,"cryptos":"enabled","running-state":"deployed","storage":{"size":76}}`
+	state := `{"name":"upgrade1","type":"BIG-IP","image":"` + tenantUpgradeFrom + `","mgmt-ip":"192.0.2.10","prefix-length":24,"gateway":"192.0.2.1",` +
+		`"nodes":[1],"vcpu-cores-per-node":4,"memory":"12288","cryptos":"enabled","running-state":"deployed","status":"Running","storage":{"size":76},"mac-data":{"mac-pool-size":1}}`
+	if err := server.Load("f5-tenants:tenants", []byte(`{"f5-tenants:tenants":{"tenant":[{"name":"upgrade1","config":`+config+`,"state":`+state+`}]}}`)); err != nil {
+		t.Fatalf("Load tenant failed: %v", err)
+	}
+	return &TenantResource{client: client, teemData: &TeemData{}}, server
+}
+
+// tenantUpgradeTestModel returns the model of tenant "upgrade1" on image.
+func tenantUpgradeTestModel(image string) *TenantResourceModel {
+	return &TenantResourceModel{
+		Id:                  types.StringValue("upgrade1"),
+		Name:                types.StringValue("upgrade1"),
+		ImageName:           types.StringValue(image),
+		RollbackOnUpgrade:   types.BoolNull(),
+		DeploymentFile:      types.StringNull(),
+		Cryptos:             types.StringValue("enabled"),
+		Type:                types.StringValue("BIG-IP"),
+		RunningState:        types.StringValue("deployed"),
+		MgmtIP:              types.StringValue("192.0.2.10"),
+		MgmtGateway:         types.StringValue("192.0.2.1"),
+		MgmtPrefix:          types.Int64Value(24),
+		CpuCores:            types.Int64Value(4),
+		Nodes:               types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(1)}),
+		MaxNodes:            types.Int64Null(),
+		Vlans:               types.ListNull(types.Int64Type),
+		Status:              types.StringValue("Running"),
+		MgmtVlan:            types.Int64Value(0),
+		MgmtVlanAccessible:  types.BoolValue(false),
+		ClusteringAsService: types.BoolValue(false),
+		MacBlockSize:        types.StringValue("one"),
+		DagIpv6prefixLength: types.Int64Value(128),
+		Timeouts:            types.ObjectNull(timeoutsAttrTypes()),
+		VirtualdiskSize:     types.Int64Value(76),
+		Memory:              types.Int64Value(12288),
+		ApplianceMode:       types.BoolNull(),
+		TrustMode:           types.BoolNull(),
+		VirtualWires:        types.SetNull(types.StringType),
+		MacNdiSet:           types.SetNull(types.ObjectType{AttrTypes: tenantMacNdiAttrTypes()}),
+		ReservedCpus:        types.StringNull(),
+		StorageLocation:     types.StringNull(),
+		HaState:             types.StringNull(),
+		FloatingAddress:     types.StringNull(),
+		WaitForReady:        types.ObjectNull(tenantReadyAttrTypes()),
+	}
+}
+
+// tenantUpgradeTestUpdate runs Update from the prior model to the planned
+// one and returns the response and the resulting state.
+func tenantUpgradeTestUpdate(t *testing.T, r *TenantResource, prior, planned *TenantResourceModel) (*fwresource.UpdateResponse, *TenantResourceModel) {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	newState := func(m *TenantResourceModel) tfsdk.State {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if diags := state.Set(ctx, m); diags.HasError() {
+			t.Fatalf("state.Set returned diagnostics: %v", diags)
+		}
+		return state
+	}
+	plan := newState(planned)
+	resp := &fwresource.UpdateResponse{State: plan}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: tfsdk.Plan(plan), State: newState(prior)}, resp)
+	var got TenantResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("failed to read back state: %v", diags)
+	}
+	return resp, &got
+}
+
+// TestUnitTenantUpgradeInPlace verifies that changing image_name upgrades
+// the tenant in place and leaves it deployed on the new image.
+func TestUnitTenantUpgradeInPlace(t *testing.T) {
+	r, server := tenantUpgradeTestResource(t)
+
+	resp, got := tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), tenantUpgradeTestModel(tenantUpgradeTo))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update returned diagnostics: %v", resp.Diagnostics)
+	}
+	if got.ImageName.ValueString() != tenantUpgradeTo || got.RunningState.ValueString() != "deployed" || got.Status.ValueString() != "Running" {
+		t.Fatalf("expected the tenant deployed on %s, got image %s, %s/%s", tenantUpgradeTo, got.ImageName, got.RunningState, got.Status)
+	}
+	var patched, deleted bool
+	for _, req := range server.Requests() {
+		switch {
+		case req == "PATCH /restconf/data/f5-tenants:tenants/tenant=upgrade1/config":
+			patched = true
+		case strings.HasPrefix(req, "DELETE "):
+			deleted = true
+		}
+	}
+	if !patched || deleted {
+		t.Fatalf("expected the tenant to be patched in place and not deleted, got %v", server.Requests())
+	}
+}
+
+// TestUnitTenantUpgradeFailure verifies that a failed upgrade is reported
+// against image_name and the state keeps the image the tenant runs, and
+// that with rollback_on_upgrade_failure the tenant is deployed again on
+// its previous image.
+func TestUnitTenantUpgradeFailure(t *testing.T) {
+	for _, rollback := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rollback=%t", rollback), func(t *testing.T) {
+			r, _ := tenantUpgradeTestResource(t)
+			planned := tenantUpgradeTestModel("missing.bundle")
+			planned.RollbackOnUpgrade = types.BoolValue(rollback)
+
+			resp, got := tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), planned)
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected the upgrade to fail")
+			}
+			d, ok := resp.Diagnostics[0].(interface{ Path() path.Path })
+			if !ok || !d.Path().Equal(path.Root("image_name")) || !strings.Contains(resp.Diagnostics[0].Detail(), "is not present") {
+				t.Fatalf("expected an image_name error naming the cause, got %v", resp.Diagnostics)
+			}
+			if got.ImageName.ValueString() != tenantUpgradeFrom {
+				t.Fatalf("expected the state to keep %s, got %s", tenantUpgradeFrom, got.ImageName)
+			}
+			wantState := "configured"
+			if rollback {
+				wantState = "deployed"
+			}
+			if got.RunningState.ValueString() != wantState {
+				t.Fatalf("expected running_state %s, got %s", wantState, got.RunningState)
+			}
+			if rolledBack := strings.Contains(resp.Diagnostics[0].Detail(), "rolled back"); rolledBack != rollback {
+				t.Fatalf("expected rolled back=%t, got %q", rollback, resp.Diagnostics[0].Detail())
+			}
+		})
+	}
+}
+
+// TestUnitTenantAdvancedSettings verifies that the placement and datapath
+// settings are sent to the device on update and read back into state.
+func TestUnitTenantAdvancedSettings(t *testing.T) {
+	r, _ := tenantUpgradeTestResource(t)
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.ApplianceMode = types.BoolValue(true)
+	planned.TrustMode = types.BoolValue(false)
+	planned.VirtualWires = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("vw1"), types.StringValue("vw2")})
+	planned.ReservedCpus = types.StringValue("0-1")
+	planned.StorageLocation = types.StringValue("sys")
+
+	resp, got := tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), planned)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update returned diagnostics: %v", resp.Diagnostics)
+	}
+	if !got.ApplianceMode.Equal(types.BoolValue(true)) || !got.TrustMode.Equal(types.BoolValue(false)) {
+		t.Fatalf("expected appliance_mode true and trust_mode false, got %s and %s", got.ApplianceMode, got.TrustMode)
+	}
+	if !got.VirtualWires.Equal(planned.VirtualWires) {
+		t.Fatalf("expected virtual_wires %s, got %s", planned.VirtualWires, got.VirtualWires)
+	}
+	if got.ReservedCpus.ValueString() != "0-1" || got.StorageLocation.ValueString() != "sys" {
+		t.Fatalf("expected reserved_cpus 0-1 and storage_location sys, got %s and %s", got.ReservedCpus, got.StorageLocation)
+	}
+	if !got.MacNdiSet.IsNull() || !got.HaState.IsNull() || !got.FloatingAddress.IsNull() {
+		t.Fatalf("expected the BIG-IP Next settings to stay null, got %s, %s, %s", got.MacNdiSet, got.HaState, got.FloatingAddress)
+	}
+}
+
+// TestUnitTenantVirtualWiresRemoved verifies that removing virtual_wires
+// from the configuration keeps the device's wires without a diff, and
+// that setting it to [] detaches the tenant from them.
+func TestUnitTenantVirtualWiresRemoved(t *testing.T) {
+	r, server := tenantUpgradeTestResource(t)
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.VirtualWires = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("vw1")})
+	resp, _ := tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), planned)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update returned diagnostics: %v", resp.Diagnostics)
+	}
+	providerAttrs := map[string]tftypes.Value{
+		"host":     tftypes.NewValue(tftypes.String, server.URL),
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"password": tftypes.NewValue(tftypes.String, "admin"),
+	}
+	var values map[string]tftypes.Value
+	if err := resp.State.Raw.As(&values); err != nil {
+		t.Fatalf("reading the state failed: %v", err)
+	}
+	config := map[string]tftypes.Value{}
+	for name, v := range values {
+		if name != "id" && name != "status" && name != "virtual_wires" {
+			config[name] = v
+		}
+	}
+	attribute := func(v tftypes.Value, name string) tftypes.Value {
+		var values map[string]tftypes.Value
+		if err := v.As(&values); err != nil {
+			t.Fatalf("reading %s failed: %v", name, err)
+		}
+		return values[name]
+	}
+	wires := func() []string {
+		tenant, err := r.client.GetTenant("upgrade1")
+		if err != nil {
+			t.Fatalf("GetTenant failed: %v", err)
+		}
+		return tenant.F5TenantsTenant[0].Config.F5TenantVwireVirtualWires
+	}
+
+	plan, state, diags := testProtoApply(t, providerAttrs, "f5os_tenant", resp.State.Raw, config)
+	if len(diags) > 0 {
+		t.Fatalf("apply without virtual_wires returned diagnostics: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	if prior := attribute(resp.State.Raw, "virtual_wires"); !attribute(plan, "virtual_wires").Equal(prior) || !attribute(state, "virtual_wires").Equal(prior) {
+		t.Fatalf("expected virtual_wires to stay %s, got plan %s and state %s", prior, attribute(plan, "virtual_wires"), attribute(state, "virtual_wires"))
+	}
+	if got := wires(); len(got) != 1 || got[0] != "vw1" {
+		t.Fatalf("expected the device to keep vw1, got %v", got)
+	}
+
+	empty := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{})
+	config["virtual_wires"] = empty
+	_, state, diags = testProtoApply(t, providerAttrs, "f5os_tenant", state, config)
+	if len(diags) > 0 {
+		t.Fatalf("apply with virtual_wires = [] returned diagnostics: %s: %s", diags[0].Summary, diags[0].Detail)
+	}
+	if got := attribute(state, "virtual_wires"); !got.Equal(empty) {
+		t.Fatalf("expected virtual_wires [], got %s", got)
+	}
+	if got := wires(); len(got) != 0 {
+		t.Fatalf("expected the device to have no virtual wires, got %v", got)
+	}
+}
+
+// tenantValidateConfig runs ValidateConfig on m.
+func tenantValidateConfig(t *testing.T, r *TenantResource, m *TenantResourceModel) *fwresource.ValidateConfigResponse {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, m); diags.HasError() {
+		t.Fatalf("state.Set returned diagnostics: %v", diags)
+	}
+	resp := &fwresource.ValidateConfigResponse{}
+	r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+	return resp
+}
+
+// TestUnitTenantNextOnlySettings verifies that mac_ndi_set, ha_state and
+// floating_address are rejected unless type is BIG-IP-Next.
+func TestUnitTenantNextOnlySettings(t *testing.T) {
+	r := &TenantResource{}
+	m := tenantUpgradeTestModel(tenantUpgradeFrom)
+	m.MacNdiSet = types.SetValueMust(types.ObjectType{AttrTypes: tenantMacNdiAttrTypes()}, []attr.Value{
+		types.ObjectValueMust(tenantMacNdiAttrTypes(), map[string]attr.Value{"ndi": types.StringValue("ndi1"), "mac": types.StringValue("00:94:a1:69:59:02")}),
+	})
+	m.HaState = types.StringValue("active")
+
+	resp := tenantValidateConfig(t, r, m)
+	if len(resp.Diagnostics) != 2 {
+		t.Fatalf("expected errors on mac_ndi_set and ha_state, got %v", resp.Diagnostics)
+	}
+	for i, name := range []string{"mac_ndi_set", "ha_state"} {
+		d, ok := resp.Diagnostics[i].(interface{ Path() path.Path })
+		if !ok || !d.Path().Equal(path.Root(name)) {
+			t.Fatalf("expected an error on %s, got %v", name, resp.Diagnostics[i])
+		}
+	}
+
+	m.Type = types.StringValue("BIG-IP-Next")
+	if resp := tenantValidateConfig(t, r, m); resp.Diagnostics.HasError() {
+		t.Fatalf("expected BIG-IP-Next to accept the settings, got %v", resp.Diagnostics)
+	}
+}
+
+// TestUnitTenantAdvancedSettingsSupport verifies that virtual_wires is
+// rejected on devices without the f5-tenant-vwire module, and the F5OS
+// 1.7.0 settings on earlier releases.
+func TestUnitTenantAdvancedSettingsSupport(t *testing.T) {
+	for _, tc := range []struct {
+		opts f5osemu.Options
+		set  func(*TenantResourceModel)
+		attr string
+	}{
+		{f5osemu.Options{Platform: f5osemu.VelosPartition}, func(m *TenantResourceModel) {
+			m.VirtualWires = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("vw1")})
+		}, "virtual_wires"},
+		{f5osemu.Options{Platform: f5osemu.RSeries, Version: "1.6.2-1234"}, func(m *TenantResourceModel) {
+			m.ReservedCpus = types.StringValue("0-1")
+		}, "reserved_cpus"},
+		{f5osemu.Options{Platform: f5osemu.RSeries, Version: "1.6.2-1234"}, func(m *TenantResourceModel) {
+			m.TrustMode = types.BoolValue(true)
+		}, "trust_mode"},
+	} {
+		t.Run(tc.attr, func(t *testing.T) {
+			client, _ := testEmulatorClient(t, tc.opts)
+			r := &TenantResource{client: client}
+
+			m := tenantUpgradeTestModel(tenantUpgradeFrom)
+			if resp := tenantValidateConfig(t, r, m); resp.Diagnostics.HasError() {
+				t.Fatalf("expected no errors without %s, got %v", tc.attr, resp.Diagnostics)
+			}
+			tc.set(m)
+			resp := tenantValidateConfig(t, r, m)
+			if len(resp.Diagnostics) != 1 {
+				t.Fatalf("expected one error on %s, got %v", tc.attr, resp.Diagnostics)
+			}
+			d, ok := resp.Diagnostics[0].(interface{ Path() path.Path })
+			if !ok || !d.Path().Equal(path.Root(tc.attr)) || resp.Diagnostics[0].Summary() != "Unsupported attribute" {
+				t.Fatalf("expected Unsupported attribute on %s, got %v", tc.attr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+// tenantModifyPlan runs ModifyPlan for planned, updating prior when it is
+// not nil, and returns the response.
+func tenantModifyPlan(t *testing.T, r *TenantResource, prior, planned *TenantResourceModel) *fwresource.ModifyPlanResponse {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	newState := func(m *TenantResourceModel) tfsdk.State {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if m != nil {
+			if diags := state.Set(ctx, m); diags.HasError() {
+				t.Fatalf("state.Set returned diagnostics: %v", diags)
+			}
+		}
+		return state
+	}
+	plan := newState(planned)
+	resp := &fwresource.ModifyPlanResponse{Plan: tfsdk.Plan(plan)}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config(plan),
+		Plan:   tfsdk.Plan(plan),
+		State:  newState(prior),
+	}, resp)
+	return resp
+}
+
+// TestUnitTenantCapacityCheck verifies that a tenant that does not fit
+// next to the deployed tenants fails the plan with a per-node breakdown,
+// and that a tenant being resized does not count against itself.
+func TestUnitTenantCapacityCheck(t *testing.T) {
+	r, _ := tenantUpgradeTestResource(t)
+
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.Id = types.StringUnknown()
+	planned.Name = types.StringValue("big1")
+	planned.Memory = types.Int64Unknown()
+	planned.CpuCores = types.Int64Value(24)
+	resp := tenantModifyPlan(t, r, nil, planned)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected a 24 vCPU tenant not to fit next to upgrade1")
+	}
+	d, ok := resp.Diagnostics[0].(interface{ Path() path.Path })
+	if !ok || !d.Path().Equal(path.Root("cpu_cores")) || resp.Diagnostics[0].Summary() != "Insufficient Tenant Capacity" {
+		t.Fatalf("expected an Insufficient Tenant Capacity error on cpu_cores, got %v", resp.Diagnostics)
+	}
+	want := "node 1: 22 of 26 vCPUs, 81408 of 93696 MB memory and 824 of 900 GB disk free (deployed: upgrade1); not enough vCPUs"
+	if !strings.Contains(resp.Diagnostics[0].Detail(), want) {
+		t.Fatalf("expected the breakdown %q, got %q", want, resp.Diagnostics[0].Detail())
+	}
+
+	planned.CpuCores = types.Int64Value(22)
+	if resp := tenantModifyPlan(t, r, nil, planned); resp.Diagnostics.HasError() {
+		t.Fatalf("expected a 22 vCPU tenant to fit, got %v", resp.Diagnostics)
+	}
+
+	planned.RunningState = types.StringValue("configured")
+	planned.CpuCores = types.Int64Value(24)
+	if resp := tenantModifyPlan(t, r, nil, planned); resp.Diagnostics.HasError() {
+		t.Fatalf("expected a configured tenant not to be checked, got %v", resp.Diagnostics)
+	}
+
+	resized := tenantUpgradeTestModel(tenantUpgradeFrom)
+	resized.CpuCores = types.Int64Value(26)
+	if resp := tenantModifyPlan(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), resized); resp.Diagnostics.HasError() {
+		t.Fatalf("expected upgrade1 to be resizable to the whole node, got %v", resp.Diagnostics)
+	}
+	resized.Nodes = types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(2)})
+	resp = tenantModifyPlan(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), resized)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "node 2: does not host tenants") {
+		t.Fatalf("expected node 2 to be reported as not hosting tenants, got %v", resp.Diagnostics)
+	}
+}
+
+// TestUnitTenantCapacityNotChecked verifies that the plan warns when the
+// device does not report its capacity, as a VELOS partition does not.
+func TestUnitTenantCapacityNotChecked(t *testing.T) {
+	client, _ := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.VelosPartition})
+	r := &TenantResource{client: client, teemData: &TeemData{}}
+
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.Id = types.StringUnknown()
+	planned.Memory = types.Int64Unknown()
+	resp := tenantModifyPlan(t, r, nil, planned)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Tenant Capacity Not Checked" {
+		t.Fatalf("expected a Tenant Capacity Not Checked warning, got %v", resp.Diagnostics)
+	}
+}
+
+// tenantReadyTestValue returns a wait_for_ready value probing url, or
+// tcp_port when url is empty, every millisecond.
+func tenantReadyTestValue(url string, port int64, timeout string) types.Object {
+	value := map[string]attr.Value{
+		"url":             types.StringNull(),
+		"tcp_port":        types.Int64Null(),
+		"expected_status": types.Int64Value(http.StatusOK),
+		"interval":        types.StringValue("1ms"),
+		"timeout":         types.StringValue(timeout),
+	}
+	if url != "" {
+		value["url"] = types.StringValue(url)
+	} else {
+		value["tcp_port"] = types.Int64Value(port)
+	}
+	return types.ObjectValueMust(tenantReadyAttrTypes(), value)
+}
+
+// TestUnitTenantWaitForReady verifies that an update does not finish
+// until the url of wait_for_ready returns expected_status, and that a
+// tenant which never does fails the update with the last probe result
+// while its state is still saved.
+func TestUnitTenantWaitForReady(t *testing.T) {
+	r, _ := tenantUpgradeTestResource(t)
+	var mu sync.Mutex
+	probes := 0
+	ready := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if probes++; probes < 3 {
+			http.Error(w, "booting", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ready.Close()
+
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.WaitForReady = tenantReadyTestValue(ready.URL+"/mgmt/tm/sys/ready", 0, "10m")
+	resp, got := tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), planned)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update returned diagnostics: %v", resp.Diagnostics)
+	}
+	if probes != 3 || !got.WaitForReady.Equal(planned.WaitForReady) {
+		t.Fatalf("expected the update to wait for the third probe and keep wait_for_ready, got %d probes and %s", probes, got.WaitForReady)
+	}
+
+	booting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "booting", http.StatusServiceUnavailable)
+	}))
+	defer booting.Close()
+	planned.WaitForReady = tenantReadyTestValue(booting.URL, 0, "50ms")
+	r, _ = tenantUpgradeTestResource(t)
+	resp, got = tenantUpgradeTestUpdate(t, r, tenantUpgradeTestModel(tenantUpgradeFrom), planned)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected the update to fail once wait_for_ready timed out")
+	}
+	d, ok := resp.Diagnostics[0].(interface{ Path() path.Path })
+	if !ok || !d.Path().Equal(path.Root("wait_for_ready")) || resp.Diagnostics[0].Summary() != "Tenant Not Ready" ||
+		!strings.Contains(resp.Diagnostics[0].Detail(), "Last probe: HTTP 503 Service Unavailable, expected 200: booting") {
+		t.Fatalf("expected a Tenant Not Ready error on wait_for_ready with the last probe, got %v", resp.Diagnostics)
+	}
+	if got.Id.ValueString() != "upgrade1" || got.RunningState.ValueString() != "deployed" {
+		t.Fatalf("expected the tenant state to be saved, got %+v", got)
+	}
+}
+
+// TestUnitTenantWaitForReadyTCP verifies the tcp_port probe on mgmt_ip,
+// and that a tenant that is not deployed is not probed.
+func TestUnitTenantWaitForReadyTCP(t *testing.T) {
+	// Each update runs against a new device with upgrade1 on 127.0.0.1.
+	update := func(planned *TenantResourceModel) *fwresource.UpdateResponse {
+		r, server := tenantUpgradeTestResource(t)
+		tenant := `{"name":"upgrade1","type":"BIG-IP","image":"` + tenantUpgradeFrom + `","mgmt-ip":"127.0.0.1","prefix-length":24,"gateway":"192.0.2.1",` +
+			`"nodes":[1],"vcpu-cores-per-node":4,"memory":12288,"cryptos":"enabled","running-state":"deployed","status":"Running","storage":{"size":76},"mac-data":{"mac-pool-size":1}}`
+		if err := server.Load("f5-tenants:tenants", []byte(`{"f5-tenants:tenants":{"tenant":[{"name":"upgrade1","config":`+tenant+`,"state":`+tenant+`}]}}`)); err != nil {
+			t.Fatalf("Load tenant failed: %v", err)
+		}
+		prior := tenantUpgradeTestModel(tenantUpgradeFrom)
+		prior.MgmtIP = types.StringValue("127.0.0.1")
+		resp, _ := tenantUpgradeTestUpdate(t, r, prior, planned)
+		return resp
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	port := int64(listener.Addr().(*net.TCPAddr).Port)
+
+	planned := tenantUpgradeTestModel(tenantUpgradeFrom)
+	planned.MgmtIP = types.StringValue("127.0.0.1")
+	planned.WaitForReady = tenantReadyTestValue("", port, "10m")
+	if resp := update(planned); resp.Diagnostics.HasError() {
+		t.Fatalf("expected the open port to be ready, got %v", resp.Diagnostics)
+	}
+
+	_ = listener.Close()
+	planned.WaitForReady = tenantReadyTestValue("", port, "50ms")
+	if resp := update(planned); !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "connection refused") {
+		t.Fatalf("expected the closed port to time out with the dial error, got %v", resp.Diagnostics)
+	}
+
+	planned.RunningState = types.StringValue("configured")
+	if resp := update(planned); resp.Diagnostics.HasError() {
+		t.Fatalf("expected a configured tenant not to be probed, got %v", resp.Diagnostics)
+	}
+}
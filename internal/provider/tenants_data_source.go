@@ -0,0 +1,313 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+var _ datasource.DataSource = &TenantsDataSource{}
+
+func NewTenantsDataSource() datasource.DataSource {
+	return &TenantsDataSource{}
+}
+
+// TenantsDataSource lists the tenants of an rSeries appliance or VELOS
+// partition with their configuration and operational state.
+type TenantsDataSource struct {
+	client   *f5ossdk.F5os
+	teemData *TeemData
+}
+
+type TenantInstanceInfo struct {
+	Node         types.Int64  `tfsdk:"node"`
+	PodName      types.String `tfsdk:"pod_name"`
+	Phase        types.String `tfsdk:"phase"`
+	Status       types.String `tfsdk:"status"`
+	CreationTime types.String `tfsdk:"creation_time"`
+	ReadyTime    types.String `tfsdk:"ready_time"`
+}
+
+type TenantInfo struct {
+	Name            types.String         `tfsdk:"name"`
+	Type            types.String         `tfsdk:"type"`
+	ImageName       types.String         `tfsdk:"image_name"`
+	Nodes           []int64              `tfsdk:"nodes"`
+	CpuCores        types.Int64          `tfsdk:"cpu_cores"`
+	Memory          types.Int64          `tfsdk:"memory"`
+	VirtualdiskSize types.Int64          `tfsdk:"virtual_disk_size"`
+	Vlans           []int64              `tfsdk:"vlans"`
+	MgmtIP          types.String         `tfsdk:"mgmt_ip"`
+	MgmtPrefix      types.Int64          `tfsdk:"mgmt_prefix"`
+	MgmtGateway     types.String         `tfsdk:"mgmt_gateway"`
+	RunningState    types.String         `tfsdk:"running_state"`
+	Status          types.String         `tfsdk:"status"`
+	PhysicalMemory  types.String         `tfsdk:"physical_memory"`
+	BaseMac         types.String         `tfsdk:"base_mac"`
+	MacPoolSize     types.Int64          `tfsdk:"mac_pool_size"`
+	MacBlock        []string             `tfsdk:"mac_block"`
+	Instances       []TenantInstanceInfo `tfsdk:"instances"`
+}
+
+type TenantsDataSourceModel struct {
+	Id        types.String `tfsdk:"id"`
+	NameRegex types.String `tfsdk:"name_regex"`
+	Tenants   []TenantInfo `tfsdk:"tenants"`
+}
+
+func (d *TenantsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tenants"
+	teemData := &TeemData{}
+	teemData.ProviderName = req.ProviderTypeName
+	teemData.ResourceName = resp.TypeName
+	d.teemData = teemData
+}
+
+func (d *TenantsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "List the tenants of an rSeries appliance or VELOS partition, with their configuration and operational state.\n\n" +
+			"Unlike the `f5os_tenant` resource, this data source reads every tenant, including those managed outside Terraform, for dashboards and `check` blocks.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Unique identifier of this data source",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Regular expression, in Go's RE2 syntax, that tenant names must match to be listed. All tenants are listed when not set.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"tenants": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The tenants, in order of name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Name of the tenant.",
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Tenant type, `BIG-IP` or `BIG-IP-Next`.",
+						},
+						"image_name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Tenant image the tenant is configured with.",
+						},
+						"nodes": schema.ListAttribute{
+							ElementType:         types.Int64Type,
+							Computed:            true,
+							MarkdownDescription: "Nodes the tenant is placed on.",
+						},
+						"cpu_cores": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "vCPUs of the tenant on each node.",
+						},
+						"memory": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Configured memory of the tenant, in MB.",
+						},
+						"virtual_disk_size": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Size of the tenant's virtual disk, in GB.",
+						},
+						"vlans": schema.ListAttribute{
+							ElementType:         types.Int64Type,
+							Computed:            true,
+							MarkdownDescription: "VLAN IDs assigned to the tenant.",
+						},
+						"mgmt_ip": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Management IP address of the tenant.",
+						},
+						"mgmt_prefix": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Prefix length of the management address.",
+						},
+						"mgmt_gateway": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Management gateway of the tenant.",
+						},
+						"running_state": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Running state the device reports for the tenant, or the configured one when it reports none.",
+						},
+						"status": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Tenant status, such as `Running`, `Pending` or `Configured`.",
+						},
+						"physical_memory": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Memory the tenant's instances were given, as reported by the device.",
+						},
+						"base_mac": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "First MAC address of the tenant's MAC block.",
+						},
+						"mac_pool_size": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Number of MAC addresses in the tenant's MAC block.",
+						},
+						"mac_block": schema.ListAttribute{
+							ElementType:         types.StringType,
+							Computed:            true,
+							MarkdownDescription: "MAC addresses of the tenant's MAC block. Reported on F5OS releases before 2.0.0.",
+						},
+						"instances": schema.ListNestedAttribute{
+							Computed:            true,
+							MarkdownDescription: "The tenant's instance on each node. Reported on F5OS releases before 2.0.0.",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"node": schema.Int64Attribute{
+										Computed:            true,
+										MarkdownDescription: "Node the instance runs on.",
+									},
+									"pod_name": schema.StringAttribute{
+										Computed:            true,
+										MarkdownDescription: "Name of the instance's pod.",
+									},
+									"phase": schema.StringAttribute{
+										Computed:            true,
+										MarkdownDescription: "Deployment phase of the instance, e.g. `Running`.",
+									},
+									"status": schema.StringAttribute{
+										Computed:            true,
+										MarkdownDescription: "Status message of the instance.",
+									},
+									"creation_time": schema.StringAttribute{
+										Computed:            true,
+										MarkdownDescription: "When the instance was created, in RFC 3339 format.",
+									},
+									"ready_time": schema.StringAttribute{
+										Computed:            true,
+										MarkdownDescription: "When the instance became ready, in RFC 3339 format. Empty until then.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TenantsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client, resp.Diagnostics = toF5osProvider(req.ProviderData)
+}
+
+func (d *TenantsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TenantsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		if nameRegex, err = regexp.Compile(data.NameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+	if d.client.WithContext(ctx).Platform() == "Velos Controller" {
+		resp.Diagnostics.AddError("Unsupported platform for data source", "`f5os_tenants` data source is supported with Velos Partition level (or) rSeries appliance")
+		return
+	}
+	tenants, err := d.client.WithContext(ctx).GetTenants()
+	if err != nil {
+		addClientErrorDiagnostic(&resp.Diagnostics, nil, err, "Unable to List Tenants", fmt.Sprintf("Failed to read the tenants: %s", err))
+		return
+	}
+
+	data.Tenants = []TenantInfo{}
+	for _, tenant := range tenants.F5TenantsTenant {
+		if nameRegex != nil && !nameRegex.MatchString(tenant.Name) {
+			continue
+		}
+		data.Tenants = append(data.Tenants, convertTenantInfo(tenant))
+	}
+	sort.Slice(data.Tenants, func(i, j int) bool {
+		return data.Tenants[i].Name.ValueString() < data.Tenants[j].Name.ValueString()
+	})
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/tenants", d.client.Host))
+	teemData.ResourceName = "f5os_tenants"
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// convertTenantInfo returns the configuration and operational state of
+// tenant. Leaves the device does not report are empty strings and zeros,
+// and lists empty.
+func convertTenantInfo(tenant f5ossdk.F5RespTenant) TenantInfo {
+	config, state := tenant.Config, tenant.State
+	info := TenantInfo{
+		Name:            types.StringValue(tenant.Name),
+		Type:            types.StringValue(config.Type),
+		ImageName:       types.StringValue(config.Image),
+		Nodes:           []int64{},
+		CpuCores:        types.Int64Value(int64(config.VcpuCoresPerNode)),
+		Memory:          types.Int64Value(int64(config.Memory)),
+		VirtualdiskSize: types.Int64Value(int64(config.Storage.Size)),
+		Vlans:           []int64{},
+		MgmtIP:          types.StringValue(config.MgmtIp),
+		MgmtPrefix:      types.Int64Value(int64(config.PrefixLength)),
+		MgmtGateway:     types.StringValue(config.Gateway),
+		RunningState:    types.StringValue(state.RunningState),
+		Status:          types.StringValue(state.Status),
+		PhysicalMemory:  types.StringValue(state.PhysicalMemory),
+		BaseMac:         types.StringValue(state.MacData.BaseMac),
+		MacPoolSize:     types.Int64Value(int64(state.MacData.MacPoolSize)),
+		MacBlock:        []string{},
+		Instances:       []TenantInstanceInfo{},
+	}
+	if state.RunningState == "" {
+		info.RunningState = types.StringValue(config.RunningState)
+	}
+	for _, node := range config.Nodes {
+		info.Nodes = append(info.Nodes, int64(node))
+	}
+	for _, vlan := range config.Vlans {
+		info.Vlans = append(info.Vlans, int64(vlan))
+	}
+	sort.Slice(info.Vlans, func(i, j int) bool { return info.Vlans[i] < info.Vlans[j] })
+	for _, entry := range state.MacData.F5TenantL2InlineMacBlock {
+		info.MacBlock = append(info.MacBlock, entry.Mac)
+	}
+	for _, instance := range state.Instances.Instance {
+		info.Instances = append(info.Instances, TenantInstanceInfo{
+			Node:         types.Int64Value(int64(instance.Node)),
+			PodName:      types.StringValue(instance.PodName),
+			Phase:        types.StringValue(instance.Phase),
+			Status:       types.StringValue(instance.Status),
+			CreationTime: types.StringValue(formatInstanceTime(instance.CreationTime)),
+			ReadyTime:    types.StringValue(formatInstanceTime(instance.ReadyTime)),
+		})
+	}
+	return info
+}
+
+// formatInstanceTime returns t in RFC 3339 format, or "" when the device
+// did not report it.
+func formatInstanceTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gitswarm.f5net.com/terraform-providers/terraform-provider-f5os/internal/f5osemu"
+)
+
+// tenantsTestData holds tenants web1 (deployed), web2 (configured) and
+// db1.
+const tenantsTestData = `{"f5-tenants:tenants":{"tenant":[` +
+	`{"name":"web2","config":{"name":"web2","type":"BIG-IP","image":"BIGIP-17.1.1-0.0.4.ALL-F5OS.qcow2.zip.bundle","nodes":[1],"vcpu-cores-per-node":2,"memory":7680,"running-state":"configured"},` +
+	`"state":{"name":"web2","running-state":"configured","status":"Configured"}},` +
+	`{"name":"web1","config":{"name":"web1","type":"BIG-IP","image":"BIGIP-17.1.1-0.0.4.ALL-F5OS.qcow2.zip.bundle","nodes":[1],"vcpu-cores-per-node":4,"memory":14848,` +
+	`"vlans":[20,10],"mgmt-ip":"192.0.2.21","prefix-length":24,"gateway":"192.0.2.1","storage":{"size":76},"running-state":"deployed"},` +
+	`"state":{"name":"web1","running-state":"deployed","status":"Running","physical-memory":"14848",` +
+	`"mac-data":{"base-mac":"00:94:a1:8e:d0:00","mac-pool-size":1,"f5-tenant-l2-inline:mac-block":[{"mac":"00:94:a1:8e:d0:00"}]},` +
+	`"instances":{"instance":[{"node":1,"pod-name":"web1-1","instance-id":1,"phase":"Running","creation-time":"2024-05-01T10:00:00Z","ready-time":"2024-05-01T10:03:00Z","status":"Started tenant instance"}]}}},` +
+	`{"name":"db1","config":{"name":"db1","type":"BIG-IP","image":"BIGIP-17.1.1-0.0.4.ALL-F5OS.qcow2.zip.bundle","nodes":[1],"vcpu-cores-per-node":2,"memory":7680,"running-state":"configured"}}]}}`
+
+// tenantsRead runs Read of f5os_tenants with nameRegex against an emulated
+// rSeries with the tenants of tenantsTestData, or none when tenants is
+// false.
+func tenantsRead(t *testing.T, tenants bool, nameRegex types.String) *fwdatasource.ReadResponse {
+	t.Helper()
+	client, server := testEmulatorClient(t, f5osemu.Options{Platform: f5osemu.RSeries})
+	if tenants {
+		if err := server.Load("f5-tenants:tenants", []byte(tenantsTestData)); err != nil {
+			t.Fatalf("Load tenants failed: %v", err)
+		}
+	}
+	return testDataSourceRead(t, &TenantsDataSource{client: client}, &TenantsDataSourceModel{Id: types.StringNull(), NameRegex: nameRegex}, nil)
+}
+
+func TestUnitTenantsDataSourceRead(t *testing.T) {
+	resp := tenantsRead(t, true, types.StringNull())
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	var got TenantsDataSourceModel
+	if diags := resp.State.Get(context.Background(), &got); diags.HasError() {
+		t.Fatalf("failed to read back state: %v", diags)
+	}
+	var names []string
+	for _, tenant := range got.Tenants {
+		names = append(names, tenant.Name.ValueString())
+	}
+	if len(names) != 3 || names[0] != "db1" || names[1] != "web1" || names[2] != "web2" {
+		t.Fatalf("expected db1, web1 and web2 in order, got %v", names)
+	}
+
+	web1 := got.Tenants[1]
+	if web1.CpuCores.ValueInt64() != 4 || web1.Memory.ValueInt64() != 14848 || web1.MgmtIP.ValueString() != "192.0.2.21" ||
+		len(web1.Vlans) != 2 || web1.Vlans[0] != 10 || len(web1.Nodes) != 1 || web1.Nodes[0] != 1 {
+		t.Fatalf("unexpected configuration for web1: %+v", web1)
+	}
+	if web1.RunningState.ValueString() != "deployed" || web1.Status.ValueString() != "Running" || web1.PhysicalMemory.ValueString() != "14848" {
+		t.Fatalf("unexpected state for web1: %+v", web1)
+	}
+	if web1.BaseMac.ValueString() != "00:94:a1:8e:d0:00" || web1.MacPoolSize.ValueInt64() != 1 || len(web1.MacBlock) != 1 {
+		t.Fatalf("unexpected MAC data for web1: %+v", web1)
+	}
+	if len(web1.Instances) != 1 || web1.Instances[0].Phase.ValueString() != "Running" || web1.Instances[0].ReadyTime.ValueString() != "2024-05-01T10:03:00Z" {
+		t.Fatalf("unexpected instances for web1: %+v", web1.Instances)
+	}
+
+	db1 := got.Tenants[0]
+	if db1.RunningState.ValueString() != "configured" || db1.Status.ValueString() != "" || len(db1.Instances) != 0 {
+		t.Fatalf("expected db1 without state to fall back to its configured running state, got %+v", db1)
+	}
+}
+
+func TestUnitTenantsDataSourceNameRegex(t *testing.T) {
+	resp := tenantsRead(t, true, types.StringValue("^web"))
+	var got TenantsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	if len(got.Tenants) != 2 || got.Tenants[0].Name.ValueString() != "web1" || got.Tenants[1].Name.ValueString() != "web2" {
+		t.Fatalf("expected only web1 and web2, got %+v", got.Tenants)
+	}
+
+	resp = tenantsRead(t, true, types.StringValue("web("))
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid Regular Expression" {
+		t.Fatalf("expected an invalid regular expression error, got %v", resp.Diagnostics)
+	}
+}
+
+// TestUnitTenantsDataSourceNoTenants verifies that a device without
+// tenants, which reports the list as a missing keypath, gives an empty
+// list rather than an error.
+func TestUnitTenantsDataSourceNoTenants(t *testing.T) {
+	resp := tenantsRead(t, false, types.StringNull())
+	var got TenantsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read returned diagnostics: %v", resp.Diagnostics)
+	}
+	if len(got.Tenants) != 0 {
+		t.Fatalf("expected no tenants, got %+v", got.Tenants)
+	}
+}
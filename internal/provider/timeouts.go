@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5ossdk "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// timeoutsModel maps the `timeouts` block of resources whose operations
+// wait on the device.
+type timeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+	Read   types.String `tfsdk:"read"`
+}
+
+// timeoutsAttrTypes returns the attr.Type map for the `timeouts` block.
+func timeoutsAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"create": types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+		"read":   types.StringType,
+	}
+}
+
+// timeoutsBlock returns the `timeouts` block. what describes the waits
+// the create and update limits apply to, e.g. "the tenant to be
+// deployed".
+func timeoutsBlock(what string, defaultWait time.Duration) schema.SingleNestedBlock {
+	attribute := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Validators: []validator.String{
+				durationValidator{},
+			},
+		}
+	}
+	return schema.SingleNestedBlock{
+		MarkdownDescription: fmt.Sprintf("Limits on how long operations wait, as durations such as `30m`. An unset limit falls back to the provider's `max_wait`, and then to %s.", defaultWait),
+		Attributes: map[string]schema.Attribute{
+			"create": attribute(fmt.Sprintf("How long to wait for %s on create.", what)),
+			"update": attribute(fmt.Sprintf("How long to wait for %s on update.", what)),
+			"delete": attribute("How long the delete may take."),
+			"read":   attribute("How long a refresh may take. Unset means no limit."),
+		},
+	}
+}
+
+// operationTimeout returns how long op ("create", "update", "delete" or
+// "read") may wait: the limit set for it in the timeouts block, else the
+// provider's max_wait, else def. A read has no limit unless one is set.
+func operationTimeout(ctx context.Context, timeouts types.Object, op string, client *f5ossdk.F5os, def time.Duration) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !timeouts.IsNull() && !timeouts.IsUnknown() {
+		var m timeoutsModel
+		diags.Append(timeouts.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return 0, diags
+		}
+		value := map[string]types.String{"create": m.Create, "update": m.Update, "delete": m.Delete, "read": m.Read}[op]
+		// The value is checked by durationValidator.
+		if d, err := time.ParseDuration(value.ValueString()); err == nil {
+			return d, diags
+		}
+	}
+	if op == "read" {
+		return 0, diags
+	}
+	if client != nil && client.MaxWait > 0 {
+		return client.MaxWait, diags
+	}
+	return def, diags
+}
+
+// withOperationTimeout bounds ctx by the limit operationTimeout returns
+// for op. The context is returned unchanged when there is no limit.
+func withOperationTimeout(ctx context.Context, timeouts types.Object, op string, client *f5ossdk.F5os, def time.Duration) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	d, diags := operationTimeout(ctx, timeouts, op, client, def)
+	if d <= 0 || diags.HasError() {
+		return ctx, func() {}, diags
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, cancel, diags
+}
+
+// timeoutSeconds converts a wait limit to the whole seconds the client's
+// wait functions take.
+func timeoutSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// timeoutsStateUpgrader upgrades state from schema version 0, described by
+// prior, in which the resource took an integer `timeout` in seconds, to the
+// `timeouts` block of current. A timeout other than legacyDefault becomes
+// the limit of each operation in ops, so the resource keeps waiting as long
+// as it did; the default leaves the block unset. Attributes added since
+// version 0 start out null and are filled in by the next refresh.
+func timeoutsStateUpgrader(prior, current schema.Schema, legacyDefault int64, ops ...string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		PriorSchema: &prior,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var values map[string]tftypes.Value
+			if err := req.State.Raw.As(&values); err != nil {
+				resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Reading the prior state failed: %s", err))
+				return
+			}
+			var seconds types.Int64
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeout"), &seconds)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			currentType := current.Type().TerraformType(ctx).(tftypes.Object)
+			upgraded := make(map[string]tftypes.Value, len(currentType.AttributeTypes))
+			for name, t := range currentType.AttributeTypes {
+				if v, ok := values[name]; ok {
+					upgraded[name] = v
+				} else {
+					upgraded[name] = tftypes.NewValue(t, nil)
+				}
+			}
+
+			timeoutsType := currentType.AttributeTypes["timeouts"]
+			if !seconds.IsNull() && !seconds.IsUnknown() && seconds.ValueInt64() != legacyDefault {
+				limit := (time.Duration(seconds.ValueInt64()) * time.Second).String()
+				limits := map[string]tftypes.Value{}
+				for name := range timeoutsAttrTypes() {
+					limits[name] = tftypes.NewValue(tftypes.String, nil)
+				}
+				for _, op := range ops {
+					limits[op] = tftypes.NewValue(tftypes.String, limit)
+				}
+				upgraded["timeouts"] = tftypes.NewValue(timeoutsType, limits)
+			}
+			resp.State.Raw = tftypes.NewValue(currentType, upgraded)
+		},
+	}
+}
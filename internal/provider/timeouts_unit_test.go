@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// TestUnitProviderConfigureTiming verifies that api_timeout, poll_interval
+// and max_wait reach the client and that invalid environment values are
+// reported against the matching attribute.
+func TestUnitProviderConfigureTiming(t *testing.T) {
+	t.Setenv("F5OS_API_TIMEOUT", "")
+	t.Setenv("F5OS_POLL_INTERVAL", "")
+	t.Setenv("F5OS_MAX_WAIT", "20m")
+	backend := newConcurrencyBackend(0)
+	defer backend.Close()
+
+	attrs := map[string]tftypes.Value{
+		"host":          tftypes.NewValue(tftypes.String, backend.URL),
+		"username":      tftypes.NewValue(tftypes.String, "admin"),
+		"password":      tftypes.NewValue(tftypes.String, "admin"),
+		"api_timeout":   tftypes.NewValue(tftypes.String, "3m"),
+		"poll_interval": tftypes.NewValue(tftypes.String, "2s"),
+	}
+	resp := testProviderConfigure(t, attrs)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	if client.ConfigOptions.APICallTimeout != 3*time.Minute || client.PollInterval != 2*time.Second || client.MaxWait != 20*time.Minute {
+		t.Fatalf("unexpected timing: api=%s poll=%s maxWait=%s", client.ConfigOptions.APICallTimeout, client.PollInterval, client.MaxWait)
+	}
+
+	t.Setenv("F5OS_MAX_WAIT", "forever")
+	resp = testProviderConfigure(t, attrs)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid F5OS_MAX_WAIT")
+	}
+	if d := resp.Diagnostics.Errors()[0]; d.Summary() != "Invalid F5OS_MAX_WAIT" {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary(), d.Detail())
+	}
+}
+
+// TestUnitOperationTimeout verifies that a limit in the timeouts block
+// wins over the provider's max_wait, which wins over the resource default,
+// and that reads are unbounded unless a limit is set.
+func TestUnitOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+	timeouts := types.ObjectValueMust(timeoutsAttrTypes(), map[string]attr.Value{
+		"create": types.StringValue("45m"),
+		"update": types.StringNull(),
+		"delete": types.StringNull(),
+		"read":   types.StringNull(),
+	})
+	unset := types.ObjectNull(timeoutsAttrTypes())
+	maxWait := &f5os.F5os{MaxWait: 20 * time.Minute}
+
+	for _, tc := range []struct {
+		name     string
+		timeouts types.Object
+		op       string
+		client   *f5os.F5os
+		want     time.Duration
+	}{
+		{"block", timeouts, "create", maxWait, 45 * time.Minute},
+		{"max_wait", timeouts, "update", maxWait, 20 * time.Minute},
+		{"default", unset, "create", &f5os.F5os{}, 6 * time.Minute},
+		{"unconfigured", unset, "delete", nil, 6 * time.Minute},
+		{"read", unset, "read", maxWait, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, diags := operationTimeout(ctx, tc.timeouts, tc.op, tc.client, 6*time.Minute)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+	if got := timeoutSeconds(1500 * time.Millisecond); got != 2 {
+		t.Fatalf("expected partial seconds to round up, got %d", got)
+	}
+}
+
+// TestUnitTimeoutsStateUpgrade verifies that state written with the
+// integer timeout attribute is moved into the timeouts block.
+func TestUnitTimeoutsStateUpgrade(t *testing.T) {
+	ctx := context.Background()
+	r := &TenantResource{}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	upgrader := r.UpgradeState(ctx)[0]
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for _, tc := range []struct {
+		timeout int64
+		want    types.String
+	}{
+		{600, types.StringValue("10m0s")},
+		{360, types.StringNull()},
+	} {
+		prior := attributeSupportValue(priorType, map[string]interface{}{
+			"name":    tftypes.NewValue(tftypes.String, "tenant1"),
+			"timeout": tftypes.NewValue(tftypes.Number, tc.timeout),
+		})
+		resp := &fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+		upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{
+			State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: prior},
+		}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		var name, create, update, del types.String
+		resp.State.GetAttribute(ctx, path.Root("name"), &name)
+		resp.State.GetAttribute(ctx, path.Root("timeouts").AtName("create"), &create)
+		resp.State.GetAttribute(ctx, path.Root("timeouts").AtName("update"), &update)
+		resp.State.GetAttribute(ctx, path.Root("timeouts").AtName("delete"), &del)
+		if name.ValueString() != "tenant1" || !create.Equal(tc.want) || !update.Equal(tc.want) || !del.IsNull() {
+			t.Fatalf("timeout %d: unexpected state name=%s create=%s update=%s delete=%s", tc.timeout, name, create, update, del)
+		}
+	}
+}
+
+// TestUnitTimeoutsStateUpgradeV0 verifies that state as provider releases
+// before 1.14.0 wrote it is read with the version 0 schema and upgraded to
+// the current one, with attributes added since left null.
+func TestUnitTimeoutsStateUpgradeV0(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range map[string]struct {
+		resource fwresource.ResourceWithUpgradeState
+		state    string
+		added    string
+	}{
+		"tenant": {&TenantResource{}, `{"name":"tenant1","image_name":"BIGIP-17.1.0.3-0.0.4.ALL-F5OS.qcow2.zip.bundle","deployment_file":null,"type":"BIG-IP",` +
+			`"mac_block_size":"one","dag_ipv6_prefix_length":128,"cpu_cores":8,"running_state":"deployed","mgmt_ip":"10.1.1.10","mgmt_gateway":"10.1.1.1",` +
+			`"mgmt_prefix":24,"cryptos":"enabled","nodes":[1],"max_nodes":null,"vlans":[10,20],"timeout":600,"virtual_disk_size":82,"memory":29184,` +
+			`"status":"Running","mgmt_vlan":null,"mgmt_vlan_accessible":null,"clustering_as_service":null,"id":"tenant1"}`, "appliance_mode"},
+		"partition": {&PartitionResource{}, `{"name":"p1","ipv4_mgmt_address":"10.1.1.20/24","ipv4_mgmt_gateway":"10.1.1.1","ipv6_mgmt_address":null,` +
+			`"ipv6_mgmt_gateway":null,"os_version":"1.6.0-5442","slots":[1],"enabled":true,"configuration_volume_size":10,"images_volume_size":15,` +
+			`"shared_volume_size":10,"timeout":600,"id":"p1"}`, ""},
+		"tenant image": {&TenantImageResource{}, `{"image_name":"BIGIP-17.1.0.3-0.0.4.ALL-F5OS.qcow2.zip.bundle","local_path":"images","upload_from_path":null,` +
+			`"protocol":"https","remote_host":"files.example.net","remote_user":null,"remote_password":null,"remote_path":"/images","remote_port":null,` +
+			`"insecure":false,"timeout":600,"id":"BIGIP-17.1.0.3-0.0.4.ALL-F5OS.qcow2.zip.bundle","status":"replicated"}`, ""},
+		"config backup": {&CfgBackupResource{}, `{"name":"backup1","remote_host":"files.example.net","remote_user":"backup","remote_password":"secret",` +
+			`"remote_path":"/backups","protocol":"https","timeout":600,"id":"backup1"}`, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			schemaResp := &fwresource.SchemaResponse{}
+			tc.resource.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			upgrader := tc.resource.UpgradeState(ctx)[0]
+			raw := tfprotov6.RawState{JSON: []byte(tc.state)}
+			prior, err := raw.Unmarshal(upgrader.PriorSchema.Type().TerraformType(ctx))
+			if err != nil {
+				t.Fatalf("the version 0 schema does not read the state: %v", err)
+			}
+			resp := &fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+			upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{
+				State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: prior},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			var id, create types.String
+			resp.State.GetAttribute(ctx, path.Root("id"), &id)
+			resp.State.GetAttribute(ctx, path.Root("timeouts").AtName("create"), &create)
+			if id.IsNull() || create.ValueString() != "10m0s" {
+				t.Fatalf("unexpected state id=%s create=%s", id, create)
+			}
+			if tc.added != "" {
+				var added types.Bool
+				resp.State.GetAttribute(ctx, path.Root(tc.added), &added)
+				if !added.IsNull() {
+					t.Fatalf("expected %s to be null, got %s", tc.added, added)
+				}
+			}
+		})
+	}
+}
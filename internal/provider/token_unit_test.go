@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// tokenBackend is a mock F5OS device that accepts a single X-Auth-Token
+// and records every request it sees, so tests can assert that no
+// basic-auth login was attempted.
+type tokenBackend struct {
+	*httptest.Server
+	mu        sync.Mutex
+	valid     string
+	basicAuth int
+	requests  []string
+}
+
+func newTokenBackend(valid string) *tokenBackend {
+	b := &tokenBackend{valid: valid}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+		if _, _, ok := r.BasicAuth(); ok {
+			b.basicAuth++
+		}
+		valid := b.valid
+		b.mu.Unlock()
+		if r.Header.Get("X-Auth-Token") != valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ietf-restconf:errors":{"error":[{"error-type":"protocol","error-tag":"access-denied"}]}}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"openconfig-system:aaa":{}}`))
+	}))
+	return b
+}
+
+// expire invalidates the token the backend accepts.
+func (b *tokenBackend) expire() {
+	b.mu.Lock()
+	b.valid = "expired"
+	b.mu.Unlock()
+}
+
+// TestNewSession_TokenSkipsBasicAuth verifies that a caller-supplied token
+// is used for the session without a basic-auth login and that no password
+// is retained.
+func TestNewSession_TokenSkipsBasicAuth(t *testing.T) {
+	backend := newTokenBackend("ci-token")
+	defer backend.Close()
+
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:     backend.URL,
+		Password: "must-not-be-kept",
+		Token:    "ci-token",
+	})
+	if err != nil {
+		t.Fatalf("NewSession with token failed: %v", err)
+	}
+	if session.Token != "ci-token" {
+		t.Fatalf("expected session token 'ci-token', got %q", session.Token)
+	}
+	if session.Password != "" {
+		t.Fatal("expected token session not to retain a password")
+	}
+	if backend.basicAuth != 0 {
+		t.Fatalf("expected no basic-auth requests, got %d", backend.basicAuth)
+	}
+}
+
+// TestNewSession_TokenRejected verifies that a token the device rejects
+// fails immediately with ErrTokenExpired instead of being retried.
+func TestNewSession_TokenRejected(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	backend := newTokenBackend("ci-token")
+	defer backend.Close()
+
+	_, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:  backend.URL,
+		Token: "stale-token",
+	})
+	if !errors.Is(err, f5os.ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+	if len(backend.requests) != 1 {
+		t.Fatalf("expected a single login request, got %v", backend.requests)
+	}
+}
+
+// TestDoRequest_TokenExpiredNoRelogin verifies that a 401 on a token
+// session surfaces ErrTokenExpired rather than attempting a basic-auth
+// login with an empty password.
+func TestDoRequest_TokenExpiredNoRelogin(t *testing.T) {
+	t.Setenv("F5OS_POLL_INTERVAL", "1ms")
+	backend := newTokenBackend("ci-token")
+	defer backend.Close()
+
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:  backend.URL,
+		Token: "ci-token",
+	})
+	if err != nil {
+		t.Fatalf("NewSession with token failed: %v", err)
+	}
+	backend.expire()
+
+	_, err = session.GetRequest("/openconfig-vlan:vlans")
+	if !errors.Is(err, f5os.ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired from GetRequest, got %v", err)
+	}
+	if backend.basicAuth != 0 {
+		t.Fatalf("expected no basic-auth re-login, got %d", backend.basicAuth)
+	}
+}
+
+// TestUnitProviderConfigureAuthToken verifies that auth_token satisfies
+// the credential checks without username/password and that a rejected
+// token is reported against the auth_token attribute.
+func TestUnitProviderConfigureAuthToken(t *testing.T) {
+	t.Setenv("F5OS_USERNAME", "")
+	t.Setenv("F5OS_PASSWORD", "")
+	t.Setenv("F5OS_TOKEN", "")
+	backend := newTokenBackend("ci-token")
+	defer backend.Close()
+
+	resp := testProviderConnect(t, map[string]tftypes.Value{
+		"host":       tftypes.NewValue(tftypes.String, backend.URL),
+		"auth_token": tftypes.NewValue(tftypes.String, "ci-token"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client := resp.ResourceData.(*f5os.F5os)
+	if client.Password != "" {
+		t.Fatal("expected token-configured client not to hold a password")
+	}
+
+	t.Setenv("F5OS_TOKEN", "stale-token")
+	resp = testProviderConnect(t, map[string]tftypes.Value{
+		"host": tftypes.NewValue(tftypes.String, backend.URL),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected a token expired diagnostic")
+	}
+	d := resp.Diagnostics.Errors()[0]
+	if d.Summary() != "F5OS auth token expired" || !strings.Contains(d.Detail(), "F5OS_TOKEN") {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary(), d.Detail())
+	}
+}
@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	f5os "gitswarm.f5net.com/terraform-providers/f5osclient"
+)
+
+// testHAR is the subset of a HAR document the trace tests inspect.
+type testHAR struct {
+	Log struct {
+		Version string `json:"version"`
+		Entries []struct {
+			Request struct {
+				Method   string `json:"method"`
+				URL      string `json:"url"`
+				Headers  []struct{ Name, Value string }
+				PostData *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status  int `json:"status"`
+				Headers []struct{ Name, Value string }
+				Content struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"response"`
+			Timings struct {
+				Send float64 `json:"send"`
+				Wait float64 `json:"wait"`
+			} `json:"timings"`
+			Attempt     int `json:"_attempt"`
+			MaxAttempts int `json:"_maxAttempts"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+func readTestHAR(t *testing.T, file string) (testHAR, string) {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading trace: %v", err)
+	}
+	var har testHAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("trace is not valid JSON: %v", err)
+	}
+	return har, string(data)
+}
+
+// TestHTTPTrace_RecordsRedactedAttempts verifies that every attempt of a
+// retried request is written to the HAR file with its attempt number and
+// timings, and that credentials never reach the file.
+func TestHTTPTrace_RecordsRedactedAttempts(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(n int, w http.ResponseWriter) {
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yang-data+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"openconfig-system:config":{"password":"device-secret","name":"u1"}}`))
+	})
+	defer backend.Close()
+
+	trace := filepath.Join(t.TempDir(), "f5os.har")
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:          backend.URL,
+		User:          "admin",
+		Password:      "admin-secret",
+		HTTPTraceFile: trace,
+		Retry: &f5os.RetryPolicy{
+			MaxAttempts:          3,
+			InitialBackoff:       time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+
+	body := `{"openconfig-system:user":[{"username":"u1","config":{"f5-openconfig-aaa-password-policy:password":"user-secret","role":"admin"}}]}`
+	if _, err := session.PutRequest("/openconfig-system:system/aaa/authentication/users", []byte(body)); err != nil {
+		t.Fatalf("PutRequest failed: %v", err)
+	}
+
+	har, raw := readTestHAR(t, trace)
+	for _, secret := range []string{"admin-secret", "test-token", "user-secret", "device-secret"} {
+		if strings.Contains(raw, secret) {
+			t.Fatalf("trace leaks %q:\n%s", secret, raw)
+		}
+	}
+	if har.Log.Version != "1.2" {
+		t.Fatalf("expected a HAR 1.2 document, got version %q", har.Log.Version)
+	}
+
+	var puts []int
+	var sawAuth, sawToken bool
+	for _, e := range har.Log.Entries {
+		for _, h := range e.Request.Headers {
+			switch http.CanonicalHeaderKey(h.Name) {
+			case "Authorization":
+				sawAuth = true
+			case "X-Auth-Token":
+				sawToken = true
+			default:
+				continue
+			}
+			if h.Value != "REDACTED" {
+				t.Fatalf("expected %s to be redacted, got %q", h.Name, h.Value)
+			}
+		}
+		if e.Request.Method != http.MethodPut {
+			continue
+		}
+		puts = append(puts, e.Attempt)
+		if e.MaxAttempts != 3 {
+			t.Fatalf("expected _maxAttempts 3, got %d", e.MaxAttempts)
+		}
+		if e.Request.PostData == nil || !strings.Contains(e.Request.PostData.Text, `"role":"admin"`) {
+			t.Fatalf("expected the request body to be recorded, got %+v", e.Request.PostData)
+		}
+		if e.Timings.Send < 0 || e.Timings.Wait < 0 {
+			t.Fatalf("expected send and wait timings, got %+v", e.Timings)
+		}
+	}
+	if !sawAuth || !sawToken {
+		t.Fatalf("expected both the login and token headers in the trace (auth=%v token=%v)", sawAuth, sawToken)
+	}
+	if len(puts) != 2 || puts[0] != 1 || puts[1] != 2 {
+		t.Fatalf("expected PUT attempts [1 2], got %v", puts)
+	}
+}
+
+// TestHTTPTrace_LongTrace verifies that the trace stays a complete HAR
+// document as entries are appended, and that large JSON bodies are cut
+// like text bodies.
+func TestHTTPTrace_LongTrace(t *testing.T) {
+	large := `{"f5-tenants:tenants":{"tenant":[` + strings.Repeat(`{"name":"t1","config":{"image":"BIGIP-17.1.0"}},`, 1<<15) + `{"name":"last"}]}}`
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/yang-data+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(large))
+	})
+	defer backend.Close()
+
+	trace := filepath.Join(t.TempDir(), "long.har")
+	session, err := f5os.NewSession(&f5os.F5osConfig{
+		Host:          backend.URL,
+		User:          "admin",
+		Password:      "admin-secret",
+		HTTPTraceFile: trace,
+	})
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	before, _ := readTestHAR(t, trace)
+	for i := 0; i < 5; i++ {
+		if _, err := session.GetRequest("/f5-tenants:tenants"); err != nil {
+			t.Fatalf("GetRequest failed: %v", err)
+		}
+	}
+	har, raw := readTestHAR(t, trace)
+	if len(har.Log.Entries) != len(before.Log.Entries)+5 {
+		t.Fatalf("expected %d entries, got %d", len(before.Log.Entries)+5, len(har.Log.Entries))
+	}
+	if strings.Count(raw, "...[truncated]") != 5 {
+		t.Fatalf("expected each %d byte response to be truncated", len(large))
+	}
+	if info, err := os.Stat(trace); err != nil || info.Size() > 8<<20 {
+		t.Fatalf("expected the trace to hold the truncated bodies only, got %v, %v", info, err)
+	}
+}
+
+// TestHTTPTrace_ContinuesExistingTrace verifies that a trace left by an
+// earlier plugin process, such as the one that ran the plan, is continued
+// rather than replaced, and that secret XML leaves are masked.
+func TestHTTPTrace_ContinuesExistingTrace(t *testing.T) {
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/yang-data+xml")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<user xmlns="http://openconfig.net/yang/system"><username>u1</username>` +
+			`<config><aaa:password xmlns:aaa="urn:f5:aaa">device-secret</aaa:password><role>admin</role></config></user>`))
+	})
+	defer backend.Close()
+
+	trace := func(file string) {
+		session, err := f5os.NewSession(&f5os.F5osConfig{
+			Host:          backend.URL,
+			User:          "admin",
+			Password:      "admin-secret",
+			HTTPTraceFile: file,
+		})
+		if err != nil {
+			t.Fatalf("NewSession failed: %v", err)
+		}
+		if _, err := session.GetRequest("/openconfig-system:system/aaa/authentication/users"); err != nil {
+			t.Fatalf("GetRequest failed: %v", err)
+		}
+	}
+	dir := t.TempDir()
+	trace(filepath.Join(dir, "plan.har"))
+	plan, raw := readTestHAR(t, filepath.Join(dir, "plan.har"))
+	got := plan.Log.Entries[len(plan.Log.Entries)-1].Response.Content.Text
+	if strings.Contains(raw, "device-secret") || !strings.Contains(got, `<aaa:password xmlns:aaa="urn:f5:aaa">REDACTED</aaa:password><role>admin</role>`) {
+		t.Fatalf("expected the XML password to be masked and the rest kept, got %s", got)
+	}
+
+	// A path this process has not traced to stands in for the file of the
+	// earlier process.
+	apply := filepath.Join(dir, "apply.har")
+	if err := os.WriteFile(apply, []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	trace(apply)
+	har, _ := readTestHAR(t, apply)
+	if len(har.Log.Entries) != 2*len(plan.Log.Entries) {
+		t.Fatalf("expected the %d entries of each process, got %d", len(plan.Log.Entries), len(har.Log.Entries))
+	}
+	if _, err := os.Stat(apply + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected the lock file to be removed, got %v", err)
+	}
+}
+
+// TestUnitProviderConfigureHTTPTraceFile verifies that http_trace_file
+// reaches the client and that the login is traced.
+func TestUnitProviderConfigureHTTPTraceFile(t *testing.T) {
+	t.Setenv("F5OS_HTTP_TRACE_FILE", "")
+	backend := newRetryBackend(http.StatusOK, func(_ int, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer backend.Close()
+
+	trace := filepath.Join(t.TempDir(), "provider.har")
+	resp := testProviderConnect(t, map[string]tftypes.Value{
+		"host":            tftypes.NewValue(tftypes.String, backend.URL),
+		"username":        tftypes.NewValue(tftypes.String, "admin"),
+		"password":        tftypes.NewValue(tftypes.String, "admin"),
+		"http_trace_file": tftypes.NewValue(tftypes.String, trace),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if client := resp.ResourceData.(*f5os.F5os); client.HTTPTraceFile != trace {
+		t.Fatalf("expected HTTPTraceFile %q, got %q", trace, client.HTTPTraceFile)
+	}
+	if har, _ := readTestHAR(t, trace); len(har.Log.Entries) == 0 {
+		t.Fatal("expected the login to be traced")
+	}
+}
@@ -19,6 +19,10 @@ import (
 var _ resource.Resource = &VlanResource{}
 var _ resource.ResourceWithImportState = &VlanResource{}
 
+// vlanErrorAttributes maps VLAN RESTCONF nodes to the attributes that set
+// them, so device errors are reported against the offending field.
+var vlanErrorAttributes = restconfAttributes(nil, "name", "vlan_id")
+
 func NewVlanResource() resource.Resource {
 	return &VlanResource{}
 }
@@ -81,7 +85,7 @@ func (r *VlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_vlan` resource is supported with Velos Partition level/rSeries appliance.")
 		return
 	}
@@ -92,19 +96,19 @@ func (r *VlanResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	teemInfo := make(map[string]any)
 	teemInfo["teemData"] = r.teemData
-	_ = r.client.SendTeem(teemInfo)
+	_ = r.client.WithContext(ctx).SendTeem(teemInfo)
 	// if err != nil {
 	// 	resp.Diagnostics.AddError("Teem Error", fmt.Sprintf("Sending Teem Data failed: %s", err))
 	// }
-	respByte, err := r.client.VlanConfig(vlanReqConfig)
+	respByte, err := r.client.WithContext(ctx).VlanConfig(vlanReqConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Create Vlan failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, vlanErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Create Vlan failed, got error: %s", err))
 		return
 	}
 	tflog.Debug(ctx, fmt.Sprintf("vlanReqConfig Response:%+v", string(respByte)))
 	data.Id = types.StringValue(fmt.Sprintf("%d", int(data.VlanId.ValueInt64())))
 
-	partData, err := r.client.GetVlan(int(data.VlanId.ValueInt64()))
+	partData, err := r.client.WithContext(ctx).GetVlan(int(data.VlanId.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Vlan, got error: %s", err))
 		return
@@ -133,7 +137,7 @@ func (r *VlanResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("[READ] Vlan :%+v", vlanId))
-	partData, err := r.client.GetVlan(vlanId)
+	partData, err := r.client.WithContext(ctx).GetVlan(vlanId)
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("%v", err), fmt.Sprintf("Unable to Read/Get Vlan ID:%d", vlanId))
 		return
@@ -154,7 +158,7 @@ func (r *VlanResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	if r.client.PlatformType == "Velos Controller" {
+	if r.client.WithContext(ctx).Platform() == "Velos Controller" {
 		resp.Diagnostics.AddError("Client Error", "`f5os_vlan` resource is supported with Velos Partition level.")
 		return
 	}
@@ -162,15 +166,15 @@ func (r *VlanResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	vlanReqConfig := getPartitionVlanConfig(data)
 	tflog.Info(ctx, fmt.Sprintf("vlanReqConfig Data:%+v", vlanReqConfig))
 
-	respByte, err := r.client.VlanConfig(vlanReqConfig)
+	respByte, err := r.client.WithContext(ctx).VlanConfig(vlanReqConfig)
 	if err != nil {
-		resp.Diagnostics.AddError("F5OS Client Error:", fmt.Sprintf("Update Vlan failed, got error: %s", err))
+		addClientErrorDiagnostic(&resp.Diagnostics, vlanErrorAttributes, err, "F5OS Client Error:", fmt.Sprintf("Update Vlan failed, got error: %s", err))
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("vlanReqConfig Response:%+v", string(respByte)))
 
 	data.Id = types.StringValue(fmt.Sprintf("%d", int(data.VlanId.ValueInt64())))
-	partData, err := r.client.GetVlan(int(data.VlanId.ValueInt64()))
+	partData, err := r.client.WithContext(ctx).GetVlan(int(data.VlanId.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("F5OS Client Error", fmt.Sprintf("Unable to Read/Get Vlan, got error: %s", err))
 		return
@@ -191,7 +195,7 @@ func (r *VlanResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.DeleteVlan(int(data.VlanId.ValueInt64()))
+	err := r.client.WithContext(ctx).DeleteVlan(int(data.VlanId.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to Delete Vlan, got error: %s", err))
 		return
@@ -43,6 +43,10 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Serve returns once Terraform stops the plugin; log out of the
+	// device sessions opened during the run before exiting.
+	provider.CloseSessions()
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}